@@ -1,21 +1,198 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
+	stdhttp "net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"cms/server/internal/config"
 	"cms/server/internal/db"
+	"cms/server/internal/mediaproc"
+	"cms/server/internal/metrics"
+	"cms/server/internal/migrate"
+	"cms/server/internal/repository"
+	"cms/server/internal/telemetry"
 	"cms/server/internal/transport/http"
+	"cms/server/internal/webhook"
+	"cms/server/pkg/storage"
+
+	"gorm.io/gorm"
 )
 
 func main() {
+	if err := run(); err != nil {
+		slog.Error("server gagal", "error", err)
+		os.Exit(1)
+	}
+}
+
+// run menjalankan API sampai SIGINT/SIGTERM atau listener API gagal, lalu
+// menunggu request dan worker selesai. Semua defer (termasuk flush span)
+// dijalankan sebelum proses keluar.
+func run() error {
 	cfg := config.Load()
+	shutdown, err := telemetry.Setup(context.Background(), telemetry.Config{
+		ServiceName:    cfg.ServiceName,
+		LogFormat:      cfg.LogFormat,
+		LogLevel:       cfg.LogLevel,
+		TracesExporter: cfg.TracesExporter,
+	})
+	if err != nil {
+		log.Fatalf("telemetry: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = shutdown(ctx)
+	}()
+
 	dbConn := db.MustOpen(cfg)
+	if err := migrateSchema(cfg, dbConn); err != nil {
+		return err
+	}
+
+	// ctx dibatalkan oleh SIGINT/SIGTERM atau listener API yang gagal:
+	// /readyz menjadi 503, worker berhenti mengambil pekerjaan baru dan
+	// stream SSE ditutup
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, fail := context.WithCancelCause(ctx)
+	defer fail(nil)
+
+	var workers sync.WaitGroup
+	run := func(fn func(context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			fn(ctx)
+		}()
+	}
+
+	// kirim event webhook dari outbox di background
+	run(webhook.NewWorker(repository.NewWebhookRepository(dbConn)).Run)
+
+	// Storage media dipakai bersama router dan worker. Storage yang mati
+	// tidak menghentikan API: endpoint media mengembalikan error dan /readyz
+	// 503 sampai storage bisa dihubungi lagi
+	store, err := storage.New(storageConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("STORAGE_DRIVER: %w", err)
+	}
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	if err := store.Ping(pingCtx); err != nil {
+		slog.Warn("storage belum siap", "driver", cfg.StorageDriver, "error", err)
+	}
+	cancel()
+
+	// metadata & poster untuk video, audio dan PDF yang baru diupload
+	run(mediaproc.NewWorker(
+		repository.NewMediaJobRepository(dbConn),
+		// metadata & poster yang selesai diproses dikirim sebagai media.updated
+		repository.NewMediaRepository(dbConn, repository.NewEventPublisher(dbConn)),
+		store,
+	).Run)
+
+	r, imports := http.NewRouter(ctx, cfg, dbConn, store)
+	api := http.NewServer(cfg, r)
+	servers := []*stdhttp.Server{api}
+	go func() {
+		slog.Info("server listening", "addr", api.Addr)
+		if err := api.ListenAndServe(); err != nil && !errors.Is(err, stdhttp.ErrServerClosed) {
+			fail(err)
+		}
+	}()
+	if cfg.MetricsAddr != "" {
+		// metrics yang gagal listen tidak menghentikan API
+		srv := metrics.NewServer(cfg.MetricsAddr, cfg.MetricsToken)
+		servers = append(servers, srv)
+		go func() {
+			slog.Info("metrics listening", "addr", srv.Addr)
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, stdhttp.ErrServerClosed) {
+				slog.Error("metrics server stopped", "addr", srv.Addr, "error", err)
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	// sinyal kedua menghentikan proses tanpa menunggu
+	stop()
+	failure := context.Cause(ctx)
+	if errors.Is(failure, context.Canceled) {
+		failure = nil
+		slog.Info("shutting down", "delay", cfg.ShutdownDelay, "timeout", cfg.ShutdownTimeout)
+		time.Sleep(cfg.ShutdownDelay)
+	}
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	for _, srv := range servers {
+		if err := srv.Shutdown(drainCtx); err != nil {
+			slog.Error("server shutdown", "addr", srv.Addr, "error", err)
+		}
+	}
+	if err := imports(drainCtx); err != nil {
+		slog.Error("import CSV belum selesai", "error", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		slog.Info("server stopped")
+	case <-drainCtx.Done():
+		slog.Error("worker belum selesai", "error", drainCtx.Err())
+	}
+	return failure
+}
+
+// migrateSchema menerapkan migrasi tertunda bila MIGRATE_ON_BOOT=true, lalu
+// menolak start selama skema database belum sampai versi terbaru.
+func migrateSchema(cfg config.Config, dbConn *gorm.DB) error {
+	sqlDB, err := dbConn.DB()
+	if err != nil {
+		return err
+	}
+	m, err := migrate.New(sqlDB)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if cfg.MigrateOnBoot {
+		applied, err := m.Up(ctx, 0)
+		for _, mig := range applied {
+			slog.Info("migrasi diterapkan", "version", mig.Version, "name", mig.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("migrate: %w", err)
+		}
+	}
+	return m.Check(ctx)
+}
 
-	r := http.NewRouter(cfg, dbConn)
-	addr := ":" + cfg.AppPort
-	log.Printf("server listening on %s", addr)
-	if err := r.Run(addr); err != nil {
-		log.Fatal(err)
+// storageConfig membangun konfigurasi storage media dari env. URL unduhan
+// driver local/memory ditandatangani dengan JWT_SECRET.
+func storageConfig(cfg config.Config) storage.Config {
+	return storage.Config{
+		Driver:     cfg.StorageDriver,
+		Endpoint:   cfg.MinIOEndpoint,
+		AccessKey:  cfg.MinIOAccessKey,
+		SecretKey:  cfg.MinIOSecretKey,
+		Bucket:     cfg.MinIOBucket,
+		Region:     cfg.MinIORegion,
+		UseSSL:     cfg.MinIOUseSSL,
+		LocalDir:   cfg.StorageLocalDir,
+		PublicURL:  cfg.StoragePublicURL,
+		SigningKey: cfg.JWTSecret,
+		Observe:    metrics.ObserveStorage,
 	}
 }
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"go/token"
+	"os"
+	"slices"
+	"strings"
+
+	"cms/server/internal/codegen"
+	"cms/server/internal/config"
+	"cms/server/internal/db"
+	"cms/server/internal/model"
+	"cms/server/internal/repository"
+	"cms/server/internal/transfer"
+)
+
+func runCodegen(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("codegen", flag.ExitOnError)
+	lang := fs.String("lang", "go", "output language: go or ts")
+	out := fs.String("o", "", "output file (default stdout)")
+	pkg := fs.String("package", "content", "package name for go output")
+	from := fs.String("from", "", "read content types from an export archive instead of the database")
+	types := fs.String("types", "", "comma separated content type slugs (default all)")
+	fs.Parse(args)
+
+	if *lang != "go" && *lang != "ts" {
+		return fmt.Errorf("unknown language %q", *lang)
+	}
+	if *lang == "go" && !token.IsIdentifier(*pkg) {
+		return fmt.Errorf("invalid package name %q", *pkg)
+	}
+
+	list, err := loadContentTypes(ctx, *from)
+	if err != nil {
+		return err
+	}
+	if *types != "" {
+		want := strings.Split(*types, ",")
+		list = slices.DeleteFunc(list, func(ct model.ContentType) bool {
+			return !slices.Contains(want, ct.Slug)
+		})
+		if len(list) != len(want) {
+			return errors.New("some content types in -types were not found")
+		}
+	}
+
+	var src []byte
+	if *lang == "go" {
+		if src, err = codegen.Go(list, *pkg); err != nil {
+			return err
+		}
+	} else {
+		src = codegen.TypeScript(list)
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*out, src, 0o644)
+}
+
+func loadContentTypes(ctx context.Context, archive string) ([]model.ContentType, error) {
+	if archive == "" {
+		cfg := config.Load()
+		return repository.NewContentTypeRepository(db.MustOpen(cfg), nil).List(ctx)
+	}
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return transfer.ReadContentTypes(f, st.Size())
+}
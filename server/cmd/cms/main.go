@@ -0,0 +1,58 @@
+// Command cms berisi perintah administrasi CMS yang dijalankan dari terminal.
+//
+//	cms export -o backup.zip [-types post,page] [-versions] [-media]
+//	cms import [-strategy skip|overwrite|rename] [-dry-run] backup.zip
+//	cms media-reconcile [-delete] [-grace 1h]
+//	cms media-verify [-backfill]
+//	cms codegen [-lang go|ts] [-o file] [-package content] [-from backup.zip] [-types post,page]
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, args []string) error
+}
+
+var commands = []command{
+	{"export", "export content types & entries to a zip archive", runExport},
+	{"import", "import a zip archive produced by export", runImport},
+	{"media-reconcile", "report (or delete) orphaned media objects and missing files", runMediaReconcile},
+	{"media-verify", "verify stored media objects against their SHA-256 checksums", runMediaVerify},
+	{"codegen", "generate Go or TypeScript types for content type data", runCodegen},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, cmd := range commands {
+		if cmd.name == os.Args[1] {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			err := cmd.run(ctx, os.Args[2:])
+			stop()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "cms %s: %v\n", cmd.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: cms <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", cmd.name, cmd.usage)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+
+	"cms/server/internal/config"
+	"cms/server/internal/db"
+	"cms/server/internal/mediacheck"
+)
+
+func newMediaCheck(ctx context.Context) (*mediacheck.Service, error) {
+	cfg := config.Load()
+	store, err := openStorage(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return mediacheck.New(db.MustOpen(cfg), store), nil
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func runMediaReconcile(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("media-reconcile", flag.ExitOnError)
+	del := fs.Bool("delete", false, "delete orphaned objects instead of only reporting them")
+	grace := fs.Duration("grace", mediacheck.DefaultGrace, "ignore objects younger than this")
+	fs.Parse(args)
+
+	svc, err := newMediaCheck(ctx)
+	if err != nil {
+		return err
+	}
+	report, err := svc.Reconcile(ctx, mediacheck.Options{Delete: *del, Grace: *grace})
+	if report != nil {
+		if err := printJSON(report); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func runMediaVerify(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("media-verify", flag.ExitOnError)
+	backfill := fs.Bool("backfill", false, "compute and store checksums for media that have none")
+	fs.Parse(args)
+
+	svc, err := newMediaCheck(ctx)
+	if err != nil {
+		return err
+	}
+	report, err := svc.Verify(ctx, mediacheck.VerifyOptions{Backfill: *backfill})
+	if report != nil {
+		if err := printJSON(report); err != nil {
+			return err
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if report.Failed() {
+		return errors.New("some media objects are missing or corrupted")
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"cms/server/internal/config"
+	"cms/server/internal/db"
+	"cms/server/internal/transfer"
+	"cms/server/pkg/storage"
+)
+
+func newTransferService(ctx context.Context, withMedia bool) (*transfer.Service, error) {
+	cfg := config.Load()
+	dbConn := db.MustOpen(cfg)
+	if !withMedia {
+		return transfer.New(dbConn, nil), nil
+	}
+	store, err := openStorage(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return transfer.New(dbConn, store), nil
+}
+
+// openStorage membuka storage media dan memastikan bisa dipakai. URL
+// unduhan driver local/memory ditandatangani dengan JWT_SECRET, sama
+// seperti di cmd/api.
+func openStorage(ctx context.Context, cfg config.Config) (storage.Storage, error) {
+	store, err := storage.New(storage.Config{
+		Driver:     cfg.StorageDriver,
+		Endpoint:   cfg.MinIOEndpoint,
+		AccessKey:  cfg.MinIOAccessKey,
+		SecretKey:  cfg.MinIOSecretKey,
+		Bucket:     cfg.MinIOBucket,
+		Region:     cfg.MinIORegion,
+		UseSSL:     cfg.MinIOUseSSL,
+		LocalDir:   cfg.StorageLocalDir,
+		PublicURL:  cfg.StoragePublicURL,
+		SigningKey: cfg.JWTSecret,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := store.Ping(ctx); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func runExport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	out := fs.String("o", "", "output file (default stdout)")
+	types := fs.String("types", "", "comma separated content type slugs (default all)")
+	versions := fs.Bool("versions", false, "include entry versions")
+	media := fs.Bool("media", false, "include referenced media files")
+	fs.Parse(args)
+
+	opts := transfer.ExportOptions{Versions: *versions, Media: *media}
+	if *types != "" {
+		opts.Types = strings.Split(*types, ",")
+	}
+
+	svc, err := newTransferService(ctx, *media)
+	if err != nil {
+		return err
+	}
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return svc.Export(ctx, w, opts)
+}
+
+func runImport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	strategy := fs.String("strategy", transfer.StrategySkip, "conflict strategy: skip, overwrite or rename")
+	dryRun := fs.Bool("dry-run", false, "only report what would change")
+	verbose := fs.Bool("v", false, "print every imported item")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("archive path is required")
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	svc, err := newTransferService(ctx, !*dryRun)
+	if err != nil {
+		return err
+	}
+	report, err := svc.Import(ctx, f, st.Size(), transfer.ImportOptions{
+		Strategy: *strategy,
+		DryRun:   *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+	if !*verbose {
+		report.Items = nil
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	if report.ContentTypes.Failed+report.Entries.Failed+report.Media.Failed > 0 {
+		return fmt.Errorf("some items failed to import")
+	}
+	return nil
+}
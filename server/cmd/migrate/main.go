@@ -0,0 +1,118 @@
+// Command migrate menerapkan migrasi SQL yang di-embed (server/migrations)
+// ke database dari konfigurasi DB_*.
+//
+//	migrate up [N]         terapkan N migrasi berikutnya (default semua)
+//	migrate down [N]       batalkan N migrasi terakhir (default 1)
+//	migrate status         tampilkan versi terpasang & migrasi tertunda
+//	migrate force VERSION  tulis versi tanpa menjalankan migrasi (hapus dirty)
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+
+	"cms/server/internal/config"
+	"cms/server/internal/db"
+	"cms/server/internal/migrate"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := run(ctx, os.Args[1], os.Args[2:])
+	stop()
+	if errors.Is(err, errUsage) {
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+var errUsage = errors.New("usage")
+
+func run(ctx context.Context, cmd string, args []string) error {
+	switch cmd {
+	case "up", "down", "status", "force":
+	default:
+		return errUsage
+	}
+	if len(args) > 1 || (cmd == "status" && len(args) > 0) || (cmd == "force" && len(args) != 1) {
+		return errUsage
+	}
+	n := 0
+	if cmd == "down" {
+		n = 1
+	}
+	if len(args) == 1 {
+		// force boleh 0 (belum ada migrasi), up/down minimal 1 langkah
+		v, err := strconv.Atoi(args[0])
+		if err != nil || v < 0 || (v == 0 && cmd != "force") {
+			return fmt.Errorf("invalid number %q", args[0])
+		}
+		n = v
+	}
+
+	gormDB := db.MustOpen(config.Load())
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+	m, err := migrate.New(sqlDB)
+	if err != nil {
+		return err
+	}
+
+	switch cmd {
+	case "up":
+		applied, err := m.Up(ctx, n)
+		for _, mig := range applied {
+			fmt.Printf("applied  %04d_%s\n", mig.Version, mig.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return err
+	case "down":
+		reverted, err := m.Down(ctx, n)
+		for _, mig := range reverted {
+			fmt.Printf("reverted %04d_%s\n", mig.Version, mig.Name)
+		}
+		return err
+	case "force":
+		return m.Force(ctx, uint(n))
+	}
+
+	st, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	dirty := ""
+	if st.Dirty {
+		dirty = " (dirty)"
+	}
+	fmt.Printf("version %d%s, latest %d\n", st.Version, dirty, st.Latest)
+	for _, mig := range st.Pending {
+		fmt.Printf("pending  %04d_%s\n", mig.Version, mig.Name)
+	}
+	return nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate <command> [args]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "  up [N]           apply the next N migrations (default all)")
+	fmt.Fprintln(os.Stderr, "  down [N]         revert the last N migrations (default 1)")
+	fmt.Fprintln(os.Stderr, "  status           show the current version and pending migrations")
+	fmt.Fprintln(os.Stderr, "  force VERSION    set the version without running migrations")
+}
@@ -0,0 +1,76 @@
+// Package cache menyimpan response yang sering dibaca (mis. /api/public)
+// beserta tag, sehingga semua response yang bergantung pada satu entry atau
+// content type bisa dihapus sekaligus saat datanya berubah.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DriverMemory = "memory"
+	DriverRedis  = "redis"
+	DriverOff    = "off"
+)
+
+// Store adalah penyimpanan key-value dengan TTL dan tag. Implementasi
+// aman dipakai bersamaan dari banyak goroutine.
+type Store interface {
+	// Get mengembalikan nilai dan true bila key ada dan belum kedaluwarsa.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Generation mengembalikan penghitung yang naik setiap Invalidate.
+	// Baca sebelum mengambil data yang akan disimpan lewat Set.
+	Generation(ctx context.Context) (uint64, error)
+	// Set menyimpan nilai selama ttl dan mencatat key di setiap tag, hanya
+	// bila Generation masih gen. Bila sudah ada Invalidate sejak gen dibaca,
+	// nilai mungkin sudah basi dan tidak disimpan.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags []string, gen uint64) error
+	// Invalidate menghapus semua key yang tercatat di salah satu tag.
+	Invalidate(ctx context.Context, tags ...string) error
+	Ping(ctx context.Context) error
+}
+
+type Config struct {
+	// Driver: memory (default), redis atau off.
+	Driver string
+	// MaxEntries membatasi jumlah key driver memory; 0 berarti default.
+	MaxEntries int
+
+	// Redis
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+}
+
+// New membuat store sesuai cfg.Driver. Driver off mengembalikan nil, yang
+// berarti tanpa cache. Koneksi ke Redis tidak dicek di sini; gunakan Ping.
+func New(cfg Config) (Store, error) {
+	switch strings.ToLower(cfg.Driver) {
+	case "", DriverMemory:
+		return NewMemory(cfg.MaxEntries), nil
+	case DriverRedis:
+		if cfg.RedisAddr == "" {
+			return nil, fmt.Errorf("cache driver redis membutuhkan REDIS_ADDR")
+		}
+		return NewRedis(cfg), nil
+	case DriverOff:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown cache driver %q (expected memory, redis or off)", cfg.Driver)
+}
+
+// Tag yang dipakai response /api/public. TagAll ada di setiap response
+// sehingga perubahan content type bisa mengosongkan semuanya sekaligus.
+const TagAll = "all"
+
+// TypeTag menandai semua response milik content type slug.
+func TypeTag(slug string) string { return "type:" + slug }
+
+// ListTag menandai response daftar entry content type slug.
+func ListTag(slug string) string { return "list:" + slug }
+
+// EntryTag menandai response yang berisi entry id.
+func EntryTag(id string) string { return "entry:" + id }
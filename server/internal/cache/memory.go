@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultMaxEntries adalah batas jumlah key driver memory bila tidak diatur.
+const DefaultMaxEntries = 10000
+
+// Memory menyimpan cache di memori proses dengan eviction LRU. Cocok untuk
+// satu instance API; dengan beberapa instance setiap instance punya cache
+// sendiri dan tetap di-invalidate lewat event NOTIFY.
+type Memory struct {
+	max int
+
+	mu    sync.Mutex
+	gen   uint64
+	lru   *list.List // *memItem, paling baru dipakai di depan
+	items map[string]*list.Element
+	tags  map[string]map[string]struct{}
+}
+
+type memItem struct {
+	key     string
+	value   []byte
+	expires time.Time
+	tags    []string
+}
+
+func NewMemory(maxEntries int) *Memory {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	return &Memory{
+		max:   maxEntries,
+		lru:   list.New(),
+		items: map[string]*list.Element{},
+		tags:  map[string]map[string]struct{}{},
+	}
+}
+
+func (m *Memory) Ping(context.Context) error { return nil }
+
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	item := el.Value.(*memItem)
+	if time.Now().After(item.expires) {
+		m.remove(el)
+		return nil, false, nil
+	}
+	m.lru.MoveToFront(el)
+	return item.value, true, nil
+}
+
+func (m *Memory) Generation(context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.gen, nil
+}
+
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration, tags []string, gen uint64) error {
+	if ttl <= 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if gen != m.gen {
+		return nil
+	}
+	if el, ok := m.items[key]; ok {
+		m.remove(el)
+	}
+	item := &memItem{key: key, value: value, expires: time.Now().Add(ttl), tags: tags}
+	m.items[key] = m.lru.PushFront(item)
+	for _, tag := range tags {
+		keys := m.tags[tag]
+		if keys == nil {
+			keys = map[string]struct{}{}
+			m.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+	for m.lru.Len() > m.max {
+		m.remove(m.lru.Back())
+	}
+	return nil
+}
+
+func (m *Memory) Invalidate(_ context.Context, tags ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gen++
+	for _, tag := range tags {
+		for key := range m.tags[tag] {
+			if el, ok := m.items[key]; ok {
+				m.remove(el)
+			}
+		}
+		delete(m.tags, tag)
+	}
+	return nil
+}
+
+// remove menghapus item beserta catatannya di semua tag; mu harus dipegang.
+func (m *Memory) remove(el *list.Element) {
+	item := m.lru.Remove(el).(*memItem)
+	delete(m.items, item.key)
+	for _, tag := range item.tags {
+		if keys := m.tags[tag]; keys != nil {
+			delete(keys, item.key)
+			if len(keys) == 0 {
+				delete(m.tags, tag)
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cms/server/internal/stream"
+)
+
+func TestMemorySetSkipsAfterInvalidate(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(0)
+
+	gen, _ := m.Generation(ctx)
+	// invalidasi datang saat handler masih membaca database
+	_ = m.Invalidate(ctx, ListTag("post"))
+	_ = m.Set(ctx, "k", []byte("stale"), time.Minute, []string{ListTag("post")}, gen)
+	if _, ok, _ := m.Get(ctx, "k"); ok {
+		t.Fatal("Set with an old generation stored a stale value")
+	}
+
+	gen, _ = m.Generation(ctx)
+	_ = m.Set(ctx, "k", []byte("fresh"), time.Minute, []string{ListTag("post")}, gen)
+	if v, ok, _ := m.Get(ctx, "k"); !ok || string(v) != "fresh" {
+		t.Fatalf("Get = %q, %v; want fresh value", v, ok)
+	}
+}
+
+func TestInvalidatesResyncFlushesAll(t *testing.T) {
+	tests := []struct {
+		msg  stream.Message
+		want []string
+	}{
+		{stream.Message{Event: stream.EventResync}, []string{TagAll}},
+		{stream.Message{Event: "content_type.changed"}, []string{TagAll}},
+		{stream.Message{Event: "entry.updated", ContentType: "post", Payload: []byte(`{"data":{"id":"e1"}}`)},
+			[]string{ListTag("post"), EntryTag("e1")}},
+	}
+	for _, tt := range tests {
+		got := invalidates(tt.msg)
+		if len(got) != len(tt.want) {
+			t.Errorf("invalidates(%s) = %v, want %v", tt.msg.Event, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("invalidates(%s) = %v, want %v", tt.msg.Event, got, tt.want)
+				break
+			}
+		}
+	}
+}
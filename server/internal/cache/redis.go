@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisPrefix = "cms:cache:"
+	// redisGenKey menyimpan Generation, dinaikkan setiap Invalidate
+	redisGenKey = redisPrefix + "generation"
+)
+
+// Redis menyimpan cache di Redis sehingga dipakai bersama semua instance
+// API. Setiap tag adalah set berisi key yang memakainya.
+type Redis struct {
+	client *redis.Client
+}
+
+func NewRedis(cfg Config) *Redis {
+	return &Redis{client: redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})}
+}
+
+func (r *Redis) Ping(ctx context.Context) error { return r.client.Ping(ctx).Err() }
+
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := r.client.Get(ctx, redisPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (r *Redis) Generation(ctx context.Context) (uint64, error) {
+	gen, err := r.client.Get(ctx, redisGenKey).Uint64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return gen, err
+}
+
+// setScript menyimpan nilai lalu mencatat key di setiap set tag, kecuali
+// generation (KEYS[1]) sudah berubah. Umur set tag hanya diperpanjang,
+// tidak pernah diperpendek, agar key yang masih hidup tetap bisa
+// di-invalidate.
+var setScript = redis.NewScript(`
+if (redis.call('GET', KEYS[1]) or '0') ~= ARGV[3] then
+	return 0
+end
+redis.call('SET', KEYS[2], ARGV[1], 'PX', ARGV[2])
+local ttl = tonumber(ARGV[2])
+for i = 3, #KEYS do
+	redis.call('SADD', KEYS[i], KEYS[2])
+	if redis.call('PTTL', KEYS[i]) < ttl then
+		redis.call('PEXPIRE', KEYS[i], ttl)
+	end
+end
+return 1
+`)
+
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags []string, gen uint64) error {
+	if ttl <= 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(tags)+2)
+	keys = append(keys, redisGenKey, redisPrefix+key)
+	for _, tag := range tags {
+		keys = append(keys, redisPrefix+"tag:"+tag)
+	}
+	return setScript.Run(ctx, r.client, keys, value, ttl.Milliseconds(), strconv.FormatUint(gen, 10)).Err()
+}
+
+// invalidateScript menaikkan generation (KEYS[1]) lalu menghapus semua key
+// di set tag beserta set-nya dalam satu langkah, sehingga Set yang
+// berjalan bersamaan tidak terlewat.
+var invalidateScript = redis.NewScript(`
+redis.call('INCR', KEYS[1])
+for i = 2, #KEYS do
+	local keys = redis.call('SMEMBERS', KEYS[i])
+	for j = 1, #keys, 500 do
+		redis.call('DEL', unpack(keys, j, math.min(j + 499, #keys)))
+	end
+	redis.call('DEL', KEYS[i])
+end
+return 1
+`)
+
+func (r *Redis) Invalidate(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(tags)+1)
+	keys = append(keys, redisGenKey)
+	for _, tag := range tags {
+		keys = append(keys, redisPrefix+"tag:"+tag)
+	}
+	return invalidateScript.Run(ctx, r.client, keys).Err()
+}
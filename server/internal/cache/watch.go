@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"cms/server/internal/model"
+	"cms/server/internal/stream"
+)
+
+// Watch menghapus response yang terdampak setiap kali ada event dari hub,
+// sampai ctx dibatalkan:
+//   - event entry menghapus daftar content type-nya dan detail entry itu;
+//   - content_type.changed menghapus semuanya, karena slug bisa berganti
+//     dan field yang dihapus ikut mengubah isi response;
+//   - listener yang tersambung ulang juga menghapus semuanya, karena event
+//     selama terputus tidak pernah sampai.
+func Watch(ctx context.Context, store Store, hub *stream.Hub) {
+	sub := hub.Subscribe(stream.Filter{ContentTypes: true, Resync: true})
+	defer hub.Unsubscribe(sub)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if err := store.Invalidate(ctx, invalidates(m)...); err != nil {
+				slog.ErrorContext(ctx, "cache: invalidate", "event", m.Event, "error", err)
+			}
+		}
+	}
+}
+
+// invalidates mengembalikan tag yang harus dihapus untuk event m.
+func invalidates(m stream.Message) []string {
+	if m.Event == model.EventContentTypeChanged || m.Event == stream.EventResync {
+		return []string{TagAll}
+	}
+	var ev struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	tags := []string{ListTag(m.ContentType)}
+	if json.Unmarshal(m.Payload, &ev) == nil && ev.Data.ID != "" {
+		tags = append(tags, EntryTag(ev.Data.ID))
+	} else {
+		tags = append(tags, TypeTag(m.ContentType))
+	}
+	return tags
+}
@@ -0,0 +1,99 @@
+// Package codegen membuat tipe Go dan TypeScript untuk Data entry dari
+// definisi ContentType, sehingga consumer tidak perlu menulis struct
+// sendiri untuk setiap content type. Nama tipe dibentuk dengan aturan yang
+// sama seperti /api/openapi.json (slug "blog-post" -> BlogPost).
+package codegen
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+
+	"cms/server/internal/model"
+	"cms/server/internal/openapi"
+)
+
+type typeDef struct {
+	ct     model.ContentType
+	name   string // tipe Data
+	idName string // tipe id entry
+	slug   string // konstanta slug
+	fields []*fieldDef
+}
+
+type fieldDef struct {
+	model.ContentField
+	opts  model.FieldOptions
+	ident string // nama field Go
+	// enum adalah nama tipe untuk select dengan choices
+	enum   string
+	consts []string // nama konstanta Go per choice
+	// target adalah tipe id untuk relation yang content type tujuannya ikut
+	// dibuat; kosong berarti string biasa
+	target string
+}
+
+// names menjaga agar tidak ada dua identifier top-level yang sama.
+type names map[string]bool
+
+func (n names) take(name string) string {
+	base := name
+	for i := 2; n[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	n[name] = true
+	return name
+}
+
+// prepare mengurutkan content type (slug) dan field (nama) agar hasil
+// generate stabil, lalu memberi nama pada semua tipe.
+func prepare(list []model.ContentType) []*typeDef {
+	sorted := append([]model.ContentType(nil), list...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Slug < sorted[j].Slug })
+
+	used := names{"Entry": true, "MediaID": true}
+	bySlug := map[string]*typeDef{}
+	defs := make([]*typeDef, 0, len(sorted))
+	for _, ct := range sorted {
+		name := used.take(openapi.TypeName(ct.Slug))
+		t := &typeDef{
+			ct:     ct,
+			name:   name,
+			idName: used.take(name + "ID"),
+			slug:   used.take(name + "Slug"),
+		}
+		bySlug[ct.Slug] = t
+		defs = append(defs, t)
+	}
+
+	for _, t := range defs {
+		fields := append([]model.ContentField(nil), t.ct.Fields...)
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+		idents := names{}
+		for _, f := range fields {
+			fd := &fieldDef{ContentField: f, opts: f.ParseOptions()}
+			fd.ident = idents.take(openapi.TypeName(f.Name))
+			switch {
+			case f.Kind == "select" && len(fd.opts.Choices) > 0:
+				fd.enum = used.take(t.name + fd.ident)
+				values := names{}
+				for _, choice := range fd.opts.Choices {
+					fd.consts = append(fd.consts, used.take(fd.enum+values.take(openapi.TypeName(choice))))
+				}
+			case f.Kind == "relation":
+				if target, ok := bySlug[fd.opts.Target]; ok {
+					fd.target = target.idName
+				}
+			}
+			t.fields = append(t.fields, fd)
+		}
+	}
+	return defs
+}
+
+// multiple melaporkan apakah nilai field berupa array.
+func (f *fieldDef) multiple() bool {
+	return f.opts.Multiple && (f.Kind == "select" || f.Kind == "image" || f.Kind == "relation")
+}
+
+var tsIdent = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
@@ -0,0 +1,62 @@
+package codegen
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"cms/server/internal/model"
+)
+
+func relationTypes() []model.ContentType {
+	return []model.ContentType{
+		{Name: "Author", Slug: "author", Fields: []model.ContentField{{Name: "name", Kind: "string"}}},
+		{Name: "Post", Slug: "post", Fields: []model.ContentField{
+			{Name: "author", Kind: "relation", Options: json.RawMessage(`{"target":"author","required":true}`)},
+			{Name: "related", Kind: "relation", Options: json.RawMessage(`{"target":"author","multiple":true}`)},
+			{Name: "legacy", Kind: "relation", Options: json.RawMessage(`{"target":"gone"}`)},
+		}},
+	}
+}
+
+// fields mengembalikan baris-baris src dengan spasi dirapatkan, agar
+// perataan kolom gofmt tidak ikut dibandingkan.
+func fields(src string) map[string]bool {
+	out := map[string]bool{}
+	for _, line := range strings.Split(src, "\n") {
+		out[strings.Join(strings.Fields(line), " ")] = true
+	}
+	return out
+}
+
+func TestGoRelationUsesTargetID(t *testing.T) {
+	src, err := Go(relationTypes(), "content")
+	if err != nil {
+		t.Fatalf("Go: %v", err)
+	}
+	lines := fields(string(src))
+	for _, want := range []string{
+		"Author AuthorID `json:\"author\"`",
+		"Related []AuthorID `json:\"related,omitempty\"`",
+		// content type tujuan tidak ikut dibuat: id tetap string
+		"Legacy *string `json:\"legacy,omitempty\"`",
+	} {
+		if !lines[want] {
+			t.Errorf("generated Go is missing %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestTypeScriptRelationUsesTargetID(t *testing.T) {
+	src := string(TypeScript(relationTypes()))
+	lines := fields(src)
+	for _, want := range []string{
+		"author: AuthorID;",
+		"related?: AuthorID[];",
+		"legacy?: string;",
+	} {
+		if !lines[want] {
+			t.Errorf("generated TypeScript is missing %q:\n%s", want, src)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cms/server/internal/model"
+)
+
+// Go membuat satu file Go di package pkg: struct Data per content type,
+// tipe id entry, tipe enum untuk select, dan Entry[T] seperti dikembalikan
+// API. Field tanpa options.required menjadi pointer (atau slice) dengan
+// omitempty.
+func Go(types []model.ContentType, pkg string) ([]byte, error) {
+	defs := prepare(types)
+	var body bytes.Buffer
+	needRaw := false
+
+	for _, t := range defs {
+		fmt.Fprintf(&body, "// %s adalah Data entry content type %q (%s).\n", t.name, t.ct.Name, t.ct.Slug)
+		fmt.Fprintf(&body, "type %s struct {\n", t.name)
+		for _, f := range t.fields {
+			if !validTag(f.Name) {
+				fmt.Fprintf(&body, "\t// field %q dilewati: tidak bisa ditulis sebagai tag json\n", f.Name)
+				continue
+			}
+			typ, omit := goType(f)
+			if typ == "json.RawMessage" {
+				needRaw = true
+			}
+			tag := f.Name
+			if omit {
+				tag += ",omitempty"
+			}
+			fmt.Fprintf(&body, "\t%s %s `json:%s`\n", f.ident, typ, strconv.Quote(tag))
+		}
+		fmt.Fprintf(&body, "}\n\n")
+
+		fmt.Fprintf(&body, "// %s adalah id entry %s.\n", t.idName, t.ct.Name)
+		fmt.Fprintf(&body, "type %s string\n\n", t.idName)
+		fmt.Fprintf(&body, "// %s dipakai di path /api/entries/:slug dan /api/public/:slug.\n", t.slug)
+		fmt.Fprintf(&body, "const %s = %q\n\n", t.slug, t.ct.Slug)
+
+		for _, f := range t.fields {
+			if f.enum == "" {
+				continue
+			}
+			fmt.Fprintf(&body, "// %s adalah pilihan field %s.\n", f.enum, f.Name)
+			fmt.Fprintf(&body, "type %s string\n\n", f.enum)
+			fmt.Fprintf(&body, "const (\n")
+			for i, choice := range f.opts.Choices {
+				fmt.Fprintf(&body, "\t%s %s = %q\n", f.consts[i], f.enum, choice)
+			}
+			fmt.Fprintf(&body, ")\n\n")
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by cms codegen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import (\n")
+	if needRaw {
+		fmt.Fprintf(&buf, "\t\"encoding/json\"\n")
+	}
+	fmt.Fprintf(&buf, "\t\"time\"\n)\n\n")
+	buf.WriteString(goPrelude)
+	buf.Write(body.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated go: %w", err)
+	}
+	return src, nil
+}
+
+const goPrelude = `// MediaID adalah id media (field image).
+type MediaID string
+
+// Entry adalah entry seperti dikembalikan API, dengan Data bertipe T.
+type Entry[T any] struct {
+	ID            string
+	ContentTypeID string
+	Slug          string
+	Status        string
+	Data          T
+	PublishedAt   *time.Time
+	CreatedBy     *string
+	UpdatedBy     *string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+`
+
+// goType mengembalikan tipe Go field dan apakah tag diberi omitempty.
+func goType(f *fieldDef) (string, bool) {
+	var typ string
+	switch f.Kind {
+	case "text", "string", "wysiwyg", "date":
+		typ = "string"
+	case "number":
+		typ = "float64"
+	case "bool":
+		typ = "bool"
+	case "select":
+		typ = "string"
+		if f.enum != "" {
+			typ = f.enum
+		}
+	case "image":
+		typ = "MediaID"
+	case "relation":
+		typ = "string"
+		if f.target != "" {
+			typ = f.target
+		}
+	default: // json dan kind yang tidak dikenal
+		return "json.RawMessage", true
+	}
+	if f.multiple() {
+		return "[]" + typ, !f.opts.Required
+	}
+	if f.opts.Required {
+		return typ, false
+	}
+	return "*" + typ, true
+}
+
+// validTag mengikuti aturan nama tag di encoding/json; nama lain diabaikan
+// oleh encoding/json sehingga field tidak akan pernah terisi.
+func validTag(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", r):
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+		default:
+			return false
+		}
+	}
+	return true
+}
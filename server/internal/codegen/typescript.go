@@ -0,0 +1,104 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cms/server/internal/model"
+)
+
+// TypeScript membuat file .ts berisi interface Data per content type, tipe
+// id entry, union string untuk select dan Entry<T> seperti dikembalikan
+// API. Field tanpa options.required menjadi opsional.
+func TypeScript(types []model.ContentType) []byte {
+	defs := prepare(types)
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by cms codegen. DO NOT EDIT.\n\n")
+	buf.WriteString(tsPrelude)
+
+	for _, t := range defs {
+		fmt.Fprintf(&buf, "/** id entry %s */\nexport type %s = string;\n\n", tsComment(t.ct.Name), t.idName)
+		fmt.Fprintf(&buf, "export const %s = %s;\n\n", t.slug, strconv.Quote(t.ct.Slug))
+
+		for _, f := range t.fields {
+			if f.enum == "" {
+				continue
+			}
+			values := make([]string, len(f.opts.Choices))
+			for i, choice := range f.opts.Choices {
+				values[i] = strconv.Quote(choice)
+			}
+			fmt.Fprintf(&buf, "export type %s = %s;\n\n", f.enum, strings.Join(values, " | "))
+		}
+
+		fmt.Fprintf(&buf, "/** Data entry content type %q (%s) */\n", tsComment(t.ct.Name), tsComment(t.ct.Slug))
+		fmt.Fprintf(&buf, "export interface %s {\n", t.name)
+		for _, f := range t.fields {
+			name := f.Name
+			if !tsIdent.MatchString(name) {
+				name = strconv.Quote(name)
+			}
+			optional := "?"
+			if f.opts.Required {
+				optional = ""
+			}
+			fmt.Fprintf(&buf, "  %s%s: %s;\n", name, optional, tsType(f))
+		}
+		buf.WriteString("}\n\n")
+	}
+	return append(bytes.TrimRight(buf.Bytes(), "\n"), '\n')
+}
+
+const tsPrelude = `/** id media (field image) */
+export type MediaID = string;
+
+/** Entry seperti dikembalikan API, dengan Data bertipe T. */
+export interface Entry<T> {
+  ID: string;
+  ContentTypeID: string;
+  Slug: string;
+  Status: string;
+  Data: T;
+  PublishedAt: string | null;
+  CreatedBy: string | null;
+  UpdatedBy: string | null;
+  CreatedAt: string;
+  UpdatedAt: string;
+}
+
+`
+
+func tsType(f *fieldDef) string {
+	var typ string
+	switch f.Kind {
+	case "text", "string", "wysiwyg", "date":
+		typ = "string"
+	case "number":
+		typ = "number"
+	case "bool":
+		typ = "boolean"
+	case "select":
+		typ = "string"
+		if f.enum != "" {
+			typ = f.enum
+		}
+	case "image":
+		typ = "MediaID"
+	case "relation":
+		typ = "string"
+		if f.target != "" {
+			typ = f.target
+		}
+	default: // json dan kind yang tidak dikenal
+		return "unknown"
+	}
+	if f.multiple() {
+		return typ + "[]"
+	}
+	return typ
+}
+
+// tsComment mencegah nama menutup blok komentar lebih awal.
+func tsComment(s string) string { return strings.ReplaceAll(s, "*/", "* /") }
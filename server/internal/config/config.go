@@ -1,7 +1,10 @@
 package config
 
 import (
+	"log/slog"
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -16,12 +19,85 @@ type Config struct {
 	AppPort   string
 	Env       string
 
-	// Tambahan MinIO
+	// Backend penyimpanan media: minio (default), s3, local atau memory
+	StorageDriver string
+	// Direktori untuk driver local
+	StorageLocalDir string
+	// URL dasar API untuk URL unduhan sementara driver local/memory
+	StoragePublicURL string
+
+	// Cara /media/:id/:filename mengirim file: stream (default) atau
+	// redirect ke presigned URL storage
+	MediaDelivery string
+	// Cache-Control max-age untuk media publik, mis. "24h"
+	MediaCacheMaxAge string
+
+	// Tambahan MinIO (juga dipakai driver s3)
 	MinIOEndpoint  string
 	MinIOAccessKey string
 	MinIOSecretKey string
 	MinIOBucket    string
+	MinIORegion    string
 	MinIOUseSSL    bool
+
+	// Batas ukuran upload per mime, mis. "image/*=20MB,video/*=2GB,*=50MB"
+	UploadMaxSizes string
+	// Mime yang boleh diupload, mis. "image/*,application/pdf"
+	UploadAllowedTypes string
+	// Alamat clamd, mis. "tcp://clamav:3310"; kosong berarti tanpa scan
+	ClamAVAddr string
+
+	// Cache response /api/public: memory (default), redis atau off
+	CacheDriver string
+	// Umur maksimum response di cache, mis. "5m"; perubahan konten
+	// menghapusnya lebih awal
+	PublicCacheTTL string
+
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+
+	// Penyimpanan bucket rate limit: memory (default) atau redis
+	RateLimitDriver string
+	// Policy "<jumlah>/<window>" per kelompok route, atau "off"
+	RateLimitAuth   string
+	RateLimitPublic string
+	RateLimitAPI    string
+	// Kuota per API key untuk endpoint publik, mis. "key1=600/m,key2=10000/h"
+	RateLimitAPIKeys string
+	// Proxy yang dipercaya untuk X-Forwarded-For, mis. "10.0.0.0/8";
+	// kosong berarti tidak ada proxy yang dipercaya (IP dari koneksi)
+	TrustedProxies string
+
+	// Log: format json (default) atau text, level debug/info/warn/error
+	LogFormat string
+	LogLevel  string
+	// Tracing OpenTelemetry: none (default), stdout atau otlp; endpoint
+	// otlp diatur lewat OTEL_EXPORTER_OTLP_ENDPOINT
+	TracesExporter string
+	ServiceName    string
+
+	// Listener terpisah untuk /metrics, mis. ":9090"; kosong berarti
+	// /metrics dipasang di router API dan wajib memakai MetricsToken
+	MetricsAddr string
+	// Bearer token untuk /metrics; kosong di listener terpisah berarti
+	// tanpa token
+	MetricsToken string
+
+	// Timeout server HTTP; 0 berarti tanpa batas. Read dan write default
+	// tanpa batas karena upload besar, stream SSE dan media bisa lama
+	HTTPReadHeaderTimeout time.Duration
+	HTTPReadTimeout       time.Duration
+	HTTPWriteTimeout      time.Duration
+	HTTPIdleTimeout       time.Duration
+	// Saat SIGTERM: jeda sebelum listener ditutup agar load balancer sempat
+	// melihat /readyz 503, lalu batas waktu menunggu request dan worker
+	ShutdownDelay   time.Duration
+	ShutdownTimeout time.Duration
+
+	// Jalankan migrasi yang tertunda saat API start; bila false API menolak
+	// start selama skema database tertinggal
+	MigrateOnBoot bool
 }
 
 func getenv(key, def string) string {
@@ -31,7 +107,20 @@ func getenv(key, def string) string {
 	return def
 }
 
+// getduration membaca durasi Go (mis. "30s"); nilai yang tidak valid
+// diganti def.
+func getduration(key, def string) time.Duration {
+	v := getenv(key, def)
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		slog.Warn(key+" tidak valid, memakai default", "value", v, "default", def)
+		d, _ = time.ParseDuration(def)
+	}
+	return d
+}
+
 func Load() Config {
+	redisDB, _ := strconv.Atoi(getenv("REDIS_DB", "0"))
 	return Config{
 		DBHost:         getenv("DB_HOST", "localhost"),
 		DBPort:         getenv("DB_PORT", "5432"),
@@ -46,6 +135,47 @@ func Load() Config {
 		MinIOAccessKey: getenv("MINIO_ACCESS_KEY", "minioadmin"),
 		MinIOSecretKey: getenv("MINIO_SECRET_KEY", "minioadmin"),
 		MinIOBucket:    getenv("MINIO_BUCKET", "media"),
+		MinIORegion:    getenv("MINIO_REGION", ""),
 		MinIOUseSSL:    getenv("MINIO_USE_SSL", "false") == "true",
+		UploadMaxSizes: getenv("UPLOAD_MAX_SIZES", "image/*=20MB,video/*=2GB,audio/*=200MB,*=50MB"),
+
+		UploadAllowedTypes: getenv("UPLOAD_ALLOWED_TYPES", "image/jpeg,image/png,image/gif,image/webp,image/svg+xml,image/avif,video/*,audio/*,application/pdf,text/plain,text/csv"),
+		ClamAVAddr:         getenv("CLAMAV_ADDR", ""),
+
+		StorageDriver:    getenv("STORAGE_DRIVER", "minio"),
+		StorageLocalDir:  getenv("STORAGE_LOCAL_DIR", "./data/media"),
+		StoragePublicURL: getenv("STORAGE_PUBLIC_URL", ""),
+		MediaDelivery:    getenv("MEDIA_DELIVERY", "stream"),
+		MediaCacheMaxAge: getenv("MEDIA_CACHE_MAX_AGE", "24h"),
+
+		CacheDriver:    getenv("CACHE_DRIVER", "memory"),
+		PublicCacheTTL: getenv("PUBLIC_CACHE_TTL", "5m"),
+		RedisAddr:      getenv("REDIS_ADDR", ""),
+		RedisPassword:  getenv("REDIS_PASSWORD", ""),
+		RedisDB:        redisDB,
+
+		RateLimitDriver:  getenv("RATE_LIMIT_DRIVER", "memory"),
+		RateLimitAuth:    getenv("RATE_LIMIT_AUTH", "10/m"),
+		RateLimitPublic:  getenv("RATE_LIMIT_PUBLIC", "300/m"),
+		RateLimitAPI:     getenv("RATE_LIMIT_API", "600/m"),
+		RateLimitAPIKeys: getenv("RATE_LIMIT_API_KEYS", ""),
+		TrustedProxies:   getenv("TRUSTED_PROXIES", ""),
+
+		LogFormat:      getenv("LOG_FORMAT", "json"),
+		LogLevel:       getenv("LOG_LEVEL", "info"),
+		TracesExporter: getenv("OTEL_TRACES_EXPORTER", "none"),
+		ServiceName:    getenv("OTEL_SERVICE_NAME", "cms-api"),
+
+		MetricsAddr:  getenv("METRICS_ADDR", ":9090"),
+		MetricsToken: getenv("METRICS_TOKEN", ""),
+
+		HTTPReadHeaderTimeout: getduration("HTTP_READ_HEADER_TIMEOUT", "10s"),
+		HTTPReadTimeout:       getduration("HTTP_READ_TIMEOUT", "0s"),
+		HTTPWriteTimeout:      getduration("HTTP_WRITE_TIMEOUT", "0s"),
+		HTTPIdleTimeout:       getduration("HTTP_IDLE_TIMEOUT", "120s"),
+		ShutdownDelay:         getduration("SHUTDOWN_DELAY", "0s"),
+		ShutdownTimeout:       getduration("SHUTDOWN_TIMEOUT", "30s"),
+
+		MigrateOnBoot: getenv("MIGRATE_ON_BOOT", "false") == "true",
 	}
 }
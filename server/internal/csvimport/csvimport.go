@@ -0,0 +1,356 @@
+// Package csvimport membuat atau meng-upsert entry dari file CSV.
+package csvimport
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+
+	"cms/server/internal/model"
+	"cms/server/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	ModeCreate = "create"
+	ModeUpsert = "upsert"
+
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionFailed  = "failed"
+)
+
+// progressEvery menentukan seberapa sering progress job disimpan.
+const progressEvery = 50
+
+type Options struct {
+	// Mapping kolom CSV -> nama field content type. Kosong berarti kolom yang
+	// namanya sama dengan nama field dipetakan otomatis.
+	Mapping      map[string]string
+	SlugColumn   string
+	StatusColumn string
+	Mode         string
+	EditorID     *uuid.UUID
+}
+
+type RowResult struct {
+	Row     int        `json:"row"`
+	Slug    string     `json:"slug"`
+	Action  string     `json:"action"`
+	EntryID *uuid.UUID `json:"entry_id,omitempty"`
+	Errors  []string   `json:"errors,omitempty"`
+}
+
+var ErrInvalidMapping = errors.New("invalid column mapping")
+
+type Importer struct {
+	cts     repository.ContentTypeRepository
+	entries repository.EntryRepository
+	jobs    repository.ImportJobRepository
+
+	// running menghitung job background yang belum selesai
+	running sync.WaitGroup
+}
+
+func New(cts repository.ContentTypeRepository, entries repository.EntryRepository, jobs repository.ImportJobRepository) *Importer {
+	return &Importer{cts: cts, entries: entries, jobs: jobs}
+}
+
+// Read membaca seluruh CSV. Baris pertama dianggap header.
+func Read(r io.Reader) ([]string, [][]string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil, errors.New("csv is empty")
+	}
+	header := records[0]
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+	return header, records[1:], nil
+}
+
+type column struct {
+	index int
+	field model.ContentField
+}
+
+type plan struct {
+	slug    string
+	fields  []model.ContentField
+	columns []column
+	slugCol int
+	statCol int
+	mode    string
+}
+
+func (im *Importer) prepare(ctx context.Context, slug string, header []string, opts Options) (*plan, error) {
+	ct, err := im.cts.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]model.ContentField, len(ct.Fields))
+	for _, f := range ct.Fields {
+		byName[f.Name] = f
+	}
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		index[h] = i
+	}
+
+	p := &plan{slug: slug, fields: ct.Fields, slugCol: -1, statCol: -1, mode: opts.Mode}
+	if p.mode == "" {
+		p.mode = ModeUpsert
+	}
+	if p.mode != ModeCreate && p.mode != ModeUpsert {
+		return nil, fmt.Errorf("%w: unknown mode %q", ErrInvalidMapping, p.mode)
+	}
+
+	slugCol := opts.SlugColumn
+	if slugCol == "" {
+		slugCol = "slug"
+	}
+	i, ok := index[slugCol]
+	if !ok {
+		return nil, fmt.Errorf("%w: slug column %q not found", ErrInvalidMapping, slugCol)
+	}
+	p.slugCol = i
+	if opts.StatusColumn != "" {
+		i, ok := index[opts.StatusColumn]
+		if !ok {
+			return nil, fmt.Errorf("%w: status column %q not found", ErrInvalidMapping, opts.StatusColumn)
+		}
+		p.statCol = i
+	}
+
+	mapping := opts.Mapping
+	if len(mapping) == 0 {
+		mapping = map[string]string{}
+		for _, h := range header {
+			if _, ok := byName[h]; ok {
+				mapping[h] = h
+			}
+		}
+	}
+	for col, fieldName := range mapping {
+		i, ok := index[col]
+		if !ok {
+			return nil, fmt.Errorf("%w: column %q not found", ErrInvalidMapping, col)
+		}
+		f, ok := byName[fieldName]
+		if !ok {
+			return nil, fmt.Errorf("%w: field %q does not exist on %s", ErrInvalidMapping, fieldName, slug)
+		}
+		p.columns = append(p.columns, column{index: i, field: f})
+	}
+	sort.Slice(p.columns, func(a, b int) bool { return p.columns[a].index < p.columns[b].index })
+	if len(p.columns) == 0 {
+		return nil, fmt.Errorf("%w: no column mapped to a field", ErrInvalidMapping)
+	}
+	return p, nil
+}
+
+// Validate memastikan mapping cocok dengan header dan content type tanpa
+// memproses baris apa pun.
+func (im *Importer) Validate(ctx context.Context, slug string, header []string, opts Options) error {
+	_, err := im.prepare(ctx, slug, header, opts)
+	return err
+}
+
+// Run memproses semua baris secara berurutan. progress dipanggil setelah
+// setiap baris dengan jumlah baris yang sudah diproses.
+func (im *Importer) Run(ctx context.Context, slug string, header []string, rows [][]string, opts Options, progress func(processed, succeeded, failed int)) ([]RowResult, error) {
+	p, err := im.prepare(ctx, slug, header, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]RowResult, 0, len(rows))
+	succeeded, failed := 0, 0
+	for i, row := range rows {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		// nomor baris mengikuti spreadsheet: header = baris 1
+		res := im.importRow(ctx, p, i+2, row, opts.EditorID)
+		if res.Action == ActionFailed {
+			failed++
+		} else {
+			succeeded++
+		}
+		results = append(results, res)
+		if progress != nil {
+			progress(i+1, succeeded, failed)
+		}
+	}
+	return results, nil
+}
+
+func (im *Importer) importRow(ctx context.Context, p *plan, rowNum int, row []string, editorID *uuid.UUID) RowResult {
+	res := RowResult{Row: rowNum, Action: ActionFailed}
+	cell := func(i int) string {
+		if i < 0 || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	res.Slug = cell(p.slugCol)
+	if res.Slug == "" {
+		res.Errors = append(res.Errors, "slug is empty")
+	}
+	status := cell(p.statCol)
+	if status != "" && status != "draft" && status != "published" {
+		res.Errors = append(res.Errors, fmt.Sprintf("invalid status %q", status))
+	}
+
+	values := map[string]any{}
+	for _, col := range p.columns {
+		v, err := ParseValue(col.field, cell(col.index))
+		if err != nil {
+			res.Errors = append(res.Errors, fmt.Sprintf("%s: %v", col.field.Name, err))
+			continue
+		}
+		if v != nil {
+			values[col.field.Name] = v
+		}
+	}
+	if len(res.Errors) > 0 {
+		return res
+	}
+
+	existing, err := im.entries.GetBySlug(ctx, p.slug, res.Slug)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		res.Errors = append(res.Errors, err.Error())
+		return res
+	}
+	if existing != nil && p.mode == ModeCreate {
+		res.Errors = append(res.Errors, "entry with this slug already exists")
+		return res
+	}
+
+	// kolom yang tidak dipetakan tetap memakai data lama
+	data := map[string]any{}
+	if existing != nil && len(existing.Data) > 0 {
+		_ = json.Unmarshal(existing.Data, &data)
+	}
+	for k, v := range values {
+		data[k] = v
+	}
+	for _, f := range p.fields {
+		if _, ok := data[f.Name]; !ok && f.ParseOptions().Required {
+			res.Errors = append(res.Errors, fmt.Sprintf("%s: is required", f.Name))
+		}
+	}
+	if len(res.Errors) > 0 {
+		return res
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		res.Errors = append(res.Errors, err.Error())
+		return res
+	}
+
+	if existing != nil {
+		var st *string
+		if status != "" {
+			st = &status
+		}
+		if err := im.entries.Update(ctx, p.slug, existing.ID, raw, st, editorID); err != nil {
+			res.Errors = append(res.Errors, err.Error())
+			return res
+		}
+		res.Action = ActionUpdated
+		res.EntryID = &existing.ID
+		return res
+	}
+
+	e := &model.Entry{Slug: res.Slug, Status: status}
+	if e.Status == "" {
+		e.Status = "draft"
+	}
+	if err := im.entries.Create(ctx, p.slug, e, raw, editorID); err != nil {
+		res.Errors = append(res.Errors, err.Error())
+		return res
+	}
+	res.Action = ActionCreated
+	res.EntryID = &e.ID
+	return res
+}
+
+// Start membuat import job dan memproses baris di background.
+func (im *Importer) Start(ctx context.Context, slug, filename string, header []string, rows [][]string, opts Options) (*model.ImportJob, error) {
+	if err := im.Validate(ctx, slug, header, opts); err != nil {
+		return nil, err
+	}
+	job := &model.ImportJob{
+		ContentTypeSlug: slug,
+		Filename:        filename,
+		Status:          model.JobPending,
+		TotalRows:       len(rows),
+		Report:          json.RawMessage(`[]`),
+		CreatedBy:       opts.EditorID,
+	}
+	if err := im.jobs.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	im.running.Add(1)
+	go func() {
+		defer im.running.Done()
+		im.runJob(job.ID, slug, header, rows, opts)
+	}()
+	return job, nil
+}
+
+// Wait menunggu semua job background selesai atau ctx habis. Job yang
+// belum selesai saat ctx habis tetap berstatus running.
+func (im *Importer) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		im.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (im *Importer) runJob(id uuid.UUID, slug string, header []string, rows [][]string, opts Options) {
+	// request asal sudah selesai, jadi job memakai context sendiri
+	ctx := context.Background()
+	_ = im.jobs.UpdateProgress(ctx, id, 0, 0, 0)
+
+	results, err := im.Run(ctx, slug, header, rows, opts, func(processed, succeeded, failed int) {
+		if processed%progressEvery == 0 || processed == len(rows) {
+			if err := im.jobs.UpdateProgress(ctx, id, processed, succeeded, failed); err != nil {
+				slog.ErrorContext(ctx, "import job: update progress", "job_id", id, "error", err)
+			}
+		}
+	})
+
+	report, _ := json.Marshal(results)
+	status, msg := model.JobCompleted, ""
+	if err != nil {
+		status, msg = model.JobFailed, err.Error()
+	}
+	if err := im.jobs.Finish(ctx, id, status, report, msg); err != nil {
+		slog.ErrorContext(ctx, "import job: finish", "job_id", id, "error", err)
+	}
+}
@@ -0,0 +1,92 @@
+package csvimport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"cms/server/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// ParseValue mengubah isi sel CSV menjadi nilai JSON sesuai kind field.
+// Sel kosong menghasilkan nil sehingga field tidak diisi.
+func ParseValue(f model.ContentField, raw string) (any, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	opts := f.ParseOptions()
+
+	switch model.NormalizeKind(f.Kind) {
+	case "text", "string", "wysiwyg":
+		return raw, nil
+	case "number":
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", raw)
+		}
+		return n, nil
+	case "bool":
+		switch strings.ToLower(raw) {
+		case "1", "true", "yes", "y":
+			return true, nil
+		case "0", "false", "no", "n":
+			return false, nil
+		}
+		return nil, fmt.Errorf("%q is not a boolean", raw)
+	case "date":
+		for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+			if t, err := time.Parse(layout, raw); err == nil {
+				if layout == "2006-01-02" {
+					return t.Format(layout), nil
+				}
+				return t.UTC().Format(time.RFC3339), nil
+			}
+		}
+		return nil, fmt.Errorf("%q is not a valid date", raw)
+	case "json":
+		if !json.Valid([]byte(raw)) {
+			return nil, errors.New("invalid json")
+		}
+		return json.RawMessage(raw), nil
+	case "select":
+		if opts.Multiple {
+			var out []string
+			for _, v := range strings.Split(raw, "|") {
+				v = strings.TrimSpace(v)
+				if len(opts.Choices) > 0 && !slices.Contains(opts.Choices, v) {
+					return nil, fmt.Errorf("%q is not one of %v", v, opts.Choices)
+				}
+				out = append(out, v)
+			}
+			return out, nil
+		}
+		if len(opts.Choices) > 0 && !slices.Contains(opts.Choices, raw) {
+			return nil, fmt.Errorf("%q is not one of %v", raw, opts.Choices)
+		}
+		return raw, nil
+	case "image", "relation":
+		if opts.Multiple {
+			var out []string
+			for _, v := range strings.Split(raw, "|") {
+				id, err := uuid.Parse(strings.TrimSpace(v))
+				if err != nil {
+					return nil, fmt.Errorf("%q is not a valid id", v)
+				}
+				out = append(out, id.String())
+			}
+			return out, nil
+		}
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid id", raw)
+		}
+		return id.String(), nil
+	}
+	return nil, fmt.Errorf("unsupported field kind %q", f.Kind)
+}
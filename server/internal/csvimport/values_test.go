@@ -0,0 +1,62 @@
+package csvimport
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"cms/server/internal/model"
+)
+
+func TestParseValue(t *testing.T) {
+	const (
+		id1 = "6f1c1a8e-8a34-4c4e-9f6e-2b1d0c9a7e11"
+		id2 = "0b7f2c44-3d52-4a61-8e0f-5c6d7e8f9a10"
+	)
+	field := func(kind, opts string) model.ContentField {
+		return model.ContentField{Name: "f", Kind: kind, Options: json.RawMessage(opts)}
+	}
+
+	tests := []struct {
+		name    string
+		field   model.ContentField
+		raw     string
+		want    any
+		wantErr bool
+	}{
+		{"empty cell", field("number", `{}`), "", nil, false},
+		{"text", field("text", `{}`), "Hello, world", "Hello, world", false},
+		{"wysiwyg", field("wysiwyg", `{}`), "<p>x</p>", "<p>x</p>", false},
+		{"number", field("number", `{}`), "12.5", 12.5, false},
+		{"number invalid", field("number", `{}`), "twelve", nil, true},
+		{"bool true", field("bool", `{}`), "Yes", true, false},
+		{"bool false", field("bool", `{}`), "0", false, false},
+		{"bool invalid", field("bool", `{}`), "maybe", nil, true},
+		{"boolean from admin ui", field("boolean", `{}`), "true", true, false},
+		{"date only", field("date", `{}`), "2024-05-01", "2024-05-01", false},
+		{"datetime to utc", field("date", `{}`), "2024-05-01T10:00:00+07:00", "2024-05-01T03:00:00Z", false},
+		{"date invalid", field("date", `{}`), "01/05/2024", nil, true},
+		{"json", field("json", `{}`), `{"a":1}`, json.RawMessage(`{"a":1}`), false},
+		{"json invalid", field("json", `{}`), `{a:1}`, nil, true},
+		{"select", field("select", `{"choices":["a","b"]}`), "b", "b", false},
+		{"select not a choice", field("select", `{"choices":["a","b"]}`), "c", nil, true},
+		{"select multiple", field("select", `{"choices":["a","b"],"multiple":true}`), "a | b", []string{"a", "b"}, false},
+		{"image", field("image", `{}`), id1, id1, false},
+		{"media from admin ui", field("media", `{}`), id1, id1, false},
+		{"image invalid", field("image", `{}`), "x.png", nil, true},
+		{"relation multiple", field("relation", `{"multiple":true}`), id1 + "|" + id2, []string{id1, id2}, false},
+		{"relation multiple invalid", field("relation", `{"multiple":true}`), id1 + "|nope", nil, true},
+		{"unknown kind", field("color", `{}`), "red", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseValue(tt.field, tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseValue(%s, %q) error = %v, wantErr %v", tt.field.Kind, tt.raw, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseValue(%s, %q) = %#v, want %#v", tt.field.Kind, tt.raw, got, tt.want)
+			}
+		})
+	}
+}
@@ -5,18 +5,25 @@ import (
 	"log"
 
 	"cms/server/internal/config"
+	"cms/server/internal/telemetry"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func MustOpen(cfg config.Config) *gorm.DB {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+func DSN(cfg config.Config) string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
 		cfg.DBHost, cfg.DBUser, cfg.DBPass, cfg.DBName, cfg.DBPort, cfg.DBSSL,
 	)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+}
+
+func MustOpen(cfg config.Config) *gorm.DB {
+	db, err := gorm.Open(postgres.Open(DSN(cfg)), &gorm.Config{Logger: telemetry.NewGormLogger()})
 	if err != nil {
 		log.Fatalf("failed to connect database: %v", err)
 	}
+	if err := db.Use(telemetry.GormTracing{}); err != nil {
+		log.Fatalf("failed to register tracing: %v", err)
+	}
 	return db
 }
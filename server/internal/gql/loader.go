@@ -0,0 +1,206 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"cms/server/internal/model"
+	"cms/server/internal/repository"
+	"cms/server/internal/service"
+
+	"github.com/google/uuid"
+)
+
+// entryNode adalah entry yang sedang di-resolve beserta Data-nya.
+type entryNode struct {
+	*model.Entry
+	data map[string]any
+}
+
+func newEntryNode(e *model.Entry) *entryNode {
+	n := &entryNode{Entry: e, data: map[string]any{}}
+	if len(e.Data) > 0 {
+		_ = json.Unmarshal(e.Data, &n.data)
+	}
+	return n
+}
+
+type mediaNode struct {
+	*model.MediaAsset
+	meta map[string]any
+}
+
+// loader mengumpulkan id yang diminta resolver di satu level query lalu
+// mengambil semuanya dengan satu query saat thunk pertama di level itu
+// dijalankan (graphql-go menjalankan thunk secara breadth-first).
+type loader[T any] struct {
+	fetch func(ids []uuid.UUID) (map[uuid.UUID]T, error)
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]bool
+	tried   map[uuid.UUID]bool
+	cache   map[uuid.UUID]T
+}
+
+func newLoader[T any](fetch func(ids []uuid.UUID) (map[uuid.UUID]T, error)) *loader[T] {
+	return &loader[T]{
+		fetch:   fetch,
+		pending: map[uuid.UUID]bool{},
+		tried:   map[uuid.UUID]bool{},
+		cache:   map[uuid.UUID]T{},
+	}
+}
+
+// queue mendaftarkan id dan mengembalikan fungsi yang menghasilkan item
+// yang ditemukan, dalam urutan ids. Id yang tidak ditemukan dilewati.
+func (l *loader[T]) queue(ids []uuid.UUID) func() ([]T, error) {
+	l.mu.Lock()
+	for _, id := range ids {
+		if !l.tried[id] {
+			l.pending[id] = true
+		}
+	}
+	l.mu.Unlock()
+
+	return func() ([]T, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if len(l.pending) > 0 {
+			batch := make([]uuid.UUID, 0, len(l.pending))
+			for id := range l.pending {
+				batch = append(batch, id)
+			}
+			l.pending = map[uuid.UUID]bool{}
+			found, err := l.fetch(batch)
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range batch {
+				l.tried[id] = true
+				if v, ok := found[id]; ok {
+					l.cache[id] = v
+				}
+			}
+		}
+		out := make([]T, 0, len(ids))
+		for _, id := range ids {
+			if v, ok := l.cache[id]; ok {
+				out = append(out, v)
+			}
+		}
+		return out, nil
+	}
+}
+
+// state berlaku untuk satu request GraphQL.
+type state struct {
+	svc       *Service
+	preview   *service.PreviewClaims
+	previewID uuid.UUID
+
+	mu      sync.Mutex
+	entries map[uuid.UUID]*loader[*entryNode] // per content type
+	media   *loader[*mediaNode]
+}
+
+type stateKey struct{}
+
+func withState(ctx context.Context, st *state) context.Context {
+	return context.WithValue(ctx, stateKey{}, st)
+}
+
+func stateFrom(ctx context.Context) *state {
+	st, _ := ctx.Value(stateKey{}).(*state)
+	return st
+}
+
+// previewing melaporkan apakah preview token berlaku untuk content type ini.
+func (st *state) previewing(ct *model.ContentType) bool {
+	return st.preview != nil && st.preview.ContentType == ct.Slug
+}
+
+func (st *state) entryLoader(ctx context.Context, ct *model.ContentType) *loader[*entryNode] {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	l, ok := st.entries[ct.ID]
+	if !ok {
+		l = newLoader(func(ids []uuid.UUID) (map[uuid.UUID]*entryNode, error) {
+			return st.fetchEntries(ctx, ct, ids)
+		})
+		st.entries[ct.ID] = l
+	}
+	return l
+}
+
+// fetchEntries mengambil entry published milik ct; entry yang sedang
+// di-preview diambil sebagai draft.
+func (st *state) fetchEntries(ctx context.Context, ct *model.ContentType, ids []uuid.UUID) (map[uuid.UUID]*entryNode, error) {
+	items, _, err := st.svc.entries.Find(ctx, repository.EntryQuery{
+		ContentTypeID: ct.ID,
+		IDs:           ids,
+		PublishedOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[uuid.UUID]*entryNode, len(items))
+	for i := range items {
+		out[items[i].ID] = newEntryNode(&items[i])
+	}
+	if st.previewing(ct) {
+		for _, id := range ids {
+			if id != st.previewID {
+				continue
+			}
+			if n, err := st.previewNode(ctx, ct, nil); err == nil {
+				out[id] = n
+			}
+		}
+	}
+	return out, nil
+}
+
+// previewNode mengembalikan entry di preview token dengan data draft atau
+// versi yang diminta token. e boleh nil bila entry belum diambil.
+func (st *state) previewNode(ctx context.Context, ct *model.ContentType, e *model.Entry) (*entryNode, error) {
+	if e == nil {
+		var err error
+		if e, err = st.svc.entries.Get(ctx, ct.Slug, st.previewID); err != nil {
+			return nil, err
+		}
+	}
+	if st.preview.Version > 0 {
+		v, err := st.svc.entries.GetVersion(ctx, e.ID, st.preview.Version)
+		if err != nil {
+			return nil, err
+		}
+		copied := *e
+		copied.Data = v.Data
+		e = &copied
+	}
+	return newEntryNode(e), nil
+}
+
+func (st *state) fetchMedia(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*mediaNode, error) {
+	items, err := st.svc.media.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[uuid.UUID]*mediaNode, len(items))
+	for i := range items {
+		n := &mediaNode{MediaAsset: &items[i], meta: map[string]any{}}
+		if len(items[i].Meta) > 0 {
+			_ = json.Unmarshal(items[i].Meta, &n.meta)
+		}
+		out[items[i].ID] = n
+	}
+	return out, nil
+}
+
+// published sama dengan syarat public API: status published dan
+// published_at sudah lewat.
+func published(e *model.Entry) bool {
+	return e.Status == "published" && e.PublishedAt != nil && !e.PublishedAt.After(time.Now())
+}
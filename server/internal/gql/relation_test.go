@@ -0,0 +1,107 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"cms/server/internal/model"
+	"cms/server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type fakeTypes struct {
+	repository.ContentTypeRepository
+	list []model.ContentType
+}
+
+func (f *fakeTypes) List(context.Context) ([]model.ContentType, error) {
+	return append([]model.ContentType(nil), f.list...), nil
+}
+
+// fakeEntries hanya mendukung query yang dipakai resolver: content type,
+// daftar id, kondisi slug dan PublishedOnly.
+type fakeEntries struct {
+	repository.EntryRepository
+	items []model.Entry
+}
+
+func (f *fakeEntries) Find(_ context.Context, q repository.EntryQuery) ([]model.Entry, int64, error) {
+	var out []model.Entry
+	for _, e := range f.items {
+		if e.ContentTypeID != q.ContentTypeID {
+			continue
+		}
+		if q.PublishedOnly && !published(&e) {
+			continue
+		}
+		if len(q.IDs) > 0 && !containsID(q.IDs, e.ID) {
+			continue
+		}
+		match := true
+		for _, c := range q.Conditions {
+			if c.Column == "slug" && c.Value != e.Slug {
+				match = false
+			}
+		}
+		if match {
+			out = append(out, e)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRelationResolvesTargetEntry(t *testing.T) {
+	now := time.Now().Add(-time.Hour)
+	author := model.ContentType{ID: uuid.New(), Name: "Author", Slug: "author", CreatedAt: now}
+	author.Fields = []model.ContentField{{Name: "name", Kind: "string"}}
+	post := model.ContentType{ID: uuid.New(), Name: "Post", Slug: "post", CreatedAt: now.Add(time.Second)}
+	post.Fields = []model.ContentField{
+		{Name: "title", Kind: "string"},
+		{Name: "author", Kind: "relation", Options: json.RawMessage(`{"target":"author"}`)},
+		{Name: "reviewers", Kind: "relation", Options: json.RawMessage(`{"target":"author","multiple":true}`)},
+	}
+
+	ann := model.Entry{ID: uuid.New(), ContentTypeID: author.ID, Slug: "ann", Status: "published", PublishedAt: &now,
+		Data: json.RawMessage(`{"name":"Ann"}`)}
+	bob := model.Entry{ID: uuid.New(), ContentTypeID: author.ID, Slug: "bob", Status: "published", PublishedAt: &now,
+		Data: json.RawMessage(`{"name":"Bob"}`)}
+	draft := model.Entry{ID: uuid.New(), ContentTypeID: author.ID, Slug: "draft", Status: "draft",
+		Data: json.RawMessage(`{"name":"Draft"}`)}
+	hello := model.Entry{ID: uuid.New(), ContentTypeID: post.ID, Slug: "hello", Status: "published", PublishedAt: &now,
+		Data: json.RawMessage(`{"title":"Hello","author":"` + ann.ID.String() + `","reviewers":["` +
+			bob.ID.String() + `","` + draft.ID.String() + `"]}`)}
+
+	svc := New(
+		&fakeTypes{list: []model.ContentType{post, author}},
+		&fakeEntries{items: []model.Entry{ann, bob, draft, hello}},
+		nil,
+	)
+	res, err := svc.Execute(context.Background(), Request{
+		Query: `{ post(slug: "hello") { title author { slug name } reviewers { name } } }`,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(res.Errors) > 0 {
+		t.Fatalf("Execute returned errors: %v", res.Errors)
+	}
+
+	got, _ := json.Marshal(res.Data)
+	// draft tidak ikut di relation karena publik hanya melihat entry publish
+	want := `{"post":{"author":{"name":"Ann","slug":"ann"},"reviewers":[{"name":"Bob"}],"title":"Hello"}}`
+	if string(got) != want {
+		t.Errorf("relation result:\n got  %s\n want %s", got, want)
+	}
+}
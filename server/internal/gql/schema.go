@@ -0,0 +1,659 @@
+package gql
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"cms/server/internal/model"
+	"cms/server/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// field sistem yang ada di setiap type entry; field konten dengan nama yang
+// sama diberi akhiran "_"
+var systemFields = map[string]string{
+	"id":          "id",
+	"slug":        "slug",
+	"status":      "status",
+	"publishedAt": "published_at",
+	"createdAt":   "created_at",
+	"updatedAt":   "updated_at",
+}
+
+// nama type yang sudah dipakai schema
+var reservedTypes = []string{
+	"Query", "Media", "JSON", "String", "Int", "Float", "Boolean", "ID",
+	"StringFilter", "FloatFilter", "BooleanFilter", "DateFilter", "IDFilter", "ListFilter",
+}
+
+// contentField adalah ContentField beserta namanya di schema.
+type contentField struct {
+	model.ContentField
+	name string
+	opts model.FieldOptions
+}
+
+// sortable melaporkan apakah field bisa dipakai di argumen sort.
+func (f contentField) sortable() bool {
+	switch f.Kind {
+	case "text", "string", "wysiwyg", "number", "bool", "date":
+		return true
+	case "select":
+		return !f.opts.Multiple
+	}
+	return false
+}
+
+type contentType struct {
+	*model.ContentType
+	typeName string
+	fields   []contentField
+	byName   map[string]contentField
+	object   *graphql.Object
+}
+
+type builder struct {
+	svc   *Service
+	types map[string]*contentType // per slug
+	names map[string]bool
+
+	json    *graphql.Scalar
+	media   *graphql.Object
+	filters map[string]*graphql.InputObject
+}
+
+func buildSchema(svc *Service, list []model.ContentType) (graphql.Schema, error) {
+	b := &builder{
+		svc:   svc,
+		types: map[string]*contentType{},
+		names: map[string]bool{},
+	}
+	for _, n := range reservedTypes {
+		b.names[n] = true
+	}
+	b.json = graphql.NewScalar(graphql.ScalarConfig{
+		Name:         "JSON",
+		Description:  "Nilai JSON apa pun.",
+		Serialize:    func(v any) any { return v },
+		ParseValue:   func(v any) any { return v },
+		ParseLiteral: func(ast.Value) any { return nil },
+	})
+	b.media = b.mediaType()
+	b.filters = filterTypes()
+
+	// content type paling lama mendapat nama tanpa akhiran bila bentrok
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.Before(list[j].CreatedAt)
+		}
+		return list[i].Slug < list[j].Slug
+	})
+	var order []*contentType
+	for i := range list {
+		ct := &contentType{ContentType: &list[i], byName: map[string]contentField{}}
+		ct.typeName = b.reserve(typeName(ct.Slug))
+		fields := append([]model.ContentField(nil), ct.Fields...)
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+		for _, f := range fields {
+			name := fieldName(f.Name)
+			for systemFields[name] != "" || ct.byName[name].name != "" {
+				name += "_"
+			}
+			cf := contentField{ContentField: f, name: name, opts: f.ParseOptions()}
+			ct.fields = append(ct.fields, cf)
+			ct.byName[name] = cf
+		}
+		b.types[ct.Slug] = ct
+		order = append(order, ct)
+	}
+
+	query := graphql.Fields{}
+	for _, ct := range order {
+		ct.object = b.entryType(ct)
+	}
+	for _, ct := range order {
+		root := lowerFirst(ct.typeName)
+		query[root] = &graphql.Field{
+			Type:        ct.object,
+			Description: fmt.Sprintf("Satu entry %s berdasarkan id atau slug.", ct.Name),
+			Args: graphql.FieldConfigArgument{
+				"id":   &graphql.ArgumentConfig{Type: graphql.ID},
+				"slug": &graphql.ArgumentConfig{Type: graphql.String},
+			},
+			Resolve: b.resolveOne(ct),
+		}
+		query[root+"List"] = &graphql.Field{
+			Type:        graphql.NewNonNull(b.listType(ct)),
+			Description: fmt.Sprintf("Daftar entry %s yang sudah publish.", ct.Name),
+			Args: graphql.FieldConfigArgument{
+				"where":  &graphql.ArgumentConfig{Type: b.whereType(ct)},
+				"sort":   &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: `Mis. ["-publishedAt", "title"]; "-" untuk urutan menurun.`},
+				"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: DefaultLimit},
+				"offset": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+			},
+			Resolve: b.resolveList(ct),
+		}
+	}
+	if len(query) == 0 {
+		// schema GraphQL wajib punya minimal satu field di Query
+		query["contentTypes"] = &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Resolve: func(graphql.ResolveParams) (any, error) { return []string{}, nil },
+		}
+	}
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: query}),
+	})
+}
+
+// reserve memastikan nama type, type list (<Name>List) dan input filter
+// (<Name>Where) belum dipakai, menambah akhiran angka bila perlu.
+func (b *builder) reserve(name string) string {
+	candidate := name
+	for n := 2; b.names[candidate] || b.names[candidate+"List"] || b.names[candidate+"Where"]; n++ {
+		candidate = name + "_" + strconv.Itoa(n)
+	}
+	b.names[candidate] = true
+	b.names[candidate+"List"] = true
+	b.names[candidate+"Where"] = true
+	return candidate
+}
+
+// typeName mengubah slug ("blog-post") menjadi nama type ("BlogPost").
+func typeName(slug string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range slug {
+		if !isNameRune(r) || r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	name := sb.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "T" + name
+	}
+	return name
+}
+
+// fieldName mengganti karakter yang tidak valid di nama GraphQL dengan "_".
+func fieldName(name string) string {
+	var sb strings.Builder
+	for _, r := range name {
+		if !isNameRune(r) {
+			r = '_'
+		}
+		sb.WriteRune(r)
+	}
+	out := sb.String()
+	if out == "" || unicode.IsDigit(rune(out[0])) || strings.HasPrefix(out, "__") {
+		out = "f" + out
+	}
+	return out
+}
+
+func isNameRune(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+func lowerFirst(s string) string {
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+func (b *builder) mediaType() *graphql.Object {
+	asset := func(p graphql.ResolveParams) *mediaNode { return p.Source.(*mediaNode) }
+	metaInt := func(key string) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (any, error) {
+			if v, ok := asset(p).meta[key].(float64); ok {
+				return int(v), nil
+			}
+			return nil, nil
+		}
+	}
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Media",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: func(p graphql.ResolveParams) (any, error) {
+				return asset(p).ID.String(), nil
+			}},
+			"filename": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (any, error) {
+				return asset(p).Filename, nil
+			}},
+			"mime": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (any, error) {
+				return asset(p).Mime, nil
+			}},
+			"sizeBytes": &graphql.Field{Type: graphql.Float, Resolve: func(p graphql.ResolveParams) (any, error) {
+				return asset(p).SizeBytes, nil
+			}},
+			"url": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Path file publik (/media/:id/:filename).",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					a := asset(p)
+					return "/media/" + a.ID.String() + "/" + url.PathEscape(a.Filename), nil
+				},
+			},
+			"width":  &graphql.Field{Type: graphql.Int, Resolve: metaInt("width")},
+			"height": &graphql.Field{Type: graphql.Int, Resolve: metaInt("height")},
+			"altText": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (any, error) {
+				return asset(p).AltText, nil
+			}},
+			"caption": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (any, error) {
+				return asset(p).Caption, nil
+			}},
+			"credit": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (any, error) {
+				return asset(p).Credit, nil
+			}},
+			"meta": &graphql.Field{Type: b.json, Resolve: func(p graphql.ResolveParams) (any, error) {
+				return asset(p).meta, nil
+			}},
+		},
+	})
+}
+
+func (b *builder) entryType(ct *contentType) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name:        ct.typeName,
+		Description: ct.Name,
+		// thunk agar relasi boleh saling merujuk (termasuk ke dirinya sendiri)
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			fields := graphql.Fields{
+				"id": &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(*entryNode).ID.String(), nil
+				}},
+				"slug": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(*entryNode).Slug, nil
+				}},
+				"status": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(*entryNode).Status, nil
+				}},
+				"publishedAt": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (any, error) {
+					if t := p.Source.(*entryNode).PublishedAt; t != nil {
+						return t.UTC().Format(time.RFC3339), nil
+					}
+					return nil, nil
+				}},
+				"createdAt": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(*entryNode).CreatedAt.UTC().Format(time.RFC3339), nil
+				}},
+				"updatedAt": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(*entryNode).UpdatedAt.UTC().Format(time.RFC3339), nil
+				}},
+			}
+			for _, f := range ct.fields {
+				fields[f.name] = b.contentField(f)
+			}
+			return fields
+		}),
+	})
+}
+
+// contentField memetakan kind field ke type GraphQL beserta resolver-nya.
+func (b *builder) contentField(f contentField) *graphql.Field {
+	key := f.Name
+	value := func(p graphql.ResolveParams) any { return p.Source.(*entryNode).data[key] }
+	scalar := func(t graphql.Output) *graphql.Field {
+		return &graphql.Field{Type: t, Resolve: func(p graphql.ResolveParams) (any, error) {
+			return value(p), nil
+		}}
+	}
+
+	switch f.Kind {
+	case "text", "string", "wysiwyg", "date":
+		return scalar(graphql.String)
+	case "number":
+		return scalar(graphql.Float)
+	case "bool":
+		return scalar(graphql.Boolean)
+	case "select":
+		if f.opts.Multiple {
+			return &graphql.Field{Type: graphql.NewList(graphql.String), Resolve: func(p graphql.ResolveParams) (any, error) {
+				return stringList(value(p)), nil
+			}}
+		}
+		return scalar(graphql.String)
+	case "image":
+		return b.refField(f, b.media, func(p graphql.ResolveParams, ids []uuid.UUID) func() ([]any, error) {
+			get := stateFrom(p.Context).media.queue(ids)
+			return func() ([]any, error) {
+				items, err := get()
+				return anySlice(items), err
+			}
+		})
+	case "relation":
+		target, ok := b.types[f.opts.Target]
+		if !ok {
+			// content type tujuan tidak ada: kembalikan id apa adanya
+			if f.opts.Multiple {
+				return &graphql.Field{Type: graphql.NewList(graphql.ID), Resolve: func(p graphql.ResolveParams) (any, error) {
+					return stringList(value(p)), nil
+				}}
+			}
+			return scalar(graphql.ID)
+		}
+		return b.refField(f, target.object, func(p graphql.ResolveParams, ids []uuid.UUID) func() ([]any, error) {
+			get := stateFrom(p.Context).entryLoader(p.Context, target.ContentType).queue(ids)
+			return func() ([]any, error) {
+				items, err := get()
+				return anySlice(items), err
+			}
+		})
+	}
+	return scalar(b.json)
+}
+
+// refField membuat field image/relation. Id dikumpulkan dulu lalu diambil
+// sekaligus lewat loader; rujukan yang tidak ditemukan (dihapus atau belum
+// publish) menjadi null atau dilewati dari list.
+func (b *builder) refField(f contentField, t graphql.Output, load func(graphql.ResolveParams, []uuid.UUID) func() ([]any, error)) *graphql.Field {
+	key := f.Name
+	ids := func(p graphql.ResolveParams) []uuid.UUID {
+		var out []uuid.UUID
+		for _, s := range stringList(p.Source.(*entryNode).data[key]) {
+			if id, err := uuid.Parse(s); err == nil {
+				out = append(out, id)
+			}
+		}
+		return out
+	}
+	if f.opts.Multiple {
+		return &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(t)), Resolve: func(p graphql.ResolveParams) (any, error) {
+			get := load(p, ids(p))
+			return func() (any, error) { return get() }, nil
+		}}
+	}
+	return &graphql.Field{Type: t, Resolve: func(p graphql.ResolveParams) (any, error) {
+		refs := ids(p)
+		if len(refs) == 0 {
+			return nil, nil
+		}
+		get := load(p, refs[:1])
+		return func() (any, error) {
+			items, err := get()
+			if err != nil || len(items) == 0 {
+				return nil, err
+			}
+			return items[0], nil
+		}, nil
+	}}
+}
+
+func (b *builder) listType(ct *contentType) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: ct.typeName + "List",
+		Fields: graphql.Fields{
+			"items": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ct.object)))},
+			"total": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+}
+
+func (b *builder) whereType(ct *contentType) *graphql.InputObject {
+	fields := graphql.InputObjectConfigFieldMap{
+		"id":          &graphql.InputObjectFieldConfig{Type: b.filters["IDFilter"]},
+		"slug":        &graphql.InputObjectFieldConfig{Type: b.filters["StringFilter"]},
+		"publishedAt": &graphql.InputObjectFieldConfig{Type: b.filters["DateFilter"]},
+		"createdAt":   &graphql.InputObjectFieldConfig{Type: b.filters["DateFilter"]},
+		"updatedAt":   &graphql.InputObjectFieldConfig{Type: b.filters["DateFilter"]},
+	}
+	for _, f := range ct.fields {
+		if t := filterFor(f); t != "" {
+			fields[f.name] = &graphql.InputObjectFieldConfig{Type: b.filters[t]}
+		}
+	}
+	return graphql.NewInputObject(graphql.InputObjectConfig{Name: ct.typeName + "Where", Fields: fields})
+}
+
+// filterFor memilih input filter untuk field; "" berarti tidak bisa difilter.
+func filterFor(f contentField) string {
+	if f.opts.Multiple && (f.Kind == "select" || f.Kind == "image" || f.Kind == "relation") {
+		return "ListFilter"
+	}
+	switch f.Kind {
+	case "text", "string", "wysiwyg", "select":
+		return "StringFilter"
+	case "number":
+		return "FloatFilter"
+	case "bool":
+		return "BooleanFilter"
+	case "date":
+		return "DateFilter"
+	case "image", "relation":
+		return "IDFilter"
+	}
+	return ""
+}
+
+func filterTypes() map[string]*graphql.InputObject {
+	input := func(name string, fields graphql.InputObjectConfigFieldMap) *graphql.InputObject {
+		return graphql.NewInputObject(graphql.InputObjectConfig{Name: name, Fields: fields})
+	}
+	ops := func(t graphql.Input, names ...string) graphql.InputObjectConfigFieldMap {
+		m := graphql.InputObjectConfigFieldMap{}
+		for _, n := range names {
+			if n == repository.OpIn {
+				m[n] = &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(t))}
+				continue
+			}
+			m[n] = &graphql.InputObjectFieldConfig{Type: t}
+		}
+		return m
+	}
+	return map[string]*graphql.InputObject{
+		"StringFilter":  input("StringFilter", ops(graphql.String, "eq", "ne", "in", "contains")),
+		"FloatFilter":   input("FloatFilter", ops(graphql.Float, "eq", "ne", "gt", "gte", "lt", "lte", "in")),
+		"BooleanFilter": input("BooleanFilter", ops(graphql.Boolean, "eq")),
+		"DateFilter":    input("DateFilter", ops(graphql.String, "eq", "ne", "gt", "gte", "lt", "lte")),
+		"IDFilter":      input("IDFilter", ops(graphql.ID, "eq", "in")),
+		"ListFilter":    input("ListFilter", ops(graphql.String, "has")),
+	}
+}
+
+func (b *builder) resolveOne(ct *contentType) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		st := stateFrom(p.Context)
+		q := repository.EntryQuery{ContentTypeID: ct.ID, Limit: 1, PublishedOnly: !st.previewing(ct.ContentType)}
+		id, hasID := p.Args["id"].(string)
+		slug, hasSlug := p.Args["slug"].(string)
+		switch {
+		case hasID == hasSlug:
+			return nil, errors.New("provide exactly one of id or slug")
+		case hasID:
+			uid, err := uuid.Parse(id)
+			if err != nil {
+				return nil, fmt.Errorf("invalid id %q", id)
+			}
+			q.IDs = []uuid.UUID{uid}
+		default:
+			q.Conditions = []repository.EntryCondition{{Column: "slug", Op: repository.OpEq, Value: slug}}
+		}
+		items, _, err := b.svc.entries.Find(p.Context, q)
+		if err != nil || len(items) == 0 {
+			return nil, err
+		}
+		e := &items[0]
+		if st.previewing(ct.ContentType) && e.ID == st.previewID {
+			return st.previewNode(p.Context, ct.ContentType, e)
+		}
+		if !published(e) {
+			return nil, nil
+		}
+		return newEntryNode(e), nil
+	}
+}
+
+func (b *builder) resolveList(ct *contentType) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		limit, _ := p.Args["limit"].(int)
+		offset, _ := p.Args["offset"].(int)
+		if limit <= 0 || limit > MaxLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+		}
+		if offset < 0 {
+			return nil, errors.New("offset must not be negative")
+		}
+		q := repository.EntryQuery{
+			ContentTypeID: ct.ID,
+			Limit:         limit,
+			Offset:        offset,
+			PublishedOnly: true,
+		}
+		if where, ok := p.Args["where"].(map[string]any); ok {
+			conds, err := conditions(ct, where)
+			if err != nil {
+				return nil, err
+			}
+			q.Conditions = conds
+		}
+		sortArg, _ := p.Args["sort"].([]any)
+		if len(sortArg) == 0 {
+			sortArg = []any{"-publishedAt"}
+		}
+		for _, v := range sortArg {
+			s, err := sortFor(ct, fmt.Sprint(v))
+			if err != nil {
+				return nil, err
+			}
+			q.Sort = append(q.Sort, s)
+		}
+
+		items, total, err := b.svc.entries.Find(p.Context, q)
+		if err != nil {
+			return nil, err
+		}
+		nodes := make([]*entryNode, len(items))
+		for i := range items {
+			nodes[i] = newEntryNode(&items[i])
+		}
+		return map[string]any{"items": nodes, "total": total}, nil
+	}
+}
+
+func sortFor(ct *contentType, key string) (repository.EntrySort, error) {
+	name, desc := strings.CutPrefix(key, "-")
+	s := repository.EntrySort{Desc: desc}
+	if col, ok := systemFields[name]; ok && name != "status" {
+		s.Column = col
+		return s, nil
+	}
+	if f, ok := ct.byName[name]; ok && f.sortable() {
+		s.Field = f.Name
+		return s, nil
+	}
+	return s, fmt.Errorf("cannot sort %s by %q", ct.typeName, name)
+}
+
+// conditions menerjemahkan argumen where menjadi EntryCondition.
+func conditions(ct *contentType, where map[string]any) ([]repository.EntryCondition, error) {
+	var out []repository.EntryCondition
+	for name, raw := range where {
+		ops, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		base := repository.EntryCondition{}
+		kind := ""
+		if col, ok := systemFields[name]; ok {
+			base.Column = col
+			kind = col
+		} else if f, ok := ct.byName[name]; ok {
+			base.Field = f.Name
+			base.Numeric = f.Kind == "number"
+			kind = f.Kind
+		} else {
+			return nil, fmt.Errorf("unknown filter field %q", name)
+		}
+		for op, v := range ops {
+			if v == nil {
+				continue
+			}
+			value, err := filterValue(kind, v)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", name, op, err)
+			}
+			c := base
+			c.Op = op
+			c.Value = value
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+// filterValue menyesuaikan nilai filter dengan cara nilai itu disimpan:
+// kolom timestamp & id memakai tipe aslinya, field di Data dibandingkan
+// sebagai teks (kecuali number).
+func filterValue(kind string, v any) (any, error) {
+	if list, ok := v.([]any); ok {
+		out := make([]any, 0, len(list))
+		for _, item := range list {
+			value, err := filterValue(kind, item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, value)
+		}
+		return out, nil
+	}
+	switch kind {
+	case "published_at", "created_at", "updated_at":
+		s := fmt.Sprint(v)
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			if t, err = time.Parse("2006-01-02", s); err != nil {
+				return nil, fmt.Errorf("%q is not a date (YYYY-MM-DD or RFC3339)", s)
+			}
+		}
+		return t, nil
+	case "id":
+		id, err := uuid.Parse(fmt.Sprint(v))
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid id", v)
+		}
+		return id, nil
+	case "number":
+		return v, nil
+	case "bool":
+		return strconv.FormatBool(v == true), nil
+	}
+	return fmt.Sprint(v), nil
+}
+
+// stringList menerima satu string atau array dari Data.
+func stringList(v any) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func anySlice[T any](items []T) []any {
+	out := make([]any, len(items))
+	for i, v := range items {
+		out[i] = v
+	}
+	return out
+}
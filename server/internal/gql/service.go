@@ -0,0 +1,186 @@
+// Package gql menyajikan GraphQL API baca-saja untuk konten. Schema dibuat
+// saat runtime dari definisi ContentType/ContentField (satu type per
+// content type) dan dibangun ulang ketika content type berubah.
+package gql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"cms/server/internal/repository"
+	"cms/server/internal/service"
+	"cms/server/internal/stream"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+const (
+	DefaultLimit = 10
+	MaxLimit     = 100
+	// MaxDepth membatasi kedalaman query agar relasi yang saling merujuk
+	// tidak bisa dipakai untuk query yang sangat besar. Introspection
+	// (__schema, __type) tidak dihitung.
+	MaxDepth = 12
+
+	// schema juga dibangun ulang berkala, berjaga-jaga bila event
+	// perubahan content type terlewat (mis. koneksi LISTEN terputus)
+	schemaMaxAge = 5 * time.Minute
+)
+
+// Request adalah body request GraphQL standar.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+type Service struct {
+	types   repository.ContentTypeRepository
+	entries repository.EntryRepository
+	media   repository.MediaRepository
+
+	mu      sync.Mutex
+	schema  *graphql.Schema
+	builtAt time.Time
+}
+
+func New(types repository.ContentTypeRepository, entries repository.EntryRepository, media repository.MediaRepository) *Service {
+	return &Service{types: types, entries: entries, media: media}
+}
+
+// Invalidate membuang schema yang tersimpan; request berikutnya membangun
+// schema baru dari database.
+func (s *Service) Invalidate() {
+	s.mu.Lock()
+	s.schema = nil
+	s.mu.Unlock()
+}
+
+// Watch memanggil Invalidate setiap kali ada event content_type.changed
+// (dari instance API mana pun) atau listener hub tersambung ulang, sampai
+// ctx dibatalkan.
+func (s *Service) Watch(ctx context.Context, hub *stream.Hub) {
+	sub := hub.Subscribe(stream.Filter{ContentTypes: true, Resync: true})
+	defer hub.Unsubscribe(sub)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			s.Invalidate()
+		}
+	}
+}
+
+// Schema mengembalikan schema untuk content type saat ini.
+func (s *Service) Schema(ctx context.Context) (*graphql.Schema, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.schema != nil && time.Since(s.builtAt) < schemaMaxAge {
+		return s.schema, nil
+	}
+	types, err := s.types.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := buildSchema(s, types)
+	if err != nil {
+		return nil, fmt.Errorf("build graphql schema: %w", err)
+	}
+	s.schema, s.builtAt = &schema, time.Now()
+	return s.schema, nil
+}
+
+// Execute menjalankan query. Tanpa preview hanya entry yang sudah publish
+// yang terlihat; dengan preview, entry di token dikembalikan sebagai draft
+// (atau versinya) di mana pun ia muncul. Error GraphQL dikembalikan di
+// dalam Result; error hanya untuk kegagalan membangun schema.
+func (s *Service) Execute(ctx context.Context, req Request, preview *service.PreviewClaims) (*graphql.Result, error) {
+	schema, err := s.Schema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(req.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, nil
+	}
+	if v := graphql.ValidateDocument(schema, doc, nil); !v.IsValid {
+		return &graphql.Result{Errors: v.Errors}, nil
+	}
+	if d := queryDepth(doc); d > MaxDepth {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(
+			fmt.Errorf("query depth %d exceeds the maximum of %d", d, MaxDepth),
+		)}, nil
+	}
+
+	st := &state{svc: s, entries: map[uuid.UUID]*loader[*entryNode]{}}
+	st.media = newLoader(func(ids []uuid.UUID) (map[uuid.UUID]*mediaNode, error) {
+		return st.fetchMedia(ctx, ids)
+	})
+	if preview != nil {
+		st.preview = preview
+		st.previewID, _ = preview.EntryID()
+	}
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        *schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withState(ctx, st),
+	}), nil
+}
+
+// queryDepth menghitung kedalaman selection set terdalam dari semua
+// operation. Dipanggil setelah validasi, jadi fragment tidak siklis.
+func queryDepth(doc *ast.Document) int {
+	frags := map[string]*ast.FragmentDefinition{}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			frags[f.Name.Value] = f
+		}
+	}
+	depth := 0
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok {
+			depth = max(depth, selectionDepth(op.SelectionSet, frags))
+		}
+	}
+	return depth
+}
+
+func selectionDepth(set *ast.SelectionSet, frags map[string]*ast.FragmentDefinition) int {
+	if set == nil {
+		return 0
+	}
+	depth := 0
+	for _, sel := range set.Selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name.Value, "__") {
+				continue
+			}
+			depth = max(depth, 1+selectionDepth(s.SelectionSet, frags))
+		case *ast.InlineFragment:
+			depth = max(depth, selectionDepth(s.SelectionSet, frags))
+		case *ast.FragmentSpread:
+			if f, ok := frags[s.Name.Value]; ok {
+				depth = max(depth, selectionDepth(f.SelectionSet, frags))
+			}
+		}
+	}
+	return depth
+}
@@ -0,0 +1,162 @@
+package imageproc
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// Info berisi metadata gambar yang disimpan di MediaAsset.Meta.
+type Info struct {
+	Width         int    `json:"width"`
+	Height        int    `json:"height"`
+	Format        string `json:"format"`
+	Blurhash      string `json:"blurhash"`
+	DominantColor string `json:"dominant_color"`
+}
+
+// Analyze membaca dimensi gambar lalu menghitung blurhash dan warna dominan
+// dari versi kecilnya.
+func Analyze(src []byte) (*Info, error) {
+	img, format, err := Decode(src)
+	if err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	info := &Info{Width: b.Dx(), Height: b.Dy(), Format: format}
+	if info.Width == 0 || info.Height == 0 {
+		return info, nil
+	}
+
+	small := Resize(img, 64, 64, FitContain)
+	thumb := image.NewNRGBA(small.Bounds())
+	draw.Draw(thumb, thumb.Bounds(), small, small.Bounds().Min, draw.Src)
+
+	cx, cy := 4, 3
+	if info.Height > info.Width {
+		cx, cy = 3, 4
+	}
+	info.Blurhash = blurhash(thumb, cx, cy)
+	info.DominantColor = dominantColor(thumb)
+	return info, nil
+}
+
+// dominantColor mengelompokkan piksel ke bucket 4-bit per channel lalu
+// mengembalikan rata-rata warna dari bucket terbanyak dalam format #rrggbb.
+// Piksel yang hampir transparan diabaikan.
+func dominantColor(img *image.NRGBA) string {
+	type bucket struct{ n, r, g, b int }
+	buckets := map[int]*bucket{}
+	var best *bucket
+	for i := 0; i+3 < len(img.Pix); i += 4 {
+		r, g, b, a := int(img.Pix[i]), int(img.Pix[i+1]), int(img.Pix[i+2]), img.Pix[i+3]
+		if a < 128 {
+			continue
+		}
+		key := (r>>4)<<8 | (g>>4)<<4 | b>>4
+		bk := buckets[key]
+		if bk == nil {
+			bk = &bucket{}
+			buckets[key] = bk
+		}
+		bk.n++
+		bk.r += r
+		bk.g += g
+		bk.b += b
+		if best == nil || bk.n > best.n {
+			best = bk
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return fmt.Sprintf("#%02x%02x%02x", best.r/best.n, best.g/best.n, best.b/best.n)
+}
+
+// blurhash meng-encode gambar menjadi string BlurHash (https://blurha.sh)
+// dengan cx×cy komponen.
+func blurhash(img *image.NRGBA, cx, cy int) string {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	factors := make([][3]float64, 0, cx*cy)
+	for j := 0; j < cy; j++ {
+		for i := 0; i < cx; i++ {
+			norm := 2.0
+			if i == 0 && j == 0 {
+				norm = 1
+			}
+			var r, g, b float64
+			for y := 0; y < h; y++ {
+				by := math.Cos(math.Pi * float64(j) * float64(y) / float64(h))
+				for x := 0; x < w; x++ {
+					basis := math.Cos(math.Pi*float64(i)*float64(x)/float64(w)) * by
+					p := img.PixOffset(img.Rect.Min.X+x, img.Rect.Min.Y+y)
+					r += basis * srgbToLinear(img.Pix[p])
+					g += basis * srgbToLinear(img.Pix[p+1])
+					b += basis * srgbToLinear(img.Pix[p+2])
+				}
+			}
+			scale := norm / float64(w*h)
+			factors = append(factors, [3]float64{r * scale, g * scale, b * scale})
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(base83((cx-1)+(cy-1)*9, 1))
+
+	maxValue := 1.0
+	if len(factors) > 1 {
+		actual := 0.0
+		for _, f := range factors[1:] {
+			actual = math.Max(actual, math.Max(math.Abs(f[0]), math.Max(math.Abs(f[1]), math.Abs(f[2]))))
+		}
+		q := int(math.Max(0, math.Min(82, math.Floor(actual*166-0.5))))
+		maxValue = float64(q+1) / 166
+		sb.WriteString(base83(q, 1))
+	} else {
+		sb.WriteString(base83(0, 1))
+	}
+
+	dc := factors[0]
+	sb.WriteString(base83(linearToSrgb(dc[0])<<16|linearToSrgb(dc[1])<<8|linearToSrgb(dc[2]), 4))
+	for _, f := range factors[1:] {
+		quant := func(v float64) int {
+			return int(math.Max(0, math.Min(18, math.Floor(signPow(v/maxValue, 0.5)*9+9.5))))
+		}
+		sb.WriteString(base83(quant(f[0])*19*19+quant(f[1])*19+quant(f[2]), 2))
+	}
+	return sb.String()
+}
+
+const base83Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+func base83(v, length int) string {
+	out := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		out[i] = base83Chars[v%83]
+		v /= 83
+	}
+	return string(out)
+}
+
+func srgbToLinear(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+func linearToSrgb(v float64) int {
+	c := math.Max(0, math.Min(1, v))
+	if c <= 0.0031308 {
+		return int(c*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(c, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(v, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), exp), v)
+}
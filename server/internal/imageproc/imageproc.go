@@ -0,0 +1,255 @@
+// Package imageproc membuat rendition gambar (resize, crop, konversi format)
+// dan mengekstrak informasi seperti dimensi, blurhash dan warna dominan.
+package imageproc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	FitCover   = "cover"   // isi kotak w×h, kelebihan dipotong di tengah
+	FitContain = "contain" // muat di dalam w×h, rasio dipertahankan
+	FitFill    = "fill"    // paksa tepat w×h tanpa mempertahankan rasio
+
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatWebP = "webp"
+
+	MaxDimension   = 4096
+	DefaultQuality = 80
+
+	// gambar sumber yang lebih besar dari ini ditolak agar decode tidak
+	// menghabiskan memori
+	maxSourcePixels = 50_000_000
+)
+
+var (
+	ErrInvalidOptions = errors.New("invalid render options")
+	ErrNotImage       = errors.New("file is not a supported image")
+	// ErrUnsupportedFormat dikembalikan bila encoder format tujuan tidak
+	// tersedia di server (mis. WebP tanpa binary cwebp).
+	ErrUnsupportedFormat = errors.New("output format is not supported on this server")
+)
+
+// CWebPPath adalah binary yang dipakai untuk encode WebP. Go standard library
+// hanya bisa decode WebP, jadi encoding diserahkan ke cwebp (libwebp-tools).
+var CWebPPath = "cwebp"
+
+// Options menentukan rendition yang diminta.
+type Options struct {
+	Width   int
+	Height  int
+	Fit     string
+	Format  string
+	Quality int
+}
+
+// Normalize mengisi nilai default dan memvalidasi opsi. srcFormat adalah
+// format gambar asli, dipakai bila Format kosong.
+func (o *Options) Normalize(srcFormat string) error {
+	if o.Width < 0 || o.Height < 0 || o.Width > MaxDimension || o.Height > MaxDimension {
+		return fmt.Errorf("%w: w and h must be between 0 and %d", ErrInvalidOptions, MaxDimension)
+	}
+	if o.Fit == "" {
+		o.Fit = FitCover
+	}
+	switch o.Fit {
+	case FitCover, FitContain, FitFill:
+	default:
+		return fmt.Errorf("%w: unknown fit %q", ErrInvalidOptions, o.Fit)
+	}
+	o.Format = strings.ToLower(o.Format)
+	if o.Format == "jpg" {
+		o.Format = FormatJPEG
+	}
+	if o.Format == "" {
+		// gif & webp sumber disimpan ulang sebagai png agar transparansi tetap ada
+		o.Format = FormatPNG
+		if srcFormat == FormatJPEG {
+			o.Format = FormatJPEG
+		}
+	}
+	switch o.Format {
+	case FormatJPEG, FormatPNG, FormatWebP:
+	default:
+		return fmt.Errorf("%w: unknown format %q", ErrInvalidOptions, o.Format)
+	}
+	if o.Quality == 0 {
+		o.Quality = DefaultQuality
+	}
+	if o.Quality < 1 || o.Quality > 100 {
+		return fmt.Errorf("%w: q must be between 1 and 100", ErrInvalidOptions)
+	}
+	return nil
+}
+
+// Key menghasilkan nama yang stabil untuk opsi ini, dipakai sebagai nama
+// object cache rendition.
+func (o Options) Key() string {
+	return fmt.Sprintf("%dx%d_%s_q%d.%s", o.Width, o.Height, o.Fit, o.Quality, o.Extension())
+}
+
+func (o Options) Extension() string {
+	if o.Format == FormatJPEG {
+		return "jpg"
+	}
+	return o.Format
+}
+
+func ContentType(format string) string {
+	return "image/" + format
+}
+
+// IsImage melaporkan apakah mime termasuk gambar yang bisa diproses.
+func IsImage(mime string) bool {
+	switch strings.ToLower(strings.TrimSpace(strings.Split(mime, ";")[0])) {
+	case "image/jpeg", "image/jpg", "image/png", "image/gif", "image/webp":
+		return true
+	}
+	return false
+}
+
+// Decode membaca gambar dan mengembalikan format aslinya.
+func Decode(src []byte) (image.Image, string, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(src))
+	if err != nil {
+		return nil, "", ErrNotImage
+	}
+	if cfg.Width*cfg.Height > maxSourcePixels {
+		return nil, "", fmt.Errorf("%w: image is too large (%dx%d)", ErrNotImage, cfg.Width, cfg.Height)
+	}
+	img, format, err := image.Decode(bytes.NewReader(src))
+	if err != nil {
+		return nil, "", ErrNotImage
+	}
+	return img, format, nil
+}
+
+// Render membuat rendition dari gambar sumber sesuai opsi.
+func Render(src []byte, opts Options) ([]byte, Options, error) {
+	img, format, err := Decode(src)
+	if err != nil {
+		return nil, opts, err
+	}
+	if err := opts.Normalize(format); err != nil {
+		return nil, opts, err
+	}
+	out := Resize(img, opts.Width, opts.Height, opts.Fit)
+
+	var buf bytes.Buffer
+	if err := encode(&buf, out, opts); err != nil {
+		return nil, opts, err
+	}
+	return buf.Bytes(), opts, nil
+}
+
+// Resize mengubah ukuran gambar. Bila hanya salah satu dari w/h diisi, sisi
+// lainnya dihitung dari rasio gambar. Gambar tidak pernah diperbesar.
+func Resize(img image.Image, w, h int, fit string) image.Image {
+	b := img.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	if sw == 0 || sh == 0 || (w == 0 && h == 0) {
+		return img
+	}
+	switch {
+	case w == 0:
+		w = max(1, sw*h/sh)
+	case h == 0:
+		h = max(1, sh*w/sw)
+	}
+
+	srcRect := b
+	switch fit {
+	case FitContain:
+		// skala terkecil supaya seluruh gambar masuk ke kotak
+		if sw*h > sh*w {
+			h = max(1, sh*w/sw)
+		} else {
+			w = max(1, sw*h/sh)
+		}
+	case FitCover:
+		// potong bagian tengah sumber agar rasionya sama dengan kotak
+		if sw*h > sh*w {
+			cw := sh * w / h
+			x0 := b.Min.X + (sw-cw)/2
+			srcRect = image.Rect(x0, b.Min.Y, x0+cw, b.Max.Y)
+		} else {
+			ch := sw * h / w
+			y0 := b.Min.Y + (sh-ch)/2
+			srcRect = image.Rect(b.Min.X, y0, b.Max.X, y0+ch)
+		}
+	}
+	if w > srcRect.Dx() || h > srcRect.Dy() {
+		if fit == FitFill {
+			w, h = min(w, sw), min(h, sh)
+		} else {
+			// pertahankan rasio saat dibatasi ke ukuran sumber
+			scale := min(float64(srcRect.Dx())/float64(w), float64(srcRect.Dy())/float64(h))
+			w, h = max(1, int(float64(w)*scale+0.5)), max(1, int(float64(h)*scale+0.5))
+		}
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, srcRect, draw.Src, nil)
+	return dst
+}
+
+func encode(w io.Writer, img image.Image, opts Options) error {
+	switch opts.Format {
+	case FormatJPEG:
+		return jpeg.Encode(w, flatten(img), &jpeg.Options{Quality: opts.Quality})
+	case FormatPNG:
+		return png.Encode(w, img)
+	case FormatWebP:
+		return encodeWebP(w, img, opts.Quality)
+	}
+	return ErrUnsupportedFormat
+}
+
+// encodeWebP mengirim gambar dalam bentuk PNG ke cwebp lewat stdin.
+func encodeWebP(w io.Writer, img image.Image, quality int) error {
+	path, err := exec.LookPath(CWebPPath)
+	if err != nil {
+		return fmt.Errorf("%w: webp (cwebp not installed)", ErrUnsupportedFormat)
+	}
+	var in bytes.Buffer
+	if err := png.Encode(&in, img); err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(path, "-quiet", "-q", strconv.Itoa(quality), "-o", "-", "--", "-")
+	cmd.Stdin = &in
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("cwebp: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// flatten menaruh gambar transparan di atas latar putih karena JPEG tidak
+// punya alpha channel.
+func flatten(img image.Image) image.Image {
+	if _, ok := img.(*image.YCbCr); ok {
+		return img
+	}
+	b := img.Bounds()
+	dst := image.NewRGBA(b)
+	draw.Draw(dst, b, image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, b, img, b.Min, draw.Over)
+	return dst
+}
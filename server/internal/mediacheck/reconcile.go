@@ -0,0 +1,142 @@
+// Package mediacheck mencocokkan isi bucket media dengan tabel media_assets:
+// mencari object yatim (tidak dirujuk asset mana pun), asset yang
+// object-nya hilang, dan object yang isinya tidak cocok dengan checksum.
+package mediacheck
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"cms/server/pkg/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultGrace melindungi object yang baru saja ditulis oleh upload yang
+// belum selesai disimpan ke database.
+const DefaultGrace = time.Hour
+
+const renditionsPrefix = "renditions/"
+
+// Store adalah bagian dari storage media yang dibutuhkan untuk rekonsiliasi
+// dan pemeriksaan integritas.
+type Store interface {
+	List(ctx context.Context, prefix string, fn func(storage.ObjectInfo) error) error
+	Open(ctx context.Context, objectName string) (io.ReadSeekCloser, int64, error)
+	Delete(ctx context.Context, objectName string) error
+}
+
+type Service struct {
+	db    *gorm.DB
+	store Store
+}
+
+func New(db *gorm.DB, store Store) *Service {
+	return &Service{db: db, store: store}
+}
+
+type Options struct {
+	// Delete menghapus object yatim dari bucket; tanpa ini hanya dilaporkan.
+	Delete bool
+	// Grace: object yang lebih muda dari ini tidak dianggap yatim.
+	Grace time.Duration
+}
+
+type Object struct {
+	Name         string    `json:"name"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+type MissingObject struct {
+	ID       uuid.UUID `json:"id"`
+	Filename string    `json:"filename"`
+	URL      string    `json:"url"`
+}
+
+type Report struct {
+	Scanned     int             `json:"scanned"`
+	Orphans     []Object        `json:"orphans"`
+	OrphanBytes int64           `json:"orphan_bytes"`
+	Deleted     int             `json:"deleted"`
+	Missing     []MissingObject `json:"missing"`
+}
+
+// Reconcile membandingkan bucket dengan database. Object dianggap yatim
+// bila bukan file asset, bukan rendition dari asset yang masih ada, dan
+// bukan milik sesi upload yang masih pending.
+func (s *Service) Reconcile(ctx context.Context, opts Options) (*Report, error) {
+	if opts.Grace <= 0 {
+		opts.Grace = DefaultGrace
+	}
+	db := s.db.WithContext(ctx)
+
+	var assets []MissingObject
+	if err := db.Table("media_assets").Select("id, filename, url").Scan(&assets).Error; err != nil {
+		return nil, err
+	}
+	byURL := make(map[string]bool, len(assets))
+	ids := make(map[string]bool, len(assets))
+	for _, a := range assets {
+		byURL[a.URL] = true
+		ids[a.ID.String()] = true
+	}
+	var pending []string
+	if err := db.Table("media_uploads").Where("status = ?", "pending").Pluck("object_name", &pending).Error; err != nil {
+		return nil, err
+	}
+	pendingSet := make(map[string]bool, len(pending))
+	for _, name := range pending {
+		pendingSet[name] = true
+	}
+
+	report := &Report{Orphans: []Object{}, Missing: []MissingObject{}}
+	seen := make(map[string]bool, len(assets))
+	cutoff := time.Now().Add(-opts.Grace)
+	err := s.store.List(ctx, "", func(obj storage.ObjectInfo) error {
+		report.Scanned++
+		if byURL[obj.Name] {
+			seen[obj.Name] = true
+			return nil
+		}
+		if pendingSet[obj.Name] || obj.LastModified.After(cutoff) {
+			return nil
+		}
+		if rest, ok := strings.CutPrefix(obj.Name, renditionsPrefix); ok {
+			id, _, _ := strings.Cut(rest, "/")
+			if ids[id] {
+				return nil
+			}
+		}
+		report.Orphans = append(report.Orphans, Object{Name: obj.Name, Size: obj.Size, LastModified: obj.LastModified})
+		report.OrphanBytes += obj.Size
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range assets {
+		if !seen[a.URL] {
+			report.Missing = append(report.Missing, a)
+		}
+	}
+
+	if opts.Delete {
+		for _, o := range report.Orphans {
+			if err := s.store.Delete(ctx, o.Name); err != nil && !storage.IsNotFound(err) {
+				return report, err
+			}
+			report.Deleted++
+		}
+	}
+
+	// jaga-jaga bila ada entry yang ditulis sebelum trigger media_usages ada
+	if err := db.Exec("SELECT rebuild_media_usages()").Error; err != nil {
+		return report, err
+	}
+	return report, nil
+}
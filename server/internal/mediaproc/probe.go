@@ -0,0 +1,245 @@
+// Package mediaproc mengekstrak metadata dan membuat poster untuk media
+// non-gambar (video, audio, PDF) memakai tool eksternal: ffprobe/ffmpeg
+// (ffmpeg) dan pdfinfo/pdftoppm (poppler-utils).
+package mediaproc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Binary yang dipakai. Bisa diganti (mis. path absolut) sebelum worker
+// dijalankan.
+var (
+	FFprobePath  = "ffprobe"
+	FFmpegPath   = "ffmpeg"
+	PDFInfoPath  = "pdfinfo"
+	PDFToPPMPath = "pdftoppm"
+)
+
+var (
+	// ErrToolMissing dikembalikan bila binary yang dibutuhkan tidak
+	// terpasang. Job tidak dicoba ulang.
+	ErrToolMissing = errors.New("media tool not installed")
+	// ErrUnsupported dikembalikan untuk mime yang tidak diproses.
+	ErrUnsupported = errors.New("media type is not processed")
+)
+
+// lebar maksimal poster dalam piksel
+const posterWidth = 1280
+
+// Info berisi metadata yang disimpan di MediaAsset.Meta. Field kosong tidak
+// ditulis.
+type Info struct {
+	Duration   float64 `json:"duration,omitempty"` // detik
+	Container  string  `json:"container,omitempty"`
+	Bitrate    int64   `json:"bitrate,omitempty"`
+	Width      int     `json:"width,omitempty"`
+	Height     int     `json:"height,omitempty"`
+	VideoCodec string  `json:"video_codec,omitempty"`
+	FrameRate  float64 `json:"frame_rate,omitempty"`
+	AudioCodec string  `json:"audio_codec,omitempty"`
+	SampleRate int     `json:"sample_rate,omitempty"`
+	Channels   int     `json:"channels,omitempty"`
+	Pages      int     `json:"pages,omitempty"`
+	PageWidth  float64 `json:"page_width,omitempty"` // point (1/72 inci)
+	PageHeight float64 `json:"page_height,omitempty"`
+	Title      string  `json:"title,omitempty"`
+
+	// hasPicture: ada stream video atau cover art yang bisa dijadikan poster
+	hasPicture bool
+}
+
+// Probe membaca metadata file di path sesuai mime-nya.
+func Probe(ctx context.Context, path, mime string) (*Info, error) {
+	switch {
+	case mime == "application/pdf":
+		return probePDF(ctx, path)
+	case strings.HasPrefix(mime, "video/"), strings.HasPrefix(mime, "audio/"):
+		return probeAV(ctx, path)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupported, mime)
+}
+
+// Poster membuat gambar JPEG dari frame video, cover art audio atau halaman
+// pertama PDF. Mengembalikan nil tanpa error bila file tidak punya gambar
+// (mis. audio tanpa cover).
+func Poster(ctx context.Context, path, mime string, info *Info) ([]byte, error) {
+	if mime == "application/pdf" {
+		return pdfPoster(ctx, path)
+	}
+	if !info.hasPicture {
+		return nil, nil
+	}
+	args := []string{"-v", "error"}
+	if info.VideoCodec != "" && info.Duration > 0 {
+		// frame paling awal sering hitam; ambil 10% durasi, maksimal 10 detik
+		args = append(args, "-ss", strconv.FormatFloat(math.Min(info.Duration/10, 10), 'f', 3, 64))
+	}
+	args = append(args, "-i", path,
+		"-frames:v", "1",
+		"-vf", fmt.Sprintf("scale='min(%d,iw)':-2", posterWidth),
+		"-f", "image2", "-c:v", "mjpeg", "-q:v", "3",
+		"pipe:1")
+	return run(ctx, FFmpegPath, args...)
+}
+
+type ffprobeOutput struct {
+	Streams []struct {
+		CodecType    string `json:"codec_type"`
+		CodecName    string `json:"codec_name"`
+		Width        int    `json:"width"`
+		Height       int    `json:"height"`
+		AvgFrameRate string `json:"avg_frame_rate"`
+		SampleRate   string `json:"sample_rate"`
+		Channels     int    `json:"channels"`
+		Disposition  struct {
+			AttachedPic int `json:"attached_pic"`
+		} `json:"disposition"`
+		Tags struct {
+			Rotate string `json:"rotate"`
+		} `json:"tags"`
+		SideDataList []struct {
+			Rotation float64 `json:"rotation"`
+		} `json:"side_data_list"`
+	} `json:"streams"`
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"format"`
+}
+
+func probeAV(ctx context.Context, path string) (*Info, error) {
+	out, err := run(ctx, FFprobePath, "-v", "error", "-print_format", "json", "-show_format", "-show_streams", path)
+	if err != nil {
+		return nil, err
+	}
+	var probe ffprobeOutput
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return nil, fmt.Errorf("ffprobe: %v", err)
+	}
+	info := &Info{Container: probe.Format.FormatName}
+	info.Duration, _ = strconv.ParseFloat(probe.Format.Duration, 64)
+	info.Bitrate, _ = strconv.ParseInt(probe.Format.BitRate, 10, 64)
+	for _, s := range probe.Streams {
+		switch s.CodecType {
+		case "video":
+			info.hasPicture = true
+			if s.Disposition.AttachedPic == 1 || info.VideoCodec != "" {
+				continue // cover art, atau bukan stream video pertama
+			}
+			info.VideoCodec = s.CodecName
+			info.Width, info.Height = s.Width, s.Height
+			info.FrameRate = parseRate(s.AvgFrameRate)
+			// video dari ponsel sering disimpan miring dengan metadata rotasi
+			rotation, _ := strconv.ParseFloat(s.Tags.Rotate, 64)
+			for _, sd := range s.SideDataList {
+				if sd.Rotation != 0 {
+					rotation = sd.Rotation
+				}
+			}
+			if r := math.Mod(math.Abs(rotation), 180); r == 90 {
+				info.Width, info.Height = info.Height, info.Width
+			}
+		case "audio":
+			if info.AudioCodec != "" {
+				continue
+			}
+			info.AudioCodec = s.CodecName
+			info.SampleRate, _ = strconv.Atoi(s.SampleRate)
+			info.Channels = s.Channels
+		}
+	}
+	if info.VideoCodec == "" && info.AudioCodec == "" {
+		return nil, errors.New("ffprobe: no audio or video stream found")
+	}
+	return info, nil
+}
+
+// parseRate mengubah "30000/1001" menjadi 29.97.
+func parseRate(s string) float64 {
+	num, den, ok := strings.Cut(s, "/")
+	if !ok {
+		f, _ := strconv.ParseFloat(s, 64)
+		return f
+	}
+	n, err1 := strconv.ParseFloat(num, 64)
+	d, err2 := strconv.ParseFloat(den, 64)
+	if err1 != nil || err2 != nil || d == 0 {
+		return 0
+	}
+	return math.Round(n/d*1000) / 1000
+}
+
+func probePDF(ctx context.Context, path string) (*Info, error) {
+	out, err := run(ctx, PDFInfoPath, path)
+	if err != nil {
+		return nil, err
+	}
+	info := &Info{}
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		key, value, ok := strings.Cut(sc.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Pages":
+			info.Pages, _ = strconv.Atoi(value)
+		case "Title":
+			info.Title = value
+		case "Page size":
+			// "612 x 792 pts (letter)"
+			var w, h float64
+			if _, err := fmt.Sscanf(value, "%g x %g", &w, &h); err == nil {
+				info.PageWidth, info.PageHeight = w, h
+			}
+		}
+	}
+	if info.Pages == 0 {
+		return nil, errors.New("pdfinfo: page count not found")
+	}
+	return info, nil
+}
+
+func pdfPoster(ctx context.Context, path string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "cms-poster-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	root := filepath.Join(dir, "poster")
+	if _, err := run(ctx, PDFToPPMPath, "-jpeg", "-f", "1", "-l", "1", "-singlefile",
+		"-scale-to", strconv.Itoa(posterWidth), path, root); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(root + ".jpg")
+}
+
+// run menjalankan binary dan mengembalikan stdout-nya.
+func run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrToolMissing, name)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s: %v: %s", filepath.Base(name), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,188 @@
+package mediaproc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"cms/server/internal/imageproc"
+	"cms/server/internal/model"
+	"cms/server/internal/repository"
+	"cms/server/pkg/storage"
+
+	"gorm.io/gorm"
+)
+
+const (
+	MaxAttempts = 3
+
+	batchSize = 4
+	// lease juga batas waktu satu job; job yang melewatinya diambil ulang
+	lease = 15 * time.Minute
+)
+
+// PosterName adalah object poster untuk media id. Disimpan di bawah
+// renditions/<id>/ sehingga ikut terhapus bersama media.
+func PosterName(id string) string {
+	return "renditions/" + id + "/poster.jpg"
+}
+
+// Backoff mengembalikan jeda sebelum percobaan berikutnya: 1 menit lalu
+// berlipat dua setiap kali gagal.
+func Backoff(attempts int) time.Duration {
+	return time.Minute << (attempts - 1)
+}
+
+type Worker struct {
+	jobs     repository.MediaJobRepository
+	media    repository.MediaRepository
+	store    storage.Storage
+	interval time.Duration
+}
+
+func NewWorker(jobs repository.MediaJobRepository, media repository.MediaRepository, store storage.Storage) *Worker {
+	return &Worker{
+		jobs:     jobs,
+		media:    media,
+		store:    store,
+		interval: 5 * time.Second,
+	}
+}
+
+// Run memproses antrian media_jobs sampai ctx dibatalkan.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		for {
+			// batch yang sudah diambil diselesaikan walau ctx dibatalkan
+			n, err := w.processBatch(context.WithoutCancel(ctx))
+			if err != nil {
+				slog.ErrorContext(ctx, "media worker", "error", err)
+			}
+			if err != nil || n < batchSize || ctx.Err() != nil {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) processBatch(ctx context.Context) (int, error) {
+	items, err := w.jobs.ClaimDue(ctx, batchSize, lease)
+	if err != nil {
+		return 0, err
+	}
+	for i := range items {
+		job := &items[i]
+		jobCtx, cancel := context.WithTimeout(ctx, lease)
+		err := w.process(jobCtx, job)
+		cancel()
+		now := time.Now()
+		switch {
+		case err == nil:
+			job.Status = model.JobCompleted
+			job.Error = ""
+			job.FinishedAt = &now
+		case errors.Is(err, ErrToolMissing), errors.Is(err, ErrUnsupported),
+			errors.Is(err, gorm.ErrRecordNotFound), job.Attempts >= MaxAttempts:
+			slog.WarnContext(ctx, "media worker: job gagal", "job_id", job.ID, "media_id", job.MediaID, "error", err)
+			job.Status = model.JobFailed
+			job.Error = err.Error()
+			job.FinishedAt = &now
+		default:
+			job.Status = model.JobPending
+			job.Error = err.Error()
+			job.NextAttemptAt = now.Add(Backoff(job.Attempts))
+		}
+		if err := w.jobs.Save(ctx, job); err != nil {
+			slog.ErrorContext(ctx, "media worker: save job", "job_id", job.ID, "error", err)
+		}
+	}
+	return len(items), nil
+}
+
+// process mengunduh file ke direktori sementara, mengekstrak metadata dan
+// poster, lalu menggabungkannya ke Meta.
+func (w *Worker) process(ctx context.Context, job *model.MediaJob) error {
+	asset, err := w.media.FindByID(ctx, job.MediaID.String())
+	if err != nil {
+		return err
+	}
+	dir, err := os.MkdirTemp("", "cms-media-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "source"+filepath.Ext(asset.URL))
+	if err := w.download(ctx, asset.URL, path); err != nil {
+		return fmt.Errorf("download %s: %w", asset.URL, err)
+	}
+
+	info, err := Probe(ctx, path, asset.Mime)
+	if err != nil {
+		return err
+	}
+	fields := map[string]any{}
+	raw, _ := json.Marshal(info)
+	_ = json.Unmarshal(raw, &fields)
+
+	// poster yang gagal dibuat tidak menggagalkan metadata
+	poster, err := Poster(ctx, path, asset.Mime, info)
+	if err != nil {
+		slog.WarnContext(ctx, "media worker: poster", "media_id", asset.ID, "error", err)
+	}
+	if len(poster) > 0 {
+		name := PosterName(asset.ID.String())
+		if err := storage.Upload(ctx, w.store, name, "image/jpeg", poster); err != nil {
+			return fmt.Errorf("upload poster: %w", err)
+		}
+		fields["poster"] = name
+		if pi, err := imageproc.Analyze(poster); err == nil {
+			fields["blurhash"] = pi.Blurhash
+			fields["dominant_color"] = pi.DominantColor
+			if info.Width == 0 {
+				// PDF & cover art: dimensi poster sebagai gantinya
+				fields["width"], fields["height"] = pi.Width, pi.Height
+			}
+		}
+	}
+
+	meta := map[string]any{}
+	if len(asset.Meta) > 0 {
+		_ = json.Unmarshal(asset.Meta, &meta)
+	}
+	for k, v := range fields {
+		meta[k] = v
+	}
+	meta["processed_at"] = time.Now().UTC().Format(time.RFC3339)
+	out, _ := json.Marshal(meta)
+	return w.media.UpdateMeta(ctx, asset.ID.String(), out)
+}
+
+func (w *Worker) download(ctx context.Context, name, path string) error {
+	src, _, err := w.store.Open(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
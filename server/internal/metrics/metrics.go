@@ -0,0 +1,156 @@
+// Package metrics mengumpulkan metrik Prometheus API: latency HTTP per
+// route, pool koneksi database, request ke storage, antrian job dan
+// beberapa counter bisnis. Semua metrik terdaftar di Registry dan
+// diekspos lewat Handler.
+package metrics
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "cms"
+
+// Registry berisi semua metrik API beserta metrik runtime Go dan proses.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Durasi request HTTP per route dan status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	storageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "request_duration_seconds",
+		Help:      "Durasi request ke MinIO/S3 per method HTTP.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	storageErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "request_errors_total",
+		Help:      "Request ke MinIO/S3 yang gagal (error jaringan atau status 5xx).",
+	}, []string{"method"})
+
+	entriesPublished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "entries",
+		Name:      "published_total",
+		Help:      "Entry yang dipublish per content type.",
+	}, []string{"content_type"})
+
+	loginFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "login_failures_total",
+		Help:      "Login yang gagal per alasan.",
+	}, []string{"reason"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpDuration, storageDuration, storageErrors, entriesPublished, loginFailures,
+	)
+	// series login gagal langsung ada (bernilai 0) agar rate() bisa dipakai
+	for _, reason := range []string{LoginInvalidCredentials, LoginInactive, LoginOther} {
+		loginFailures.WithLabelValues(reason)
+	}
+}
+
+// ObserveHTTP mencatat satu request. route adalah pola route gin (mis.
+// /api/entries/:slug), bukan path, agar jumlah series tetap terbatas.
+func ObserveHTTP(method, route string, status int, d time.Duration) {
+	httpDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(d.Seconds())
+}
+
+// ObserveStorage mencatat satu request ke MinIO/S3; dipasang sebagai
+// storage.Config.Observe.
+func ObserveStorage(method string, d time.Duration, err error) {
+	storageDuration.WithLabelValues(method).Observe(d.Seconds())
+	if err != nil {
+		storageErrors.WithLabelValues(method).Inc()
+	}
+}
+
+// EntryPublished menambah counter publish untuk content type slug.
+func EntryPublished(slug string) {
+	entriesPublished.WithLabelValues(slug).Inc()
+}
+
+// Alasan login gagal untuk LoginFailed. Label dibatasi ke nilai ini agar
+// pesan error tidak menambah series baru.
+const (
+	LoginInvalidCredentials = "invalid_credentials"
+	LoginInactive           = "inactive"
+	LoginOther              = "other"
+)
+
+// LoginFailed menambah counter login gagal; reason di luar konstanta
+// Login* dicatat sebagai LoginOther.
+func LoginFailed(reason string) {
+	switch reason {
+	case LoginInvalidCredentials, LoginInactive:
+	default:
+		reason = LoginOther
+	}
+	loginFailures.WithLabelValues(reason).Inc()
+}
+
+// RegisterDB mengekspos statistik pool koneksi database (koneksi terbuka,
+// idle, jumlah dan lama menunggu koneksi).
+func RegisterDB(db *sql.DB) error {
+	return register(collectors.NewDBStatsCollector(db, "cmsdb"))
+}
+
+// register mengabaikan collector yang sudah terdaftar, mis. bila router
+// dibuat lebih dari sekali dalam satu proses.
+func register(c prometheus.Collector) error {
+	if err := Registry.Register(c); err != nil {
+		if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewServer membuat server /metrics di listener terpisah dari API, mis.
+// port yang hanya bisa dijangkau Prometheus.
+func NewServer(addr, token string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler(token))
+	return &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+}
+
+// Handler menyajikan metrik dalam format Prometheus. Bila token tidak
+// kosong, request harus membawa header "Authorization: Bearer <token>".
+func Handler(token string) http.Handler {
+	h := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+	if token == "" {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestLoginFailedBoundsReason(t *testing.T) {
+	before := testutil.CollectAndCount(loginFailures)
+	LoginFailed(LoginInvalidCredentials)
+	LoginFailed("pq: connection refused to 10.0.0.5")
+	LoginFailed("user not found")
+
+	if n := testutil.CollectAndCount(loginFailures); n != before || n != 3 {
+		t.Errorf("login failure series = %d (before %d), want the 3 fixed reasons", n, before)
+	}
+	if got := testutil.ToFloat64(loginFailures.WithLabelValues(LoginOther)); got != 2 {
+		t.Errorf("other = %v, want 2", got)
+	}
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// queueTimeout membatasi lama query jumlah antrian per scrape.
+const queueTimeout = 2 * time.Second
+
+// QueueDepth menghitung item yang masih menunggu diproses di satu antrian.
+type QueueDepth func(ctx context.Context) (int64, error)
+
+var queueDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "queue", "depth"),
+	"Item yang belum selesai diproses per antrian.",
+	[]string{"queue"}, nil,
+)
+
+// queueCollector menghitung antrian saat di-scrape, sehingga nilainya
+// selalu sesuai database walau ada beberapa instance API.
+type queueCollector map[string]QueueDepth
+
+// RegisterQueues mengekspos kedalaman antrian sebagai cms_queue_depth
+// dengan label queue sesuai key map.
+func RegisterQueues(queues map[string]QueueDepth) error {
+	return register(queueCollector(queues))
+}
+
+func (q queueCollector) Describe(ch chan<- *prometheus.Desc) { ch <- queueDesc }
+
+func (q queueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), queueTimeout)
+	defer cancel()
+	for name, depth := range q {
+		n, err := depth(ctx)
+		if err != nil {
+			slog.WarnContext(ctx, "metrics: queue depth", "queue", name, "error", err)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(queueDesc, prometheus.GaugeValue, float64(n), name)
+	}
+}
@@ -0,0 +1,322 @@
+// Package migrate menerapkan migrasi SQL berversi dari package migrations.
+//
+// Versi yang terpasang dicatat di tabel schema_migrations dengan format yang
+// sama seperti golang-migrate (satu baris: version, dirty), sehingga database
+// yang sebelumnya dimigrasi lewat `migrate` CLI bisa langsung dilanjutkan.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cms/server/migrations"
+)
+
+// lockKey adalah key pg_advisory_lock yang dipegang selama migrasi, agar
+// beberapa instance yang start bersamaan tidak menjalankan migrasi ganda.
+const lockKey int64 = 0x636d735f6d6967 // "cms_mig"
+
+var (
+	ErrDirty  = errors.New("database schema is dirty")
+	ErrBehind = errors.New("database schema is behind")
+)
+
+// Migration adalah pasangan file NNNN_nama.up.sql dan NNNN_nama.down.sql.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status menggambarkan posisi skema database terhadap migrasi yang di-embed.
+type Status struct {
+	Version uint // 0 berarti belum ada migrasi yang terpasang
+	Dirty   bool
+	Latest  uint
+	Pending []Migration
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New membuat Migrator dengan migrasi yang di-embed ke binary.
+func New(db *sql.DB) (*Migrator, error) {
+	ms, err := Load(migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: ms}, nil
+}
+
+// Load membaca semua migrasi di root fsys, urut dari versi terkecil. Setiap
+// versi wajib punya file up dan down.
+func Load(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[uint]*Migration{}
+	for _, file := range files {
+		base, dir, ok := strings.Cut(strings.TrimSuffix(path.Base(file), ".sql"), ".")
+		if !ok || (dir != "up" && dir != "down") {
+			return nil, fmt.Errorf("migration %s: expected NNNN_name.up.sql or NNNN_name.down.sql", file)
+		}
+		num, name, _ := strings.Cut(base, "_")
+		v, err := strconv.ParseUint(num, 10, 32)
+		if err != nil || v == 0 {
+			return nil, fmt.Errorf("migration %s: invalid version %q", file, num)
+		}
+		body, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		m := byVersion[uint(v)]
+		if m == nil {
+			m = &Migration{Version: uint(v), Name: name}
+			byVersion[uint(v)] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration %s: version %d already used by %s", file, v, m.Name)
+		}
+		if dir == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	ms := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s: missing up or down file", m.Version, m.Name)
+		}
+		ms = append(ms, *m)
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].Version < ms[j].Version })
+	return ms, nil
+}
+
+// Latest mengembalikan versi migrasi terbaru yang di-embed.
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+func (m *Migrator) Status(ctx context.Context) (Status, error) {
+	st := Status{Latest: m.Latest()}
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return st, err
+	}
+	defer conn.Close()
+
+	if st.Version, st.Dirty, err = version(ctx, conn); err != nil {
+		return st, err
+	}
+	st.Pending = pending(m.migrations, st.Version)
+	return st, nil
+}
+
+// pending mengembalikan migrasi di ms (urut versi) yang lebih baru dari
+// versi terpasang current.
+func pending(ms []Migration, current uint) []Migration {
+	var out []Migration
+	for _, mig := range ms {
+		if mig.Version > current {
+			out = append(out, mig)
+		}
+	}
+	return out
+}
+
+// Check mengembalikan ErrBehind bila masih ada migrasi yang belum
+// diterapkan dan ErrDirty bila migrasi terakhir gagal di tengah jalan.
+// Skema yang lebih baru dari binary (mis. saat rollback deploy) dibiarkan.
+func (m *Migrator) Check(ctx context.Context) error {
+	st, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if st.Dirty {
+		return fmt.Errorf("%w at version %d, fix it manually then run `migrate force %d`", ErrDirty, st.Version, st.Version)
+	}
+	if len(st.Pending) > 0 {
+		return fmt.Errorf("%w: version %d, latest %d (run `migrate up` or set MIGRATE_ON_BOOT=true)", ErrBehind, st.Version, st.Latest)
+	}
+	return nil
+}
+
+// Up menerapkan n migrasi berikutnya (n <= 0 berarti semua) dan
+// mengembalikan migrasi yang diterapkan. Setiap migrasi berjalan dalam
+// transaksinya sendiri bersama update schema_migrations.
+func (m *Migrator) Up(ctx context.Context, n int) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, dirty, err := version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w at version %d", ErrDirty, current)
+		}
+		for _, mig := range pending(m.migrations, current) {
+			if n > 0 && len(applied) == n {
+				break
+			}
+			if err := apply(ctx, conn, mig, mig.Up, mig.Version); err != nil {
+				return err
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down membatalkan n migrasi terakhir (n <= 0 berarti semua) dan
+// mengembalikan migrasi yang dibatalkan.
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, dirty, err := version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w at version %d", ErrDirty, current)
+		}
+		i := len(m.migrations) - 1
+		for i >= 0 && m.migrations[i].Version > current {
+			i--
+		}
+		if current > 0 && (i < 0 || m.migrations[i].Version != current) {
+			return fmt.Errorf("database version %d is not a known migration", current)
+		}
+		for ; i >= 0; i-- {
+			if n > 0 && len(reverted) == n {
+				break
+			}
+			var prev uint
+			if i > 0 {
+				prev = m.migrations[i-1].Version
+			}
+			mig := m.migrations[i]
+			if err := apply(ctx, conn, mig, mig.Down, prev); err != nil {
+				return err
+			}
+			reverted = append(reverted, mig)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Force menulis versi tanpa menjalankan migrasi apa pun dan menghapus tanda
+// dirty; dipakai setelah database diperbaiki manual.
+func (m *Migrator) Force(ctx context.Context, v uint) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if err := setVersion(ctx, tx, v); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// withLock menjalankan fn di satu koneksi yang memegang advisory lock.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		_, unlockErr := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", lockKey)
+		if unlockErr != nil {
+			// lock session ikut lepas bila koneksinya dibuang dari pool
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT NOT NULL PRIMARY KEY,
+		dirty BOOLEAN NOT NULL
+	)`); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// version membaca versi terpasang; tabel yang belum ada berarti versi 0.
+func version(ctx context.Context, conn *sql.Conn) (uint, bool, error) {
+	var exists bool
+	if err := conn.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		return 0, false, err
+	}
+	if !exists {
+		return 0, false, nil
+	}
+	var (
+		v     int64
+		dirty bool
+	)
+	err := conn.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&v, &dirty)
+	if errors.Is(err, sql.ErrNoRows) || v < 0 {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return uint(v), dirty, nil
+}
+
+// apply menjalankan query migrasi lalu mencatat versi target dalam satu
+// transaksi, sehingga migrasi yang gagal tidak meninggalkan skema setengah
+// jadi.
+func apply(ctx context.Context, conn *sql.Conn, mig Migration, query string, target uint) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("migration %04d_%s: %w", mig.Version, mig.Name, err)
+	}
+	if err := setVersion(ctx, tx, target); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func setVersion(ctx context.Context, tx *sql.Tx, v uint) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return err
+	}
+	if v == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)", int64(v))
+	return err
+}
@@ -0,0 +1,136 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"cms/server/migrations"
+)
+
+func file(body string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(body)} }
+
+func TestLoad(t *testing.T) {
+	ms, err := Load(fstest.MapFS{
+		"0002_add_slug.up.sql":   file("ALTER TABLE t ADD slug TEXT;"),
+		"0002_add_slug.down.sql": file("ALTER TABLE t DROP slug;"),
+		"0001_init.up.sql":       file("CREATE TABLE t ();"),
+		"0001_init.down.sql":     file("DROP TABLE t;"),
+		"README.md":              file("bukan migrasi"),
+		"sub/0003_x.up.sql":      file("-- subdirektori tidak dibaca"),
+	})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(ms) != 2 {
+		t.Fatalf("Load returned %d migrations, want 2", len(ms))
+	}
+	want := []Migration{
+		{Version: 1, Name: "init", Up: "CREATE TABLE t ();", Down: "DROP TABLE t;"},
+		{Version: 2, Name: "add_slug", Up: "ALTER TABLE t ADD slug TEXT;", Down: "ALTER TABLE t DROP slug;"},
+	}
+	for i := range want {
+		if ms[i] != want[i] {
+			t.Errorf("migration %d = %+v, want %+v", i, ms[i], want[i])
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		fs      fstest.MapFS
+		wantErr string
+	}{
+		{
+			name:    "missing down",
+			fs:      fstest.MapFS{"0001_init.up.sql": file("x")},
+			wantErr: "missing up or down file",
+		},
+		{
+			name:    "missing up",
+			fs:      fstest.MapFS{"0001_init.down.sql": file("x")},
+			wantErr: "missing up or down file",
+		},
+		{
+			name: "duplicate version",
+			fs: fstest.MapFS{
+				"0001_init.up.sql":    file("x"),
+				"0001_init.down.sql":  file("x"),
+				"0001_other.up.sql":   file("x"),
+				"0001_other.down.sql": file("x"),
+			},
+			wantErr: "version 1 already used by",
+		},
+		{
+			name:    "non-numeric version",
+			fs:      fstest.MapFS{"init.up.sql": file("x"), "init.down.sql": file("x")},
+			wantErr: "invalid version",
+		},
+		{
+			name:    "version zero",
+			fs:      fstest.MapFS{"0000_init.up.sql": file("x"), "0000_init.down.sql": file("x")},
+			wantErr: "invalid version",
+		},
+		{
+			name:    "no direction",
+			fs:      fstest.MapFS{"0001_init.sql": file("x")},
+			wantErr: "expected NNNN_name.up.sql",
+		},
+		{
+			name:    "unknown direction",
+			fs:      fstest.MapFS{"0001_init.redo.sql": file("x")},
+			wantErr: "expected NNNN_name.up.sql",
+		},
+	}
+	for _, tt := range tests {
+		_, err := Load(tt.fs)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: Load error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPending(t *testing.T) {
+	ms := []Migration{{Version: 1}, {Version: 2}, {Version: 5}}
+	tests := []struct {
+		current uint
+		want    []uint
+	}{
+		{0, []uint{1, 2, 5}},
+		{1, []uint{2, 5}},
+		{2, []uint{5}},
+		// versi di antara migrasi (mis. hasil force) tetap dibandingkan urut
+		{3, []uint{5}},
+		{5, nil},
+		// database lebih baru dari binary: tidak ada yang tertunda
+		{9, nil},
+	}
+	for _, tt := range tests {
+		got := pending(ms, tt.current)
+		var versions []uint
+		for _, m := range got {
+			versions = append(versions, m.Version)
+		}
+		if len(versions) != len(tt.want) {
+			t.Errorf("pending(%d) = %v, want %v", tt.current, versions, tt.want)
+			continue
+		}
+		for i := range versions {
+			if versions[i] != tt.want[i] {
+				t.Errorf("pending(%d) = %v, want %v", tt.current, versions, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	ms, err := Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("embedded migrations: %v", err)
+	}
+	if len(ms) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+}
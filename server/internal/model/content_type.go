@@ -23,3 +23,34 @@ type ContentField struct {
 	Kind          string
 	Options       json.RawMessage `gorm:"type:jsonb;default:'{}'"`
 }
+
+// kindAliases memetakan nama kind yang dipakai admin UI ke kind server.
+var kindAliases = map[string]string{
+	"boolean": "bool",
+	"media":   "image",
+}
+
+// NormalizeKind mengembalikan kind kanonik, mis. "boolean" menjadi "bool".
+// Kind lain dikembalikan apa adanya.
+func NormalizeKind(kind string) string {
+	if k, ok := kindAliases[kind]; ok {
+		return k
+	}
+	return kind
+}
+
+// FieldOptions adalah isi ContentField.Options yang dipahami server.
+type FieldOptions struct {
+	Required bool     `json:"required,omitempty"`
+	Choices  []string `json:"choices,omitempty"` // untuk kind select
+	Target   string   `json:"target,omitempty"`  // slug content type tujuan untuk kind relation
+	Multiple bool     `json:"multiple,omitempty"`
+}
+
+func (f ContentField) ParseOptions() FieldOptions {
+	var o FieldOptions
+	if len(f.Options) > 0 {
+		_ = json.Unmarshal(f.Options, &o)
+	}
+	return o
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event lifecycle yang bisa di-subscribe webhook.
+const (
+	EventEntryCreated       = "entry.created"
+	EventEntryUpdated       = "entry.updated"
+	EventEntryPublished     = "entry.published"
+	EventEntryUnpublished   = "entry.unpublished"
+	EventEntryDeleted       = "entry.deleted"
+	EventMediaUploaded      = "media.uploaded"
+	EventMediaUpdated       = "media.updated"
+	EventMediaMoved         = "media.moved"
+	EventMediaDeleted       = "media.deleted"
+	EventContentTypeChanged = "content_type.changed"
+)
+
+var Events = []string{
+	EventEntryCreated,
+	EventEntryUpdated,
+	EventEntryPublished,
+	EventEntryUnpublished,
+	EventEntryDeleted,
+	EventMediaUploaded,
+	EventMediaUpdated,
+	EventMediaMoved,
+	EventMediaDeleted,
+	EventContentTypeChanged,
+}
+
+// EventsChannel adalah channel Postgres LISTEN/NOTIFY tempat event dikirim
+// ke semua instance API.
+const EventsChannel = "cms_events"
+
+// Event adalah payload yang dikirim ke webhook dan stream SSE.
+type Event struct {
+	ID        uuid.UUID `json:"id"`
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+)
+
+type ImportJob struct {
+	ID              uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	ContentTypeSlug string
+	Filename        string
+	Status          string
+	TotalRows       int
+	ProcessedRows   int
+	Succeeded       int
+	Failed          int
+	Report          json.RawMessage `gorm:"type:jsonb;default:'[]'"`
+	Error           string
+	CreatedBy       *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	FinishedAt      *time.Time
+}
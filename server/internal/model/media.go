@@ -5,6 +5,14 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	// MediaVisibilityAuto: publik selama dirujuk entry yang sudah publish.
+	MediaVisibilityAuto    = "auto"
+	MediaVisibilityPublic  = "public"
+	MediaVisibilityPrivate = "private"
 )
 
 type MediaAsset struct {
@@ -13,7 +21,56 @@ type MediaAsset struct {
 	Mime      string
 	SizeBytes int64
 	URL       string
-	Meta      json.RawMessage `gorm:"type:jsonb;default:'{}'"`
-	CreatedBy *uuid.UUID      `gorm:"type:uuid"`
+	// Checksum adalah SHA-256 (hex) dari isi file. Asset dengan isi yang
+	// sama berbagi satu object di storage.
+	Checksum string
+	// Visibility menentukan apakah file bisa diambil lewat /media/:id/:filename
+	// tanpa login. Lihat MediaVisibility*.
+	Visibility string          `gorm:"default:auto"`
+	Meta       json.RawMessage `gorm:"type:jsonb;default:'{}'"`
+	FolderID   *uuid.UUID      `gorm:"type:uuid"`
+	Tags       pq.StringArray  `gorm:"type:text[];default:'{}'"`
+	AltText    string
+	Caption    string
+	Credit     string
+	CreatedBy  *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt  time.Time
+}
+
+// MediaObject adalah satu file di storage. RefCount dijaga trigger database
+// dan berisi jumlah MediaAsset yang memakai object ini.
+type MediaObject struct {
+	ObjectName string `gorm:"primaryKey"`
+	Checksum   string
+	SizeBytes  int64
+	RefCount   int
+	CreatedAt  time.Time
+}
+
+type MediaFolder struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Name      string
+	ParentID  *uuid.UUID `gorm:"type:uuid"`
+	CreatedBy *uuid.UUID `gorm:"type:uuid"`
 	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// MediaJobMetadata mengekstrak metadata (durasi, codec, dimensi, jumlah
+// halaman) dan membuat poster untuk video, audio dan PDF.
+const MediaJobMetadata = "metadata"
+
+// MediaJob adalah satu pekerjaan pemrosesan media di background. Status
+// memakai konstanta Job*.
+type MediaJob struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	MediaID       uuid.UUID `gorm:"type:uuid;index"`
+	Kind          string
+	Status        string
+	Attempts      int
+	Error         string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	FinishedAt    *time.Time
 }
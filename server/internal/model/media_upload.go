@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	UploadMethodMultipart = "multipart"
+	UploadMethodPresigned = "presigned"
+
+	UploadPending   = "pending"
+	UploadCompleted = "completed"
+	UploadAborted   = "aborted"
+)
+
+// MediaUpload adalah sesi upload besar yang belum menjadi MediaAsset: lewat
+// multipart upload S3 (part dikirim lewat API dan bisa dilanjutkan) atau
+// presigned POST langsung ke storage.
+type MediaUpload struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Method     string
+	ObjectName string
+	UploadID   string
+	Filename   string
+	Mime       string
+	SizeBytes  int64
+	PartSize   int64
+	Status     string
+	AssetID    *uuid.UUID `gorm:"type:uuid"`
+	CreatedBy  *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+type Webhook struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Name      string
+	URL       string
+	Secret    string         `json:"-"`
+	Events    pq.StringArray `gorm:"type:text[]"`
+	Active    bool
+	CreatedBy *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// WebhookDelivery adalah baris outbox: satu event untuk satu webhook, berikut
+// hasil percobaan pengiriman terakhirnya.
+type WebhookDelivery struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	WebhookID      uuid.UUID `gorm:"type:uuid;index"`
+	EventID        uuid.UUID `gorm:"type:uuid"`
+	Event          string
+	Payload        json.RawMessage `gorm:"type:jsonb;default:'{}'"`
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastAttemptAt  *time.Time
+	LastStatusCode int
+	LastError      string
+	LastResponse   string
+	DurationMs     int64
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
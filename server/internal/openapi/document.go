@@ -0,0 +1,308 @@
+// Package openapi membangun dokumen OpenAPI 3 dari route yang benar-benar
+// terdaftar di router dan dari definisi ContentType, sehingga dokumen
+// selalu sama dengan server yang sedang berjalan.
+package openapi
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cms/server/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+const openAPIVersion = "3.0.3"
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Tags       []Tag                `json:"tags,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+	Security   []Requirement        `json:"security,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem dipetakan per method (huruf kecil: get, post, ...).
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	// Security nil berarti mengikuti security dokumen (bearer token);
+	// slice kosong berarti endpoint publik.
+	Security *[]Requirement `json:"security,omitempty"`
+	// Roles adalah role yang boleh memanggil endpoint (x-roles).
+	Roles []string `json:"x-roles,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	Responses       map[string]*Response       `json:"responses,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+type Requirement map[string][]string
+
+// Build membuat dokumen dari route gin dan content type saat ini. Route
+// /api/entries/:slug dan /api/public/:slug dijabarkan menjadi path per
+// content type dengan schema Data masing-masing; path generik {slug} hanya
+// dipakai bila belum ada content type.
+func Build(routes gin.RoutesInfo, types []model.ContentType) *Document {
+	b := newBuilder()
+	b.contentTypes(types)
+
+	sorted := append(gin.RoutesInfo(nil), routes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Path != sorted[j].Path {
+			return sorted[i].Path < sorted[j].Path
+		}
+		return methodOrder(sorted[i].Method) < methodOrder(sorted[j].Method)
+	})
+	for _, rt := range sorted {
+		spec, ok := registry[rt.Method+" "+rt.Path]
+		if !ok {
+			spec = route{tag: tagFor(rt.Path)}
+		}
+		if isPerType(rt.Path) && len(b.types) > 0 {
+			for _, t := range b.types {
+				path := strings.Replace(rt.Path, ":slug", t.ct.Slug, 1)
+				b.operation(rt.Method, path, spec, t)
+			}
+			continue
+		}
+		b.operation(rt.Method, rt.Path, spec, b.generic)
+	}
+
+	return &Document{
+		OpenAPI: openAPIVersion,
+		Info: Info{
+			Title:       "CMS API",
+			Description: "Dokumen ini dibangun dari route yang terdaftar dan content type saat ini.",
+			Version:     "1",
+		},
+		Tags:  b.tags(),
+		Paths: b.paths,
+		Components: Components{
+			Schemas: b.schemas,
+			Responses: map[string]*Response{
+				"Error": {
+					Description: "Error",
+					Content:     jsonContent(&Schema{Ref: schemaRef("Error")}),
+				},
+			},
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+		Security: []Requirement{{"bearerAuth": {}}},
+	}
+}
+
+func (b *builder) operation(method, path string, spec route, t *entryType) {
+	op := &Operation{
+		OperationID: b.operationID(method, path, spec.id, t),
+		Summary:     strings.ReplaceAll(spec.summary, "{type}", t.ct.Name),
+		Description: spec.description,
+		Tags:        []string{spec.tag},
+		Responses:   map[string]*Response{},
+	}
+	if t != b.generic {
+		op.Tags = []string{spec.tag + ": " + t.ct.Slug}
+	}
+	b.usedTags[op.Tags[0]] = true
+
+	public, roles := access(path)
+	if public {
+		op.Security = &[]Requirement{}
+	}
+	op.Roles = roles
+
+	op.Parameters = pathParams(path)
+	op.Parameters = append(op.Parameters, spec.query...)
+
+	if spec.body != nil {
+		ct := spec.bodyType
+		if ct == "" {
+			ct = "application/json"
+		}
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{ct: {Schema: b.resolve(spec.body, t)}},
+		}
+	}
+
+	status := spec.status
+	if status == 0 {
+		status = 200
+	}
+	res := &Response{Description: "OK"}
+	if spec.resp != nil {
+		ct := spec.respType
+		if ct == "" {
+			ct = "application/json"
+		}
+		res.Content = map[string]*MediaType{ct: {Schema: b.resolve(spec.resp, t)}}
+	}
+	op.Responses[strconv.Itoa(status)] = res
+	op.Responses["default"] = &Response{Ref: "#/components/responses/Error"}
+
+	oapiPath := ginPath.ReplaceAllString(path, "{$1}")
+	item, ok := b.paths[oapiPath]
+	if !ok {
+		item = &PathItem{}
+		b.paths[oapiPath] = item
+	}
+	(*item)[strings.ToLower(method)] = op
+}
+
+// operationID memakai id dari registry (dengan {Type} diganti nama type)
+// atau, untuk route yang belum didokumentasikan, dibentuk dari method dan
+// path. Id dijamin unik.
+func (b *builder) operationID(method, path, id string, t *entryType) string {
+	if id != "" {
+		id = strings.ReplaceAll(id, "{Type}", t.name)
+	} else {
+		parts := []string{strings.ToLower(method)}
+		for _, seg := range strings.Split(path, "/") {
+			seg = strings.TrimLeft(seg, ":*")
+			if seg != "" && seg != "api" {
+				parts = append(parts, seg)
+			}
+		}
+		id = lowerFirst(TypeName(strings.Join(parts, "-")))
+	}
+	base := id
+	for i := 2; b.opIDs[id]; i++ {
+		id = base + strconv.Itoa(i)
+	}
+	b.opIDs[id] = true
+	return id
+}
+
+var ginPath = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+func pathParams(path string) []Parameter {
+	var out []Parameter
+	for _, m := range ginPath.FindAllStringSubmatch(path, -1) {
+		name := m[1]
+		p := Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}}
+		switch name {
+		case "id", "job", "delivery":
+			p.Schema.Format = "uuid"
+		case "version", "part":
+			p.Schema = &Schema{Type: "integer", Minimum: ptr(1.0)}
+		case "slug":
+			p.Description = "slug content type"
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+func isPerType(path string) bool {
+	return strings.HasPrefix(path, "/api/entries/:slug") || strings.HasPrefix(path, "/api/public/:slug")
+}
+
+// access mengikuti grup & middleware di router: mengembalikan apakah
+// endpoint publik dan role yang dibutuhkan.
+func access(path string) (public bool, roles []string) {
+	switch {
+	case path == "/healthz", path == "/api/openapi.json", path == "/api/graphql",
+		strings.HasPrefix(path, "/api/auth/"),
+		strings.HasPrefix(path, "/api/public/"),
+		strings.HasPrefix(path, "/api/files/"),
+		strings.HasPrefix(path, "/media/"):
+		return true, nil
+	case strings.HasPrefix(path, "/api/admin/"):
+		return false, []string{"Admin"}
+	case strings.HasPrefix(path, "/api/content-types"),
+		strings.HasPrefix(path, "/api/entries/"),
+		strings.HasPrefix(path, "/api/media"):
+		return false, []string{"Editor", "Admin"}
+	}
+	return false, nil
+}
+
+func tagFor(path string) string {
+	seg := strings.Split(strings.TrimPrefix(strings.TrimPrefix(path, "/"), "api/"), "/")
+	if seg[0] == "admin" && len(seg) > 1 {
+		return "admin"
+	}
+	return seg[0]
+}
+
+func (b *builder) tags() []Tag {
+	names := make([]string, 0, len(b.usedTags))
+	for name := range b.usedTags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := make([]Tag, 0, len(names))
+	for _, name := range names {
+		out = append(out, Tag{Name: name})
+	}
+	return out
+}
+
+func methodOrder(m string) int {
+	for i, v := range []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"} {
+		if v == m {
+			return i
+		}
+	}
+	return 99
+}
+
+func jsonContent(s *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: s}}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,430 @@
+package openapi
+
+import (
+	"cms/server/internal/csvimport"
+	"cms/server/internal/mediacheck"
+	"cms/server/internal/model"
+	"cms/server/internal/repository"
+	"cms/server/internal/transfer"
+	"cms/server/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+// route melengkapi satu route gin di dokumen. Route yang terdaftar di
+// router tapi belum ada di registry tetap muncul, hanya tanpa schema.
+type route struct {
+	// id adalah operationId; "{Type}" diganti nama content type.
+	id          string
+	summary     string // "{type}" diganti nama content type
+	description string
+	tag         string
+	query       []Parameter
+	body        *Schema
+	bodyType    string // default application/json
+	status      int    // default 200
+	resp        *Schema
+	respType    string // default application/json
+}
+
+func data(s *Schema) *Schema { return object(map[string]*Schema{"data": s}, "data") }
+
+func page(item *Schema) *Schema {
+	return object(map[string]*Schema{
+		"data":   arrayOf(item),
+		"total":  {Type: "integer", Format: "int64"},
+		"limit":  integerSchema,
+		"offset": integerSchema,
+	}, "data", "total", "limit", "offset")
+}
+
+// cursorPage adalah response list dengan pagination cursor; total hanya
+// ada bila diminta dengan ?total=true.
+func cursorPage(item *Schema) *Schema {
+	cursor := &Schema{Type: "string", Nullable: true}
+	return object(map[string]*Schema{
+		"data":  arrayOf(item),
+		"total": {Type: "integer", Format: "int64"},
+		"limit": integerSchema,
+		"next":  cursor,
+		"prev":  cursor,
+	}, "data", "limit", "next", "prev")
+}
+
+func query(name string, s *Schema, desc string) Parameter {
+	return Parameter{Name: name, In: "query", Schema: s, Description: desc}
+}
+
+var (
+	message = object(map[string]*Schema{"message": stringSchema}, "message")
+
+	limitParam  = query("limit", integerSchema, "")
+	offsetParam = query("offset", integerSchema, "")
+
+	// pageParams untuk list dengan cursorPage; offset tetap diterima untuk
+	// client lama
+	pageParams = []Parameter{
+		query("limit", &Schema{Type: "integer", Minimum: ptr(1.0), Maximum: ptr(float64(repository.MaxPageSize))}, ""),
+		query("cursor", stringSchema, "next atau prev dari halaman sebelumnya"),
+		query("total", booleanSchema, "sertakan jumlah total"),
+	}
+
+	multipartFile = object(map[string]*Schema{"file": binarySchema}, "file")
+
+	authUser = object(map[string]*Schema{
+		"id":    uuidSchema,
+		"name":  stringSchema,
+		"email": stringSchema,
+		"role":  stringSchema,
+	}, "id", "name", "email", "role")
+
+	uploadSession = object(map[string]*Schema{
+		"upload":     of(model.MediaUpload{}),
+		"url":        stringSchema,
+		"fields":     {Type: "object", AdditionalProperties: stringSchema},
+		"part_count": integerSchema,
+		"parts":      arrayOf(of(storage.Part{})),
+	}, "upload")
+
+	mediaWithUsage = object(map[string]*Schema{
+		"data":       of(model.MediaAsset{}),
+		"used_by":    of([]repository.MediaUsage{}),
+		"jobs":       of([]model.MediaJob{}),
+		"public_url": stringSchema,
+	}, "data", "used_by", "jobs", "public_url")
+)
+
+var registry = map[string]route{
+	"GET /healthz": {id: "health", summary: "Health check", tag: "system",
+		resp: object(map[string]*Schema{"status": stringSchema}, "status")},
+	"GET /api/openapi.json": {id: "openAPI", summary: "Dokumen OpenAPI ini", tag: "system",
+		resp: &Schema{Type: "object"}},
+
+	// auth
+	"POST /api/auth/login": {id: "login", summary: "Login dan ambil JWT", tag: "auth",
+		body: of(struct {
+			Email    string `json:"email" binding:"required"`
+			Password string `json:"password" binding:"required"`
+		}{}),
+		resp: object(map[string]*Schema{
+			"user":       authUser,
+			"token":      stringSchema,
+			"token_type": stringSchema,
+		}, "user", "token", "token_type")},
+	"POST /api/auth/register": {id: "register", summary: "Daftar user baru", tag: "auth", status: 201,
+		body: of(struct {
+			Name     string `json:"name" binding:"required"`
+			Email    string `json:"email" binding:"required,email"`
+			Password string `json:"password" binding:"required,min=6"`
+		}{}),
+		resp: object(map[string]*Schema{"user": authUser}, "user")},
+
+	"GET /api/events": {id: "events", summary: "Stream perubahan konten (Server-Sent Events)", tag: "events",
+		description: "Token boleh dikirim lewat ?access_token= karena EventSource tidak bisa mengirim header.",
+		query: []Parameter{
+			query("types", stringSchema, "slug content type, dipisah koma"),
+			query("media", booleanSchema, "ikut kirim event media (default true)"),
+			query("access_token", stringSchema, "JWT"),
+		},
+		resp: stringSchema, respType: "text/event-stream"},
+
+	// content types
+	"GET /api/content-types": {id: "listContentTypes", summary: "Daftar content type", tag: "content-types",
+		resp: data(of([]model.ContentType{}))},
+	"POST /api/content-types": {id: "createContentType", summary: "Buat content type", tag: "content-types", status: 201,
+		body: of(struct {
+			Name string `json:"name" binding:"required"`
+			Slug string `json:"slug" binding:"required"`
+		}{}),
+		resp: data(of(model.ContentType{}))},
+	"GET /api/content-types/:id": {id: "getContentType", summary: "Detail content type beserta field", tag: "content-types",
+		resp: data(of(model.ContentType{}))},
+	"PUT /api/content-types/:id": {id: "updateContentType", summary: "Ubah nama/slug content type", tag: "content-types",
+		body: of(struct {
+			Name string `json:"name"`
+			Slug string `json:"slug"`
+		}{}),
+		resp: data(of(model.ContentType{}))},
+	"DELETE /api/content-types/:id": {id: "deleteContentType", summary: "Hapus content type", tag: "content-types",
+		resp: message},
+	"POST /api/content-types/:id/fields": {id: "addContentField", summary: "Tambah field", tag: "content-types",
+		body: object(map[string]*Schema{
+			"name":    stringSchema,
+			"kind":    {Type: "string", Enum: []string{"text", "string", "number", "bool", "date", "json", "select", "image", "wysiwyg", "relation"}},
+			"options": of(model.FieldOptions{}),
+		}, "name", "kind"),
+		resp: data(of(model.ContentField{}))},
+
+	// entries (dijabarkan per content type)
+	"GET /api/entries/:slug": {id: "list{Type}Entries", summary: "Daftar {type}", tag: "entries",
+		query: pageParams,
+		resp:  cursorPage(typeRef("Entry"))},
+	"POST /api/entries/:slug": {id: "create{Type}Entry", summary: "Buat {type}", tag: "entries", status: 201,
+		body: object(map[string]*Schema{
+			"slug":   stringSchema,
+			"status": {Type: "string", Enum: []string{"draft", "published"}},
+			"data":   typeRef("EntryData"),
+		}, "data"),
+		resp: data(typeRef("Entry"))},
+	"GET /api/entries/:slug/:id": {id: "get{Type}Entry", summary: "Detail {type}", tag: "entries",
+		resp: data(typeRef("Entry"))},
+	"PUT /api/entries/:slug/:id": {id: "update{Type}Entry", summary: "Ubah {type} (membuat versi baru)", tag: "entries",
+		body: object(map[string]*Schema{
+			"status": {Type: "string", Enum: []string{"draft", "published"}},
+			"data":   typeRef("EntryData"),
+		})},
+	"DELETE /api/entries/:slug/:id":                 {id: "delete{Type}Entry", summary: "Hapus {type}", tag: "entries", status: 204},
+	"POST /api/entries/:slug/:id/publish":           {id: "publish{Type}Entry", summary: "Publish {type}", tag: "entries"},
+	"POST /api/entries/:slug/:id/unpublish":         {id: "unpublish{Type}Entry", summary: "Unpublish {type}", tag: "entries"},
+	"POST /api/entries/:slug/:id/rollback/:version": {id: "rollback{Type}Entry", summary: "Kembalikan {type} ke versi lama", tag: "entries"},
+	"POST /api/entries/:slug/:id/preview-token": {id: "preview{Type}Token", summary: "Buat preview token {type}", tag: "entries",
+		body: of(struct {
+			Version    int `json:"version"`
+			TTLSeconds int `json:"ttl_seconds"`
+		}{}),
+		resp: object(map[string]*Schema{
+			"token":      stringSchema,
+			"expires_at": {Type: "string", Format: "date-time"},
+			"url":        stringSchema,
+		}, "token", "expires_at", "url")},
+	"POST /api/entries/:slug/bulk": {id: "bulk{Type}Entries", summary: "Operasi massal {type}", tag: "entries",
+		body: object(map[string]*Schema{
+			"action": {Type: "string", Enum: []string{repository.BulkPublish, repository.BulkUnpublish, repository.BulkDelete, repository.BulkStatus}},
+			"ids":    arrayOf(uuidSchema),
+			"status": {Type: "string", Enum: []string{"draft", "published"}},
+			"atomic": booleanSchema,
+		}, "action", "ids"),
+		resp: object(map[string]*Schema{
+			"results":   of([]repository.BulkResult{}),
+			"succeeded": integerSchema,
+			"failed":    integerSchema,
+		}, "results", "succeeded", "failed")},
+	"POST /api/entries/:slug/import": {id: "import{Type}CSV", summary: "Import {type} dari CSV", tag: "entries",
+		description: "Import besar (atau async=true) berjalan di background dan mengembalikan 202 dengan ImportJob.",
+		body: object(map[string]*Schema{
+			"file":          binarySchema,
+			"mapping":       {Type: "string", Description: "JSON kolom -> field"},
+			"slug_column":   stringSchema,
+			"status_column": stringSchema,
+			"mode":          {Type: "string", Enum: []string{csvimport.ModeCreate, csvimport.ModeUpsert}},
+			"async":         booleanSchema,
+		}, "file"),
+		bodyType: "multipart/form-data",
+		resp: data(object(map[string]*Schema{
+			"results":   of([]csvimport.RowResult{}),
+			"total":     integerSchema,
+			"succeeded": integerSchema,
+			"failed":    integerSchema,
+		}, "results", "total", "succeeded", "failed"))},
+	"GET /api/entries/:slug/import/:job": {id: "get{Type}ImportJob", summary: "Status import CSV {type}", tag: "entries",
+		resp: data(of(model.ImportJob{}))},
+
+	// public (dijabarkan per content type)
+	"GET /api/public/:slug": {id: "listPublished{Type}", summary: "Daftar {type} yang sudah publish", tag: "public",
+		query: append([]Parameter{query("sort", &Schema{Type: "string", Enum: []string{"-published_at", "published_at"}}, "")}, pageParams...),
+		resp:  cursorPage(typeRef("Entry"))},
+	"GET /api/public/:slug/:id": {id: "getPublished{Type}", summary: "Detail {type} yang sudah publish", tag: "public",
+		query: []Parameter{query("preview_token", stringSchema, "tampilkan draft; bisa juga lewat header X-Preview-Token")},
+		resp:  data(typeRef("Entry"))},
+
+	"GET /api/graphql": {id: "graphQLGet", summary: "Query GraphQL (baca saja)", tag: "graphql",
+		query: []Parameter{
+			query("query", stringSchema, ""),
+			query("variables", stringSchema, "object JSON"),
+			query("operationName", stringSchema, ""),
+		},
+		resp: &Schema{Type: "object"}},
+	"POST /api/graphql": {id: "graphQL", summary: "Query GraphQL (baca saja)", tag: "graphql",
+		body: object(map[string]*Schema{
+			"query":         stringSchema,
+			"variables":     {Type: "object", AdditionalProperties: &Schema{}},
+			"operationName": stringSchema,
+		}, "query"),
+		resp: &Schema{Type: "object"}},
+
+	// media
+	"GET /api/media": {id: "listMedia", summary: "Daftar media", tag: "media",
+		query: append([]Parameter{
+			query("q", stringSchema, "cari di filename, alt text & caption"),
+			query("tags", stringSchema, "dipisah koma; asset harus punya semua tag"),
+			query("mime", stringSchema, "mis. image/png atau image/*"),
+			query("folder", stringSchema, "id folder atau root"),
+			query("from", stringSchema, ""),
+			query("to", stringSchema, ""),
+		}, pageParams...),
+		resp: cursorPage(of(model.MediaAsset{}))},
+	"POST /api/media": {id: "uploadMedia", summary: "Upload file", tag: "media",
+		body: multipartFile, bodyType: "multipart/form-data",
+		resp: object(map[string]*Schema{
+			"id":       uuidSchema,
+			"filename": stringSchema,
+			"url":      stringSchema,
+			"checksum": stringSchema,
+			"reused":   booleanSchema,
+			"meta":     {Type: "object", AdditionalProperties: &Schema{}},
+		}, "id", "filename", "url", "checksum", "reused")},
+	"GET /api/media/:id": {id: "getMedia", summary: "Detail media beserta pemakaian dan job", tag: "media",
+		resp: mediaWithUsage},
+	"PUT /api/media/:id": {id: "updateMedia", summary: "Ubah metadata media", tag: "media",
+		body: object(map[string]*Schema{
+			"alt_text":   stringSchema,
+			"caption":    stringSchema,
+			"credit":     stringSchema,
+			"tags":       arrayOf(stringSchema),
+			"visibility": {Type: "string", Enum: []string{model.MediaVisibilityAuto, model.MediaVisibilityPublic, model.MediaVisibilityPrivate}},
+		}),
+		resp: data(of(model.MediaAsset{}))},
+	"DELETE /api/media/:id": {id: "deleteMedia", summary: "Hapus media", tag: "media",
+		query: []Parameter{query("force", booleanSchema, "hapus walau masih dipakai entry")},
+		resp: object(map[string]*Schema{
+			"message": stringSchema,
+			"used_by": of([]repository.MediaUsage{}),
+		}, "message")},
+	"GET /api/media/tags": {id: "listMediaTags", summary: "Semua tag media", tag: "media",
+		resp: data(of([]repository.MediaTag{}))},
+	"POST /api/media/move": {id: "moveMedia", summary: "Pindahkan media ke folder", tag: "media",
+		body: object(map[string]*Schema{
+			"ids":       arrayOf(uuidSchema),
+			"folder_id": {Type: "string", Format: "uuid", Nullable: true},
+		}, "ids"),
+		resp: data(object(map[string]*Schema{"moved": {Type: "integer", Format: "int64"}}, "moved"))},
+	"GET /api/media/preview/:id": {id: "previewMedia", summary: "Signed URL sementara", tag: "media",
+		resp: object(map[string]*Schema{"preview_url": stringSchema}, "preview_url")},
+	"GET /api/media/:id/render": {id: "renderMedia", summary: "Rendition gambar", tag: "media",
+		query: []Parameter{
+			query("w", integerSchema, ""),
+			query("h", integerSchema, ""),
+			query("fit", &Schema{Type: "string", Enum: []string{"cover", "contain", "fill"}}, ""),
+			query("format", &Schema{Type: "string", Enum: []string{"jpeg", "png", "webp"}}, ""),
+			query("q", integerSchema, "kualitas 1-100"),
+		},
+		resp: binarySchema, respType: "image/*"},
+	"GET /api/media/:id/poster": {id: "getMediaPoster", summary: "Poster video/PDF", tag: "media",
+		resp: binarySchema, respType: "image/jpeg"},
+	"POST /api/media/:id/process": {id: "processMedia", summary: "Antrikan ulang ekstraksi metadata", tag: "media", status: 202,
+		resp: data(of(model.MediaJob{}))},
+
+	"POST /api/media/uploads": {id: "createUpload", summary: "Mulai upload besar (multipart/presigned)", tag: "media", status: 201,
+		body: object(map[string]*Schema{
+			"filename": stringSchema,
+			"mime":     stringSchema,
+			"size":     {Type: "integer", Format: "int64"},
+			"method":   {Type: "string", Enum: []string{model.UploadMethodMultipart, model.UploadMethodPresigned}},
+		}, "filename", "mime", "size"),
+		resp: data(uploadSession)},
+	"GET /api/media/uploads/:id": {id: "getUpload", summary: "Status sesi upload", tag: "media",
+		resp: data(uploadSession)},
+	"PUT /api/media/uploads/:id/parts/:part": {id: "putUploadPart", summary: "Kirim satu part", tag: "media",
+		body: binarySchema, bodyType: "application/octet-stream",
+		resp: data(of(storage.Part{}))},
+	"POST /api/media/uploads/:id/complete": {id: "completeUpload", summary: "Selesaikan upload", tag: "media",
+		resp: object(map[string]*Schema{
+			"data":   of(model.MediaAsset{}),
+			"reused": booleanSchema,
+		}, "data", "reused")},
+	"DELETE /api/media/uploads/:id": {id: "abortUpload", summary: "Batalkan upload", tag: "media",
+		resp: message},
+
+	"GET /api/media/folders": {id: "listMediaFolders", summary: "Daftar folder", tag: "media",
+		query: []Parameter{query("parent", uuidSchema, "tanpa parent: folder di root")},
+		resp:  data(of([]model.MediaFolder{}))},
+	"POST /api/media/folders": {id: "createMediaFolder", summary: "Buat folder", tag: "media", status: 201,
+		body: of(struct {
+			Name     string     `json:"name" binding:"required"`
+			ParentID *uuid.UUID `json:"parent_id"`
+		}{}),
+		resp: data(of(model.MediaFolder{}))},
+	"PUT /api/media/folders/:id": {id: "updateMediaFolder", summary: "Ganti nama/pindahkan folder", tag: "media",
+		body: of(struct {
+			Name     string     `json:"name" binding:"required"`
+			ParentID *uuid.UUID `json:"parent_id"`
+		}{}),
+		resp: data(of(model.MediaFolder{}))},
+	"DELETE /api/media/folders/:id": {id: "deleteMediaFolder", summary: "Hapus folder kosong", tag: "media",
+		resp: message},
+
+	"GET /media/:id/:filename": {id: "deliverMedia", summary: "File media publik", tag: "delivery",
+		resp: binarySchema, respType: "application/octet-stream"},
+	"HEAD /media/:id/:filename": {id: "headMedia", summary: "Header file media publik", tag: "delivery"},
+	"GET /api/files/*name": {id: "getFile", summary: "File dari storage lokal lewat signed URL", tag: "delivery",
+		query: []Parameter{
+			query("expires", integerSchema, ""),
+			query("signature", stringSchema, ""),
+		},
+		resp: binarySchema, respType: "application/octet-stream"},
+
+	// admin
+	"GET /api/admin/roles": {id: "listRoles", summary: "Daftar role", tag: "admin",
+		resp: of([]model.Role{})},
+	"POST /api/admin/roles": {id: "createRole", summary: "Buat role", tag: "admin", status: 201,
+		body: of(struct {
+			Name string `json:"name" binding:"required"`
+		}{}),
+		resp: message},
+	"GET /api/admin/users": {id: "listUsers", summary: "Daftar user beserta role", tag: "admin",
+		resp: data(of([]repository.UserWithRoles{}))},
+	"GET /api/admin/users/:id/roles": {id: "getUserRoles", summary: "Role milik user", tag: "admin",
+		resp: object(map[string]*Schema{"roles": of([]model.Role{})}, "roles")},
+	"POST /api/admin/users/:id/roles": {id: "setUserRoles", summary: "Atur role user", tag: "admin",
+		body: object(map[string]*Schema{"roles": arrayOf(integerSchema)}, "roles"),
+		resp: message},
+	"GET /api/admin/export": {id: "exportContent", summary: "Export content type, entry & media (zip)", tag: "admin",
+		query: []Parameter{
+			query("types", stringSchema, "slug dipisah koma; kosong berarti semua"),
+			query("versions", booleanSchema, ""),
+			query("media", booleanSchema, ""),
+		},
+		resp: binarySchema, respType: "application/zip"},
+	"POST /api/admin/import": {id: "importContent", summary: "Import arsip export", tag: "admin",
+		query: []Parameter{
+			query("strategy", &Schema{Type: "string", Enum: []string{transfer.StrategySkip, transfer.StrategyOverwrite, transfer.StrategyRename}}, ""),
+			query("dry_run", booleanSchema, ""),
+		},
+		body: multipartFile, bodyType: "multipart/form-data",
+		resp: data(of(transfer.Report{}))},
+	"GET /api/admin/audit": {id: "listAuditLogs", summary: "Audit log, terbaru lebih dulu", tag: "admin",
+		query: append([]Parameter{
+			query("actor", uuidSchema, "id user"),
+			query("action", stringSchema, "mis. delete_entry"),
+			query("resource", stringSchema, "mis. entry:<id>"),
+		}, pageParams...),
+		resp: cursorPage(of(model.AuditLog{}))},
+	"POST /api/admin/media/reconcile": {id: "reconcileMedia", summary: "Cari object yatim & file yang hilang", tag: "admin",
+		query: []Parameter{
+			query("delete", booleanSchema, "hapus object yatim"),
+			query("grace", stringSchema, "mis. 1h"),
+		},
+		resp: data(of(mediacheck.Report{}))},
+
+	"GET /api/admin/webhooks": {id: "listWebhooks", summary: "Daftar webhook", tag: "admin",
+		resp: object(map[string]*Schema{
+			"data":   of([]model.Webhook{}),
+			"events": arrayOf(stringSchema),
+		}, "data", "events")},
+	"POST /api/admin/webhooks": {id: "createWebhook", summary: "Buat webhook", tag: "admin", status: 201,
+		body: webhookInput,
+		resp: object(map[string]*Schema{
+			"data":   of(model.Webhook{}),
+			"secret": stringSchema,
+		}, "data", "secret")},
+	"GET /api/admin/webhooks/:id": {id: "getWebhook", summary: "Detail webhook", tag: "admin",
+		resp: data(of(model.Webhook{}))},
+	"PUT /api/admin/webhooks/:id": {id: "updateWebhook", summary: "Ubah webhook", tag: "admin",
+		body: webhookInput,
+		resp: data(of(model.Webhook{}))},
+	"DELETE /api/admin/webhooks/:id": {id: "deleteWebhook", summary: "Hapus webhook", tag: "admin",
+		resp: message},
+	"GET /api/admin/webhooks/:id/deliveries": {id: "listWebhookDeliveries", summary: "Riwayat pengiriman webhook", tag: "admin",
+		query: []Parameter{limitParam, offsetParam},
+		resp:  page(of(model.WebhookDelivery{}))},
+	"POST /api/admin/webhooks/:id/deliveries/:delivery/redeliver": {id: "redeliverWebhook", summary: "Kirim ulang event", tag: "admin", status: 202,
+		resp: data(of(model.WebhookDelivery{}))},
+}
+
+var webhookInput = of(struct {
+	Name   string   `json:"name" binding:"required"`
+	URL    string   `json:"url" binding:"required"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events" binding:"required"`
+	Active *bool    `json:"active"`
+}{})
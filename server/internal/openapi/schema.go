@@ -0,0 +1,324 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"cms/server/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+
+	// goType diisi lewat of(); schema-nya dibuat dari struct Go saat
+	// dokumen dibangun.
+	goType reflect.Type
+}
+
+// of menandai schema yang dibuat dari tipe v. Struct bernama menjadi
+// component, struct anonim (body request inline) ditulis di tempat.
+func of(v any) *Schema { return &Schema{goType: reflect.TypeOf(v)} }
+
+func schemaRef(name string) string { return "#/components/schemas/" + name }
+
+// typeRef merujuk schema per content type; "{Type}" diganti nama type
+// (atau kosong untuk path generik), mis. "{Type}Entry" -> "PostEntry".
+func typeRef(name string) *Schema { return &Schema{Ref: schemaRef("{Type}" + name)} }
+
+func object(props map[string]*Schema, required ...string) *Schema {
+	return &Schema{Type: "object", Properties: props, Required: required}
+}
+
+func arrayOf(s *Schema) *Schema { return &Schema{Type: "array", Items: s} }
+
+var (
+	stringSchema  = &Schema{Type: "string"}
+	integerSchema = &Schema{Type: "integer"}
+	booleanSchema = &Schema{Type: "boolean"}
+	uuidSchema    = &Schema{Type: "string", Format: "uuid"}
+	binarySchema  = &Schema{Type: "string", Format: "binary"}
+)
+
+// entryType adalah content type beserta nama schema-nya di dokumen.
+type entryType struct {
+	ct   *model.ContentType
+	name string
+}
+
+type builder struct {
+	schemas  map[string]*Schema
+	named    map[reflect.Type]string
+	paths    map[string]*PathItem
+	opIDs    map[string]bool
+	usedTags map[string]bool
+	types    []*entryType
+	generic  *entryType
+}
+
+func newBuilder() *builder {
+	b := &builder{
+		schemas:  map[string]*Schema{},
+		named:    map[reflect.Type]string{},
+		paths:    map[string]*PathItem{},
+		opIDs:    map[string]bool{},
+		usedTags: map[string]bool{},
+		generic:  &entryType{ct: &model.ContentType{Name: "entry"}},
+	}
+	b.schemas["Error"] = object(map[string]*Schema{"error": stringSchema}, "error")
+	b.schemas["EntryData"] = &Schema{Type: "object", AdditionalProperties: &Schema{}}
+	b.schemas["Entry"] = b.entrySchema("EntryData")
+	return b
+}
+
+// contentTypes membuat schema <Type>EntryData dan <Type>Entry untuk setiap
+// content type.
+func (b *builder) contentTypes(list []model.ContentType) {
+	sorted := append([]model.ContentType(nil), list...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Slug < sorted[j].Slug })
+
+	for i := range sorted {
+		ct := &sorted[i]
+		name := TypeName(ct.Slug)
+		for n := 2; b.schemas[name+"Entry"] != nil || b.schemas[name+"EntryData"] != nil; n++ {
+			name = TypeName(ct.Slug) + strconv.Itoa(n)
+		}
+		b.schemas[name+"EntryData"] = DataSchema(ct.Fields)
+		b.schemas[name+"Entry"] = b.entrySchema(name + "EntryData")
+		b.types = append(b.types, &entryType{ct: ct, name: name})
+	}
+}
+
+// entrySchema adalah model.Entry dengan Data merujuk schema data.
+func (b *builder) entrySchema(data string) *Schema {
+	s := b.structSchema(reflect.TypeOf(model.Entry{}), true)
+	s.Properties["Data"] = &Schema{Ref: schemaRef(data)}
+	return s
+}
+
+// DataSchema membuat schema object untuk Entry.Data dari field content
+// type. Field dengan options.required masuk daftar required.
+func DataSchema(fields []model.ContentField) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, f := range fields {
+		s.Properties[f.Name] = FieldSchema(f)
+		if f.ParseOptions().Required {
+			s.Required = append(s.Required, f.Name)
+		}
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+// FieldSchema memetakan ContentField.Kind ke schema JSON, sama dengan
+// nilai yang diterima csvimport.ParseValue.
+func FieldSchema(f model.ContentField) *Schema {
+	opts := f.ParseOptions()
+	var s *Schema
+	switch f.Kind {
+	case "text", "string", "wysiwyg":
+		s = &Schema{Type: "string"}
+	case "number":
+		s = &Schema{Type: "number"}
+	case "bool":
+		s = &Schema{Type: "boolean"}
+	case "date":
+		s = &Schema{Type: "string", Description: "tanggal (YYYY-MM-DD) atau waktu RFC 3339"}
+	case "select":
+		s = &Schema{Type: "string", Enum: opts.Choices}
+	case "image":
+		s = &Schema{Type: "string", Format: "uuid", Description: "id media"}
+	case "relation":
+		s = &Schema{Type: "string", Format: "uuid", Description: "id entry " + opts.Target}
+	default: // json dan kind yang tidak dikenal: nilai JSON apa pun
+		return &Schema{}
+	}
+	if opts.Multiple && (f.Kind == "select" || f.Kind == "image" || f.Kind == "relation") {
+		desc := s.Description
+		s.Description = ""
+		return &Schema{Type: "array", Items: s, Description: desc}
+	}
+	return s
+}
+
+// resolve menyalin schema dari registry: tipe Go diubah menjadi schema
+// dan "{Type}" di $ref diganti nama type t.
+func (b *builder) resolve(s *Schema, t *entryType) *Schema {
+	if s == nil {
+		return nil
+	}
+	if s.goType != nil {
+		return b.typeSchema(s.goType)
+	}
+	out := *s
+	out.Ref = strings.ReplaceAll(s.Ref, "{Type}", t.name)
+	out.Items = b.resolve(s.Items, t)
+	out.AdditionalProperties = b.resolve(s.AdditionalProperties, t)
+	if s.Properties != nil {
+		// urut key agar nama component yang bentrok selalu sama
+		keys := make([]string, 0, len(s.Properties))
+		for k := range s.Properties {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out.Properties = make(map[string]*Schema, len(keys))
+		for _, k := range keys {
+			out.Properties[k] = b.resolve(s.Properties[k], t)
+		}
+	}
+	return &out
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	uuidType = reflect.TypeOf(uuid.UUID{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+	pqType   = reflect.TypeOf(pq.StringArray{})
+)
+
+// typeSchema membuat schema dari tipe Go mengikuti encoding/json: model
+// tanpa tag json memakai nama field Go sebagai key.
+func (b *builder) typeSchema(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case rawType:
+		return &Schema{}
+	case pqType:
+		return arrayOf(&Schema{Type: "string"})
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := b.typeSchema(t.Elem())
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return arrayOf(b.typeSchema(t.Elem()))
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t, false)
+		}
+		return &Schema{Ref: schemaRef(b.component(t))}
+	}
+	return &Schema{}
+}
+
+// component mendaftarkan struct bernama sebagai component. Nama yang sudah
+// dipakai tipe lain diberi awalan nama package (transfer.Report ->
+// TransferReport).
+func (b *builder) component(t reflect.Type) string {
+	if name, ok := b.named[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if _, taken := b.schemas[name]; taken {
+		pkg := t.PkgPath()
+		name = TypeName(pkg[strings.LastIndex(pkg, "/")+1:]) + name
+	}
+	b.named[t] = name
+	b.schemas[name] = &Schema{Type: "object"} // placeholder untuk tipe rekursif
+	b.schemas[name] = b.structSchema(t, true)
+	return name
+}
+
+// structSchema membuat schema object dari field struct. Field body request
+// wajib bila tag binding berisi required; untuk model respons (component)
+// semua field tanpa omitempty selalu ada dan ditandai required.
+func (b *builder) structSchema(t reflect.Type, response bool) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			embedded := b.structSchema(f.Type, response)
+			for k, v := range embedded.Properties {
+				s.Properties[k] = v
+			}
+			s.Required = append(s.Required, embedded.Required...)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = b.typeSchema(f.Type)
+
+		omitempty := strings.Contains(","+opts+",", ",omitempty,")
+		binding := strings.Split(f.Tag.Get("binding"), ",")
+		if (response && !omitempty) || binding[0] == "required" {
+			s.Required = append(s.Required, name)
+		}
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+// TypeName mengubah slug menjadi nama tipe PascalCase ("blog-post" ->
+// "BlogPost"). Nama yang diawali angka diberi awalan "T".
+func TypeName(slug string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range slug {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) || r > unicode.MaxASCII {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	name := sb.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "T" + name
+	}
+	return name
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
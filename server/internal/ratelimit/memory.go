@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval adalah jarak minimum antar pembersihan bucket yang sudah
+// penuh kembali (dan karenanya sama dengan bucket baru).
+const sweepInterval = time.Minute
+
+// Memory menyimpan bucket di memori proses. Dengan beberapa instance API
+// setiap instance menghitung sendiri-sendiri.
+type Memory struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time // saat bucket penuh lagi bila tidak dipakai
+}
+
+func NewMemory() *Memory {
+	return &Memory{buckets: map[string]*bucket{}, lastSweep: time.Now()}
+}
+
+func (m *Memory) Ping(context.Context) error { return nil }
+
+func (m *Memory) Allow(_ context.Context, key string, p Policy) (Result, error) {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.Sub(m.lastSweep) > sweepInterval {
+		for k, b := range m.buckets {
+			if now.After(b.full) {
+				delete(m.buckets, k)
+			}
+		}
+		m.lastSweep = now
+	}
+
+	rate := p.perMilli()
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(p.Limit), updated: now}
+		m.buckets[key] = b
+	}
+	elapsed := float64(now.Sub(b.updated)) / float64(time.Millisecond)
+	b.tokens = math.Min(float64(p.Limit), b.tokens+elapsed*rate)
+	b.updated = now
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	res := result(p, b.tokens, allowed)
+	b.full = now.Add(res.Reset)
+	return res, nil
+}
@@ -0,0 +1,45 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryRefillAndRetryAfter(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	p := Policy{Name: "test", Limit: 2, Window: 200 * time.Millisecond}
+
+	for i := 0; i < 2; i++ {
+		res, err := m.Allow(ctx, "client", p)
+		if err != nil || !res.Allowed {
+			t.Fatalf("request %d: Allow = %+v, %v; want allowed", i+1, res, err)
+		}
+		if res.Limit != 2 || res.Remaining != 1-i {
+			t.Errorf("request %d: Limit/Remaining = %d/%d, want 2/%d", i+1, res.Limit, res.Remaining, 1-i)
+		}
+	}
+
+	res, _ := m.Allow(ctx, "client", p)
+	if res.Allowed {
+		t.Fatal("third request allowed, want bucket empty")
+	}
+	// satu token terisi setiap 100ms
+	if res.RetryAfter <= 0 || res.RetryAfter > 100*time.Millisecond {
+		t.Errorf("RetryAfter = %v, want (0, 100ms]", res.RetryAfter)
+	}
+	if res.Reset <= 100*time.Millisecond || res.Reset > 200*time.Millisecond {
+		t.Errorf("Reset = %v, want (100ms, 200ms]", res.Reset)
+	}
+
+	// bucket lain tidak terpengaruh
+	if res, _ := m.Allow(ctx, "other", p); !res.Allowed {
+		t.Error("other client was limited")
+	}
+
+	time.Sleep(res.RetryAfter + 10*time.Millisecond)
+	if res, _ := m.Allow(ctx, "client", p); !res.Allowed {
+		t.Errorf("after RetryAfter: Allow = %+v, want allowed", res)
+	}
+}
@@ -0,0 +1,164 @@
+// Package ratelimit membatasi jumlah request per client dengan token
+// bucket: setiap client punya bucket berisi Limit token yang terisi ulang
+// merata selama Window, dan setiap request mengambil satu token.
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DriverMemory = "memory"
+	DriverRedis  = "redis"
+)
+
+// Policy adalah batas satu kelompok route, mis. "10/m" untuk /api/auth.
+type Policy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// Off melaporkan apakah policy tidak membatasi apa pun.
+func (p Policy) Off() bool { return p.Limit <= 0 }
+
+// perMilli adalah kecepatan pengisian bucket dalam token per milidetik.
+func (p Policy) perMilli() float64 {
+	return float64(p.Limit) / float64(p.Window.Milliseconds())
+}
+
+func (p Policy) String() string {
+	if p.Off() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", p.Limit, p.Window)
+}
+
+// ParsePolicy membaca "<jumlah>/<window>", dengan window s, m, h atau
+// durasi Go (mis. "100/m", "5/10s", "1000/1h"). "off" dan string kosong
+// berarti tanpa batas.
+func ParsePolicy(name, s string) (Policy, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "off" {
+		return Policy{Name: name}, nil
+	}
+	count, window, ok := strings.Cut(s, "/")
+	if !ok {
+		return Policy{}, fmt.Errorf("policy %s %q: expected <limit>/<window>", name, s)
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || limit <= 0 {
+		return Policy{}, fmt.Errorf("policy %s %q: invalid limit", name, s)
+	}
+	window = strings.TrimSpace(window)
+	switch window {
+	case "s", "m", "h":
+		window = "1" + window
+	}
+	d, err := time.ParseDuration(window)
+	if err != nil || d < time.Millisecond {
+		return Policy{}, fmt.Errorf("policy %s %q: invalid window", name, s)
+	}
+	return Policy{Name: name, Limit: limit, Window: d}, nil
+}
+
+// Keys memetakan API key ke policy (kuota) client pemiliknya.
+type Keys map[string]Policy
+
+// ParseKeys membaca daftar "<key>=<policy>" dipisah koma, mis.
+// "k3y-web=600/m,k3y-batch=10000/h".
+func ParseKeys(s string) (Keys, error) {
+	keys := Keys{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, spec, ok := strings.Cut(item, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("api key %q: expected <key>=<policy>", item)
+		}
+		p, err := ParsePolicy("apikey", spec)
+		if err != nil {
+			return nil, err
+		}
+		keys[key] = p
+	}
+	return keys, nil
+}
+
+// KeyID adalah identitas API key di bucket dan log; key aslinya tidak
+// pernah disimpan.
+func KeyID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Result adalah hasil satu pengambilan token.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset adalah waktu sampai bucket penuh kembali.
+	Reset time.Duration
+	// RetryAfter adalah waktu sampai satu token tersedia; nol bila Allowed.
+	RetryAfter time.Duration
+}
+
+// Store menyimpan isi bucket.
+type Store interface {
+	// Allow mengambil satu token dari bucket key di bawah policy p.
+	Allow(ctx context.Context, key string, p Policy) (Result, error)
+	Ping(ctx context.Context) error
+}
+
+type Config struct {
+	// Driver: memory (default) atau redis.
+	Driver string
+
+	// Redis
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+}
+
+// New membuat store sesuai cfg.Driver. Koneksi ke Redis tidak dicek di
+// sini; gunakan Ping.
+func New(cfg Config) (Store, error) {
+	switch strings.ToLower(cfg.Driver) {
+	case "", DriverMemory:
+		return NewMemory(), nil
+	case DriverRedis:
+		if cfg.RedisAddr == "" {
+			return nil, fmt.Errorf("rate limit driver redis membutuhkan REDIS_ADDR")
+		}
+		return NewRedis(cfg), nil
+	}
+	return nil, fmt.Errorf("unknown rate limit driver %q (expected memory or redis)", cfg.Driver)
+}
+
+// result menghitung header dari isi bucket setelah pengambilan token.
+func result(p Policy, tokens float64, allowed bool) Result {
+	rate := p.perMilli()
+	res := Result{
+		Allowed:   allowed,
+		Limit:     p.Limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     millis((float64(p.Limit) - tokens) / rate),
+	}
+	if !allowed {
+		res.RetryAfter = millis((1 - tokens) / rate)
+	}
+	return res
+}
+
+func millis(ms float64) time.Duration {
+	return time.Duration(math.Ceil(ms)) * time.Millisecond
+}
@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Policy
+		wantErr bool
+	}{
+		{"", Policy{Name: "api"}, false},
+		{"off", Policy{Name: "api"}, false},
+		{"100/m", Policy{Name: "api", Limit: 100, Window: time.Minute}, false},
+		{" 5 / 10s ", Policy{Name: "api", Limit: 5, Window: 10 * time.Second}, false},
+		{"1000/1h", Policy{Name: "api", Limit: 1000, Window: time.Hour}, false},
+		{"3/s", Policy{Name: "api", Limit: 3, Window: time.Second}, false},
+		{"100", Policy{}, true},
+		{"0/m", Policy{}, true},
+		{"-1/m", Policy{}, true},
+		{"x/m", Policy{}, true},
+		{"10/day", Policy{}, true},
+		{"10/1us", Policy{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePolicy("api", tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePolicy(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePolicy(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+	if p, _ := ParsePolicy("api", "off"); !p.Off() {
+		t.Errorf("off policy is not Off()")
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys(" k3y-web=600/m, k3y-batch=10000/h ,")
+	if err != nil {
+		t.Fatalf("ParseKeys: %v", err)
+	}
+	want := Keys{
+		"k3y-web":   {Name: "apikey", Limit: 600, Window: time.Minute},
+		"k3y-batch": {Name: "apikey", Limit: 10000, Window: time.Hour},
+	}
+	if len(keys) != len(want) {
+		t.Fatalf("ParseKeys = %v, want %v", keys, want)
+	}
+	for k, p := range want {
+		if keys[k] != p {
+			t.Errorf("keys[%q] = %+v, want %+v", k, keys[k], p)
+		}
+	}
+
+	if keys, err := ParseKeys(""); err != nil || len(keys) != 0 {
+		t.Errorf("ParseKeys(\"\") = %v, %v; want empty", keys, err)
+	}
+	for _, spec := range []string{"nokey", "=10/m", "k=10", "k=ten/m"} {
+		if _, err := ParseKeys(spec); err == nil {
+			t.Errorf("ParseKeys(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestKeyIDHidesKey(t *testing.T) {
+	id := KeyID("secret-key")
+	if id == "secret-key" || len(id) != 16 || id != KeyID("secret-key") || id == KeyID("other-key") {
+		t.Errorf("KeyID = %q, want a stable 16 char hash", id)
+	}
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisPrefix = "cms:ratelimit:"
+
+// Redis menyimpan bucket di Redis sehingga batas berlaku gabungan untuk
+// semua instance API. Selama Redis tidak bisa dihubungi, bucket dihitung
+// di memori instance masing-masing agar API tetap melayani request.
+type Redis struct {
+	client   *redis.Client
+	fallback *Memory
+	// lastError (unix nano) membatasi log error Redis satu per menit
+	lastError atomic.Int64
+}
+
+func NewRedis(cfg Config) *Redis {
+	return &Redis{
+		client: redis.NewClient(&redis.Options{
+			Addr:     cfg.RedisAddr,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+		}),
+		fallback: NewMemory(),
+	}
+}
+
+func (r *Redis) Ping(ctx context.Context) error { return r.client.Ping(ctx).Err() }
+
+// allowScript menjalankan token bucket secara atomik memakai jam Redis,
+// sehingga selisih jam antar instance tidak berpengaruh. Bucket dihapus
+// Redis saat sudah penuh kembali.
+var allowScript = redis.NewScript(`
+redis.replicate_commands()
+local limit = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + tonumber(t[2]) / 1000
+local b = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(b[1]) or limit
+local ts = tonumber(b[2]) or now
+tokens = math.min(limit, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((limit - tokens) / rate) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+func (r *Redis) Allow(ctx context.Context, key string, p Policy) (Result, error) {
+	rate := strconv.FormatFloat(p.perMilli(), 'g', -1, 64)
+	out, err := allowScript.Run(ctx, r.client, []string{redisPrefix + key}, p.Limit, rate).Slice()
+	if err == nil && len(out) == 2 {
+		allowed, _ := out[0].(int64)
+		s, _ := out[1].(string)
+		if tokens, perr := strconv.ParseFloat(s, 64); perr == nil {
+			return result(p, tokens, allowed == 1), nil
+		}
+	}
+	if err != nil && ctx.Err() == nil {
+		now := time.Now().UnixNano()
+		if last := r.lastError.Load(); now-last > int64(time.Minute) && r.lastError.CompareAndSwap(last, now) {
+			slog.WarnContext(ctx, "ratelimit: redis gagal, memakai bucket di memori", "error", err)
+		}
+	}
+	return r.fallback.Allow(ctx, key, p)
+}
@@ -2,14 +2,26 @@ package repository
 
 import (
 	"context"
+	"strconv"
+	"time"
 
 	"cms/server/internal/model"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type AuditRepository interface {
 	Log(ctx context.Context, log *model.AuditLog) error
+	// List mengembalikan log terbaru lebih dulu.
+	List(ctx context.Context, f AuditFilter, page Page) ([]model.AuditLog, PageInfo, error)
+}
+
+// AuditFilter menyaring audit log; field kosong tidak menyaring.
+type AuditFilter struct {
+	ActorID  *uuid.UUID
+	Action   string
+	Resource string
 }
 
 type auditRepository struct {
@@ -23,3 +35,19 @@ func NewAuditRepository(db *gorm.DB) AuditRepository {
 func (r *auditRepository) Log(ctx context.Context, log *model.AuditLog) error {
 	return r.db.WithContext(ctx).Create(log).Error
 }
+
+func (r *auditRepository) List(ctx context.Context, f AuditFilter, page Page) ([]model.AuditLog, PageInfo, error) {
+	q := r.db.WithContext(ctx).Model(&model.AuditLog{})
+	if f.ActorID != nil {
+		q = q.Where("actor_id = ?", *f.ActorID)
+	}
+	if f.Action != "" {
+		q = q.Where("action = ?", f.Action)
+	}
+	if f.Resource != "" {
+		q = q.Where("resource = ?", f.Resource)
+	}
+	// id bertambah terus, jadi cukup id sebagai urutan
+	return paginate(q, page, keyset{sort: "-id", desc: true},
+		func(l *model.AuditLog) (time.Time, string) { return time.Time{}, strconv.FormatUint(l.ID, 10) })
+}
@@ -13,21 +13,41 @@ type ContentTypeRepository interface {
 	Create(ctx context.Context, ct *model.ContentType) error
 	List(ctx context.Context) ([]model.ContentType, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*model.ContentType, error)
+	GetBySlug(ctx context.Context, slug string) (*model.ContentType, error)
 	Update(ctx context.Context, id uuid.UUID, name, slug string) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	AddField(ctx context.Context, field *model.ContentField) error
 }
 
 type contentTypeRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	events EventPublisher
 }
 
-func NewContentTypeRepository(db *gorm.DB) ContentTypeRepository {
-	return &contentTypeRepository{db: db}
+func NewContentTypeRepository(db *gorm.DB, events EventPublisher) ContentTypeRepository {
+	return &contentTypeRepository{db: db, events: events}
+}
+
+// changed menulis event content_type.changed ke outbox lewat tx, transaksi
+// yang sama dengan perubahannya.
+func (r *contentTypeRepository) changed(ctx context.Context, tx *gorm.DB, action string, id uuid.UUID, slug string) error {
+	if r.events == nil {
+		return nil
+	}
+	return NewEventPublisher(tx).Publish(ctx, model.EventContentTypeChanged, map[string]any{
+		"action": action,
+		"id":     id,
+		"slug":   slug,
+	})
 }
 
 func (r *contentTypeRepository) Create(ctx context.Context, ct *model.ContentType) error {
-	return r.db.WithContext(ctx).Create(ct).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(ct).Error; err != nil {
+			return err
+		}
+		return r.changed(ctx, tx, "created", ct.ID, ct.Slug)
+	})
 }
 
 func (r *contentTypeRepository) List(ctx context.Context) ([]model.ContentType, error) {
@@ -50,19 +70,60 @@ func (r *contentTypeRepository) GetByID(ctx context.Context, id uuid.UUID) (*mod
 	return &ct, nil
 }
 
+func (r *contentTypeRepository) GetBySlug(ctx context.Context, slug string) (*model.ContentType, error) {
+	var ct model.ContentType
+	err := r.db.WithContext(ctx).
+		Preload("Fields").
+		First(&ct, "slug = ?", slug).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ct, nil
+}
+
 func (r *contentTypeRepository) Update(ctx context.Context, id uuid.UUID, name, slug string) error {
-	return r.db.WithContext(ctx).Model(&model.ContentType{}).
-		Where("id = ?", id).
-		Updates(map[string]interface{}{
-			"name": name,
-			"slug": slug,
-		}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.ContentType{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"name": name,
+				"slug": slug,
+			}).Error; err != nil {
+			return err
+		}
+		return r.changed(ctx, tx, "updated", id, slug)
+	})
 }
 
 func (r *contentTypeRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&model.ContentType{}, "id = ?", id).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ct model.ContentType
+		if err := tx.Select("slug").First(&ct, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.ContentType{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+		return r.changed(ctx, tx, "deleted", id, ct.Slug)
+	})
 }
 
 func (r *contentTypeRepository) AddField(ctx context.Context, field *model.ContentField) error {
-	return r.db.WithContext(ctx).Create(field).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(field).Error; err != nil {
+			return err
+		}
+		// rujukan di entry yang sudah ada ikut tercatat agar asset yang
+		// dipakai tidak bisa dihapus
+		if field.Kind == "image" {
+			if err := rebuildUsage(tx, field.ContentTypeID); err != nil {
+				return err
+			}
+		}
+		var ct model.ContentType
+		if err := tx.Select("slug").First(&ct, "id = ?", field.ContentTypeID).Error; err != nil {
+			return err
+		}
+		return r.changed(ctx, tx, "field_added", field.ContentTypeID, ct.Slug)
+	})
 }
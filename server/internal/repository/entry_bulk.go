@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cms/server/internal/metrics"
+
+	"github.com/google/uuid"
+)
+
+const (
+	BulkPublish   = "publish"
+	BulkUnpublish = "unpublish"
+	BulkDelete    = "delete"
+	BulkStatus    = "status"
+)
+
+// BulkOperation menjelaskan satu aksi yang diterapkan ke banyak entry sekaligus.
+// Jika Atomic true, semua item dijalankan dalam satu transaksi dan dibatalkan
+// bila ada satu item yang gagal.
+type BulkOperation struct {
+	Action string
+	IDs    []uuid.UUID
+	Status string
+	Atomic bool
+}
+
+type BulkResult struct {
+	ID    uuid.UUID `json:"id"`
+	OK    bool      `json:"ok"`
+	Error string    `json:"error,omitempty"`
+}
+
+var ErrBulkAborted = errors.New("bulk operation aborted, no changes applied")
+
+func (op BulkOperation) Validate() error {
+	switch op.Action {
+	case BulkPublish, BulkUnpublish, BulkDelete:
+	case BulkStatus:
+		if op.Status != "draft" && op.Status != "published" {
+			return fmt.Errorf("invalid status %q", op.Status)
+		}
+	default:
+		return fmt.Errorf("invalid action %q", op.Action)
+	}
+	if len(op.IDs) == 0 {
+		return errors.New("ids is required")
+	}
+	return nil
+}
+
+func (r *entryRepository) Bulk(ctx context.Context, slug string, op BulkOperation, editorID *uuid.UUID) ([]BulkResult, error) {
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := r.findContentTypeID(ctx, slug); err != nil {
+		return nil, err
+	}
+
+	if !op.Atomic {
+		// tiap item sudah di-commit sendiri-sendiri
+		results := r.applyBulk(ctx, slug, op, editorID)
+		countPublished(slug, op, results)
+		return results, nil
+	}
+
+	var results []BulkResult
+	err := r.transaction(ctx, func(tx *entryRepository) error {
+		results = tx.applyBulk(ctx, slug, op, editorID)
+		for _, res := range results {
+			if !res.OK {
+				return ErrBulkAborted
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, ErrBulkAborted) {
+		// tandai item yang sebelumnya sukses sebagai ikut dibatalkan
+		for i := range results {
+			if results[i].OK {
+				results[i].OK = false
+				results[i].Error = "rolled back"
+			}
+		}
+	}
+	if err == nil {
+		countPublished(slug, op, results)
+	}
+	return results, err
+}
+
+// countPublished mencatat metrik publish untuk item yang sukses; dipanggil
+// setelah commit agar item yang ikut di-rollback tidak terhitung.
+func countPublished(slug string, op BulkOperation, results []BulkResult) {
+	if op.Action != BulkPublish && (op.Action != BulkStatus || op.Status == "draft") {
+		return
+	}
+	for _, res := range results {
+		if res.OK {
+			metrics.EntryPublished(slug)
+		}
+	}
+}
+
+func (r *entryRepository) applyBulk(ctx context.Context, slug string, op BulkOperation, editorID *uuid.UUID) []BulkResult {
+	results := make([]BulkResult, 0, len(op.IDs))
+	for _, id := range op.IDs {
+		res := BulkResult{ID: id, OK: true}
+		if err := r.applyBulkItem(ctx, slug, op, id, editorID); err != nil {
+			res.OK = false
+			res.Error = err.Error()
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// applyBulkItem menjalankan satu item dalam transaksinya sendiri (savepoint
+// bila Bulk atomic). Metrik tidak dicatat di sini, lihat countPublished.
+func (r *entryRepository) applyBulkItem(ctx context.Context, slug string, op BulkOperation, id uuid.UUID, editorID *uuid.UUID) error {
+	var apply func(tx *entryRepository) error
+	switch op.Action {
+	case BulkPublish:
+		apply = func(tx *entryRepository) error { return tx.publishStatus(ctx, slug, id, time.Now(), editorID) }
+	case BulkUnpublish:
+		apply = func(tx *entryRepository) error { return tx.unpublish(ctx, slug, id, editorID) }
+	case BulkDelete:
+		apply = func(tx *entryRepository) error { return tx.delete(ctx, slug, id, editorID) }
+	case BulkStatus:
+		// validate sudah membatasi status ke draft/published
+		if op.Status == "draft" {
+			apply = func(tx *entryRepository) error { return tx.unpublish(ctx, slug, id, editorID) }
+		} else {
+			apply = func(tx *entryRepository) error { return tx.publishStatus(ctx, slug, id, time.Now(), editorID) }
+		}
+	default:
+		return fmt.Errorf("invalid action %q", op.Action)
+	}
+	return r.transaction(ctx, apply)
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cms/server/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvalidQuery = errors.New("invalid entry query")
+
+// Operator perbandingan pada EntryCondition.
+const (
+	OpEq       = "eq"
+	OpNe       = "ne"
+	OpGt       = "gt"
+	OpGte      = "gte"
+	OpLt       = "lt"
+	OpLte      = "lte"
+	OpIn       = "in"
+	OpContains = "contains" // substring, tidak peka huruf besar/kecil
+	OpHas      = "has"      // array di Data berisi nilai
+)
+
+// EntryQuery adalah pencarian entry dengan filter pada kolom entry maupun
+// field di Data. Dipakai GraphQL API.
+type EntryQuery struct {
+	ContentTypeID uuid.UUID
+	IDs           []uuid.UUID
+	Conditions    []EntryCondition
+	Sort          []EntrySort
+	Limit         int
+	Offset        int
+	// PublishedOnly membatasi ke entry published yang published_at-nya
+	// sudah lewat, sama seperti public API.
+	PublishedOnly bool
+}
+
+// EntryCondition membandingkan Column (id, slug, status, published_at,
+// created_at, updated_at) atau Field (nama field di Data) dengan Value.
+// Numeric membandingkan field Data sebagai angka.
+type EntryCondition struct {
+	Column  string
+	Field   string
+	Numeric bool
+	Op      string
+	Value   any
+}
+
+type EntrySort struct {
+	Column string
+	Field  string
+	Desc   bool
+}
+
+var entryColumns = map[string]bool{
+	"id": true, "slug": true, "status": true,
+	"published_at": true, "created_at": true, "updated_at": true,
+}
+
+var sqlOps = map[string]string{
+	OpEq: "=", OpNe: "<>", OpGt: ">", OpGte: ">=", OpLt: "<", OpLte: "<=",
+}
+
+// Find mengembalikan entry sesuai q beserta jumlah totalnya (tanpa
+// limit/offset).
+func (r *entryRepository) Find(ctx context.Context, q EntryQuery) ([]model.Entry, int64, error) {
+	db := r.db.WithContext(ctx).Model(&model.Entry{}).Where("content_type_id = ?", q.ContentTypeID)
+	if q.PublishedOnly {
+		db = db.Where("status = ? AND published_at <= ?", "published", time.Now())
+	}
+	if q.IDs != nil {
+		db = db.Where("id IN ?", q.IDs)
+	}
+	for _, c := range q.Conditions {
+		expr, err := c.expr()
+		if err != nil {
+			return nil, 0, err
+		}
+		db = db.Where(expr)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// urutan selalu diakhiri id agar stabil untuk paginasi
+	var order []string
+	var vars []any
+	for _, s := range q.Sort {
+		dir := " ASC NULLS LAST"
+		if s.Desc {
+			dir = " DESC NULLS LAST"
+		}
+		switch {
+		case s.Column != "" && entryColumns[s.Column]:
+			order = append(order, s.Column+dir)
+		case s.Field != "":
+			order = append(order, "data -> ?"+dir)
+			vars = append(vars, s.Field)
+		default:
+			return nil, 0, fmt.Errorf("%w: cannot sort by %q", ErrInvalidQuery, s.Column)
+		}
+	}
+	order = append(order, "id")
+	db = db.Order(clause.OrderBy{Expression: clause.Expr{SQL: strings.Join(order, ", "), Vars: vars, WithoutParentheses: true}})
+
+	var items []model.Entry
+	if q.Limit > 0 {
+		db = db.Limit(q.Limit)
+	}
+	if err := db.Offset(q.Offset).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (c EntryCondition) expr() (clause.Expression, error) {
+	var target clause.Expression
+	switch {
+	case c.Column != "" && entryColumns[c.Column]:
+		target = clause.Expr{SQL: c.Column}
+	case c.Field != "" && c.Numeric:
+		// nilai yang bukan angka dianggap NULL, bukan error cast
+		target = clause.Expr{
+			SQL:  "CASE WHEN jsonb_typeof(data -> ?) = 'number' THEN (data ->> ?)::numeric END",
+			Vars: []any{c.Field, c.Field},
+		}
+	case c.Field != "":
+		target = clause.Expr{SQL: "data ->> ?", Vars: []any{c.Field}}
+	default:
+		return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidQuery, c.Column)
+	}
+
+	switch c.Op {
+	case OpIn:
+		return clause.Expr{SQL: "? IN ?", Vars: []any{target, c.Value}}, nil
+	case OpContains:
+		s, _ := c.Value.(string)
+		return clause.Expr{SQL: `? ILIKE ? ESCAPE '\'`, Vars: []any{target, "%" + escapeLike(s) + "%"}}, nil
+	case OpHas:
+		if c.Field == "" {
+			return nil, fmt.Errorf("%w: %s only applies to fields", ErrInvalidQuery, c.Op)
+		}
+		return clause.Expr{SQL: "data -> ? @> jsonb_build_array(?::text)", Vars: []any{c.Field, fmt.Sprint(c.Value)}}, nil
+	}
+	op, ok := sqlOps[c.Op]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown operator %q", ErrInvalidQuery, c.Op)
+	}
+	if c.Op == OpNe {
+		// field yang tidak diisi juga dianggap "tidak sama"
+		return clause.Expr{SQL: "? IS DISTINCT FROM ?", Vars: []any{target, c.Value}}, nil
+	}
+	return clause.Expr{SQL: "? " + op + " ?", Vars: []any{target, c.Value}}, nil
+}
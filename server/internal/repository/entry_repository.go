@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"cms/server/internal/metrics"
 	"cms/server/internal/model"
 
 	"github.com/google/uuid"
@@ -14,35 +15,86 @@ import (
 
 type EntryRepository interface {
 	Create(ctx context.Context, ctSlug string, e *model.Entry, data json.RawMessage, editorID *uuid.UUID) error
-	List(ctx context.Context, ctSlug string, limit, offset int) ([]model.Entry, int64, error)
+	List(ctx context.Context, ctSlug string, page Page) ([]model.Entry, PageInfo, error)
 	Get(ctx context.Context, ctSlug string, id uuid.UUID) (*model.Entry, error)
+	GetBySlug(ctx context.Context, ctSlug string, entrySlug string) (*model.Entry, error)
 	Update(ctx context.Context, ctSlug string, id uuid.UUID, data json.RawMessage, status *string, editorID *uuid.UUID) error
 	Delete(ctx context.Context, ctSlug string, id uuid.UUID, editorID *uuid.UUID) error
 	Publish(ctx context.Context, ctSlug string, id uuid.UUID, t time.Time, editorID *uuid.UUID) error
+	Unpublish(ctx context.Context, ctSlug string, id uuid.UUID, editorID *uuid.UUID) error
 	Rollback(ctx context.Context, ctSlug string, id uuid.UUID, version int, editorID *uuid.UUID) error
-	ListPublished(ctx context.Context, slug string, limit, offset int, sort string) ([]model.Entry, int64, error)
+	GetVersion(ctx context.Context, id uuid.UUID, version int) (*model.EntryVersion, error)
+	// ListPublished mengurutkan dari published_at terbaru bila sort
+	// "-published_at", selain itu dari yang terlama.
+	ListPublished(ctx context.Context, slug string, page Page, sort string) ([]model.Entry, PageInfo, error)
 	GetPublished(ctx context.Context, slug string, id uuid.UUID) (*model.Entry, error)
+	// NextScheduled mengembalikan published_at terdekat di masa depan dari
+	// entry published content type slug, atau nil bila tidak ada.
+	NextScheduled(ctx context.Context, slug string) (*time.Time, error)
+	Bulk(ctx context.Context, slug string, op BulkOperation, editorID *uuid.UUID) ([]BulkResult, error)
+	Find(ctx context.Context, q EntryQuery) ([]model.Entry, int64, error)
 }
 
 type entryRepository struct {
-	db    *gorm.DB
-	audit AuditRepository
+	db     *gorm.DB
+	audit  AuditRepository
+	events EventPublisher
 }
 
-func NewEntryRepository(db *gorm.DB, audit AuditRepository) EntryRepository {
-	return &entryRepository{db: db, audit: audit}
+func NewEntryRepository(db *gorm.DB, audit AuditRepository, events EventPublisher) EntryRepository {
+	return &entryRepository{db: db, audit: audit, events: events}
 }
 
-func (r *entryRepository) findContentTypeID(slug string) (uuid.UUID, error) {
+// transaction menjalankan fn dengan repository yang memakai satu transaksi,
+// sehingga perubahan entry, versi, audit log dan baris outbox event
+// tersimpan bersama atau dibatalkan bersama. Di dalam transaksi lain
+// (bulk atomic) GORM memakai savepoint.
+func (r *entryRepository) transaction(ctx context.Context, fn func(tx *entryRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		tx := &entryRepository{db: db}
+		if r.audit != nil {
+			tx.audit = NewAuditRepository(db)
+		}
+		if r.events != nil {
+			tx.events = NewEventPublisher(db)
+		}
+		return fn(tx)
+	})
+}
+
+// publish menulis event entry ke outbox; panggil dari repository
+// transaksi agar event ikut di-rollback bersama perubahannya.
+func (r *entryRepository) publish(ctx context.Context, event, slug string, e *model.Entry) error {
+	if r.events == nil {
+		return nil
+	}
+	return r.events.Publish(ctx, event, map[string]any{
+		"id":           e.ID,
+		"content_type": slug,
+		"slug":         e.Slug,
+		"status":       e.Status,
+		"data":         e.Data,
+		"published_at": e.PublishedAt,
+		"updated_at":   e.UpdatedAt,
+	})
+}
+
+func (r *entryRepository) findContentTypeID(ctx context.Context, slug string) (uuid.UUID, error) {
 	var ct model.ContentType
-	if err := r.db.Where("slug = ?", slug).First(&ct).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&ct).Error; err != nil {
 		return uuid.UUID{}, err
 	}
 	return ct.ID, nil
 }
 
 func (r *entryRepository) Create(ctx context.Context, slug string, e *model.Entry, data json.RawMessage, editorID *uuid.UUID) error {
-	ctID, err := r.findContentTypeID(slug)
+	return r.transaction(ctx, func(tx *entryRepository) error {
+		return tx.create(ctx, slug, e, data, editorID)
+	})
+}
+
+func (r *entryRepository) create(ctx context.Context, slug string, e *model.Entry, data json.RawMessage, editorID *uuid.UUID) error {
+	ctID, err := r.findContentTypeID(ctx, slug)
 	if err != nil {
 		return err
 	}
@@ -81,27 +133,29 @@ func (r *entryRepository) Create(ctx context.Context, slug string, e *model.Entr
 			Meta:     data,
 		})
 	}
-
-	return nil
+	return r.publish(ctx, model.EventEntryCreated, slug, e)
 }
 
-func (r *entryRepository) List(ctx context.Context, slug string, limit, offset int) ([]model.Entry, int64, error) {
-	ctID, err := r.findContentTypeID(slug)
+func (r *entryRepository) List(ctx context.Context, slug string, page Page) ([]model.Entry, PageInfo, error) {
+	ctID, err := r.findContentTypeID(ctx, slug)
 	if err != nil {
-		return nil, 0, err
+		return nil, PageInfo{}, err
 	}
-	var items []model.Entry
-	var total int64
-	db := r.db.WithContext(ctx).Where("content_type_id = ?", ctID)
-	db.Model(&model.Entry{}).Count(&total)
-	if err := db.Order("created_at desc").Limit(limit).Offset(offset).Find(&items).Error; err != nil {
-		return nil, 0, err
+	db := r.db.WithContext(ctx).Model(&model.Entry{}).Where("content_type_id = ?", ctID)
+	return paginate(db, page, keyset{sort: "-created_at", column: "created_at", desc: true}, entryCreatedKey)
+}
+
+func entryCreatedKey(e *model.Entry) (time.Time, string) { return e.CreatedAt, e.ID.String() }
+
+func entryPublishedKey(e *model.Entry) (time.Time, string) {
+	if e.PublishedAt == nil {
+		return time.Time{}, e.ID.String()
 	}
-	return items, total, nil
+	return *e.PublishedAt, e.ID.String()
 }
 
 func (r *entryRepository) Get(ctx context.Context, slug string, id uuid.UUID) (*model.Entry, error) {
-	ctID, err := r.findContentTypeID(slug)
+	ctID, err := r.findContentTypeID(ctx, slug)
 	if err != nil {
 		return nil, err
 	}
@@ -112,7 +166,25 @@ func (r *entryRepository) Get(ctx context.Context, slug string, id uuid.UUID) (*
 	return &e, nil
 }
 
+func (r *entryRepository) GetBySlug(ctx context.Context, slug string, entrySlug string) (*model.Entry, error) {
+	ctID, err := r.findContentTypeID(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	var e model.Entry
+	if err := r.db.WithContext(ctx).Where("content_type_id = ? AND slug = ?", ctID, entrySlug).First(&e).Error; err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
 func (r *entryRepository) Update(ctx context.Context, slug string, id uuid.UUID, data json.RawMessage, status *string, editorID *uuid.UUID) error {
+	return r.transaction(ctx, func(tx *entryRepository) error {
+		return tx.update(ctx, slug, id, data, status, editorID)
+	})
+}
+
+func (r *entryRepository) update(ctx context.Context, slug string, id uuid.UUID, data json.RawMessage, status *string, editorID *uuid.UUID) error {
 	e, err := r.Get(ctx, slug, id)
 	if err != nil {
 		return err
@@ -160,14 +232,28 @@ func (r *entryRepository) Update(ctx context.Context, slug string, id uuid.UUID,
 			Meta:     data,
 		})
 	}
-
-	return nil
+	return r.publish(ctx, model.EventEntryUpdated, slug, e)
 }
 
 func (r *entryRepository) Delete(ctx context.Context, slug string, id uuid.UUID, editorID *uuid.UUID) error {
-	if err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.Entry{}).Error; err != nil {
+	return r.transaction(ctx, func(tx *entryRepository) error {
+		return tx.delete(ctx, slug, id, editorID)
+	})
+}
+
+func (r *entryRepository) delete(ctx context.Context, slug string, id uuid.UUID, editorID *uuid.UUID) error {
+	ctID, err := r.findContentTypeID(ctx, slug)
+	if err != nil {
 		return err
 	}
+	// entry dari content type lain dianggap tidak ada
+	res := r.db.WithContext(ctx).Where("content_type_id = ? AND id = ?", ctID, id).Delete(&model.Entry{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
 
 	// 👇 Tambahkan audit log
 	if r.audit != nil {
@@ -178,17 +264,32 @@ func (r *entryRepository) Delete(ctx context.Context, slug string, id uuid.UUID,
 			Meta:     json.RawMessage(`{"deleted": true}`),
 		})
 	}
+	if r.events == nil {
+		return nil
+	}
+	return r.events.Publish(ctx, model.EventEntryDeleted, map[string]any{
+		"id":           id,
+		"content_type": slug,
+	})
+}
 
+func (r *entryRepository) Publish(ctx context.Context, slug string, id uuid.UUID, t time.Time, editorID *uuid.UUID) error {
+	if err := r.transaction(ctx, func(tx *entryRepository) error {
+		return tx.publishEntry(ctx, slug, id, t, editorID)
+	}); err != nil {
+		return err
+	}
+	metrics.EntryPublished(slug)
 	return nil
 }
 
-func (r *entryRepository) Publish(ctx context.Context, slug string, id uuid.UUID, t time.Time, editorID *uuid.UUID) error {
+func (r *entryRepository) publishEntry(ctx context.Context, slug string, id uuid.UUID, t time.Time, editorID *uuid.UUID) error {
 	entry, err := r.Get(ctx, slug, id)
 	if err != nil {
 		return err
 	}
 	status := "published"
-	if err := r.Update(ctx, slug, id, entry.Data, &status, editorID); err != nil {
+	if err := r.update(ctx, slug, id, entry.Data, &status, editorID); err != nil {
 		return err
 	}
 
@@ -200,17 +301,96 @@ func (r *entryRepository) Publish(ctx context.Context, slug string, id uuid.UUID
 			Meta:     json.RawMessage(`{"slug": "` + slug + `"}`),
 		})
 	}
+	published, err := r.Get(ctx, slug, id)
+	if err != nil {
+		return err
+	}
+	return r.publish(ctx, model.EventEntryPublished, slug, published)
+}
 
-	return nil
+// publishStatus dipakai bulk publish: hanya status yang berubah, data tidak
+// berubah sehingga tidak perlu versi baru maupun audit update_entry.
+func (r *entryRepository) publishStatus(ctx context.Context, slug string, id uuid.UUID, t time.Time, editorID *uuid.UUID) error {
+	e, err := r.Get(ctx, slug, id)
+	if err != nil {
+		return err
+	}
+	publishedAt := e.PublishedAt
+	if publishedAt == nil {
+		publishedAt = &t
+	}
+	if err := r.db.WithContext(ctx).Model(e).Updates(map[string]interface{}{
+		"status":       "published",
+		"published_at": publishedAt,
+		"updated_by":   editorID,
+		"updated_at":   time.Now(),
+	}).Error; err != nil {
+		return err
+	}
+
+	if r.audit != nil {
+		_ = r.audit.Log(ctx, &model.AuditLog{
+			ActorID:  editorID,
+			Action:   "publish_entry",
+			Resource: "entry:" + id.String(),
+			Meta:     json.RawMessage(`{"slug": "` + slug + `"}`),
+		})
+	}
+	published, err := r.Get(ctx, slug, id)
+	if err != nil {
+		return err
+	}
+	return r.publish(ctx, model.EventEntryPublished, slug, published)
+}
+
+func (r *entryRepository) Unpublish(ctx context.Context, slug string, id uuid.UUID, editorID *uuid.UUID) error {
+	return r.transaction(ctx, func(tx *entryRepository) error {
+		return tx.unpublish(ctx, slug, id, editorID)
+	})
+}
+
+func (r *entryRepository) unpublish(ctx context.Context, slug string, id uuid.UUID, editorID *uuid.UUID) error {
+	e, err := r.Get(ctx, slug, id)
+	if err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Model(e).Updates(map[string]interface{}{
+		"status":       "draft",
+		"published_at": nil,
+		"updated_by":   editorID,
+		"updated_at":   time.Now(),
+	}).Error; err != nil {
+		return err
+	}
+
+	if r.audit != nil {
+		_ = r.audit.Log(ctx, &model.AuditLog{
+			ActorID:  editorID,
+			Action:   "unpublish_entry",
+			Resource: "entry:" + id.String(),
+			Meta:     json.RawMessage(`{"slug": "` + slug + `"}`),
+		})
+	}
+	unpublished, err := r.Get(ctx, slug, id)
+	if err != nil {
+		return err
+	}
+	return r.publish(ctx, model.EventEntryUnpublished, slug, unpublished)
 }
 
 func (r *entryRepository) Rollback(ctx context.Context, slug string, id uuid.UUID, version int, editorID *uuid.UUID) error {
+	return r.transaction(ctx, func(tx *entryRepository) error {
+		return tx.rollback(ctx, slug, id, version, editorID)
+	})
+}
+
+func (r *entryRepository) rollback(ctx context.Context, slug string, id uuid.UUID, version int, editorID *uuid.UUID) error {
 	var v model.EntryVersion
 	if err := r.db.WithContext(ctx).Where("entry_id = ? AND version = ?", id, version).First(&v).Error; err != nil {
 		return err
 	}
 
-	if err := r.Update(ctx, slug, id, v.Data, nil, editorID); err != nil {
+	if err := r.update(ctx, slug, id, v.Data, nil, editorID); err != nil {
 		return err
 	}
 
@@ -230,35 +410,32 @@ func (r *entryRepository) Rollback(ctx context.Context, slug string, id uuid.UUI
 	return nil
 }
 
-func (r *entryRepository) ListPublished(ctx context.Context, slug string, limit, offset int, sort string) ([]model.Entry, int64, error) {
-	ctID, err := r.findContentTypeID(slug)
-	if err != nil {
-		return nil, 0, err
+func (r *entryRepository) GetVersion(ctx context.Context, id uuid.UUID, version int) (*model.EntryVersion, error) {
+	var v model.EntryVersion
+	if err := r.db.WithContext(ctx).Where("entry_id = ? AND version = ?", id, version).First(&v).Error; err != nil {
+		return nil, err
 	}
+	return &v, nil
+}
 
-	var items []model.Entry
-	var total int64
+func (r *entryRepository) ListPublished(ctx context.Context, slug string, page Page, sort string) ([]model.Entry, PageInfo, error) {
+	ctID, err := r.findContentTypeID(ctx, slug)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
 
 	db := r.db.WithContext(ctx).Model(&model.Entry{}).
 		Where("content_type_id = ? AND status = ? AND published_at <= ?", ctID, "published", time.Now())
 
-	db.Count(&total)
-
-	if sort == "-published_at" {
-		db = db.Order("published_at desc")
-	} else {
-		db = db.Order("published_at asc")
-	}
-
-	if err := db.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
-		return nil, 0, err
+	k := keyset{sort: "-published_at", column: "published_at", desc: true}
+	if sort != "-published_at" {
+		k = keyset{sort: "published_at", column: "published_at"}
 	}
-
-	return items, total, nil
+	return paginate(db, page, k, entryPublishedKey)
 }
 
 func (r *entryRepository) GetPublished(ctx context.Context, slug string, id uuid.UUID) (*model.Entry, error) {
-	ctID, err := r.findContentTypeID(slug)
+	ctID, err := r.findContentTypeID(ctx, slug)
 	if err != nil {
 		return nil, err
 	}
@@ -270,3 +447,17 @@ func (r *entryRepository) GetPublished(ctx context.Context, slug string, id uuid
 	}
 	return &e, nil
 }
+
+func (r *entryRepository) NextScheduled(ctx context.Context, slug string) (*time.Time, error) {
+	ctID, err := r.findContentTypeID(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	var next *time.Time
+	if err := r.db.WithContext(ctx).Model(&model.Entry{}).
+		Where("content_type_id = ? AND status = ? AND published_at > ?", ctID, "published", time.Now()).
+		Select("min(published_at)").Scan(&next).Error; err != nil {
+		return nil, err
+	}
+	return next, nil
+}
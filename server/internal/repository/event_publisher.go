@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"cms/server/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EventPublisher mencatat event lifecycle konten. Event ditulis ke outbox
+// webhook_deliveries dan dikirim lewat NOTIFY memakai koneksi/transaksi yang
+// sama dengan perubahannya, sehingga event dari transaksi yang di-rollback
+// tidak pernah terkirim.
+type EventPublisher interface {
+	Publish(ctx context.Context, event string, data any) error
+}
+
+type eventPublisher struct {
+	db *gorm.DB
+}
+
+func NewEventPublisher(db *gorm.DB) EventPublisher {
+	return &eventPublisher{db: db}
+}
+
+func (p *eventPublisher) Publish(ctx context.Context, event string, data any) error {
+	ev := model.Event{
+		ID:        uuid.New(),
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	// satu baris outbox per webhook aktif yang subscribe event ini
+	if err := p.db.WithContext(ctx).Exec(`
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event, payload, status, next_attempt_at)
+		SELECT id, ?, ?, ?, ?, now()
+		FROM webhooks
+		WHERE active AND (? = ANY(events) OR '*' = ANY(events))`,
+		ev.ID, event, payload, model.DeliveryPending, event,
+	).Error; err != nil {
+		return err
+	}
+
+	return p.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", model.EventsChannel, string(notifyPayload(payload))).Error
+}
+
+// maxNotifyPayload sedikit di bawah batas 8000 byte payload NOTIFY Postgres.
+const maxNotifyPayload = 7900
+
+// notifyPayload membuang isi "data.data" (konten entry) bila payload terlalu
+// besar untuk NOTIFY; subscriber tetap menerima id, slug dan status-nya.
+func notifyPayload(payload []byte) []byte {
+	if len(payload) <= maxNotifyPayload {
+		return payload
+	}
+	var ev struct {
+		ID        uuid.UUID                  `json:"id"`
+		Event     string                     `json:"event"`
+		CreatedAt time.Time                  `json:"created_at"`
+		Data      map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		return payload
+	}
+	delete(ev.Data, "data")
+	ev.Data["truncated"] = json.RawMessage("true")
+	out, err := json.Marshal(ev)
+	if err != nil {
+		return payload
+	}
+	return out
+}
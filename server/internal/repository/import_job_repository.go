@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"cms/server/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ImportJobRepository interface {
+	Create(ctx context.Context, job *model.ImportJob) error
+	Get(ctx context.Context, id uuid.UUID) (*model.ImportJob, error)
+	UpdateProgress(ctx context.Context, id uuid.UUID, processed, succeeded, failed int) error
+	Finish(ctx context.Context, id uuid.UUID, status string, report json.RawMessage, errMsg string) error
+	// Pending menghitung import yang pending atau running.
+	Pending(ctx context.Context) (int64, error)
+}
+
+type importJobRepository struct {
+	db *gorm.DB
+}
+
+func NewImportJobRepository(db *gorm.DB) ImportJobRepository {
+	return &importJobRepository{db: db}
+}
+
+func (r *importJobRepository) Create(ctx context.Context, job *model.ImportJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+func (r *importJobRepository) Get(ctx context.Context, id uuid.UUID) (*model.ImportJob, error) {
+	var job model.ImportJob
+	if err := r.db.WithContext(ctx).First(&job, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *importJobRepository) UpdateProgress(ctx context.Context, id uuid.UUID, processed, succeeded, failed int) error {
+	return r.db.WithContext(ctx).Model(&model.ImportJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":         model.JobRunning,
+			"processed_rows": processed,
+			"succeeded":      succeeded,
+			"failed":         failed,
+			"updated_at":     time.Now(),
+		}).Error
+}
+
+func (r *importJobRepository) Finish(ctx context.Context, id uuid.UUID, status string, report json.RawMessage, errMsg string) error {
+	now := time.Now()
+	if report == nil {
+		report = json.RawMessage(`[]`)
+	}
+	return r.db.WithContext(ctx).Model(&model.ImportJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"report":      report,
+			"error":       errMsg,
+			"updated_at":  now,
+			"finished_at": &now,
+		}).Error
+}
+
+func (r *importJobRepository) Pending(ctx context.Context) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&model.ImportJob{}).
+		Where("status IN ?", []string{model.JobPending, model.JobRunning}).Count(&n).Error
+	return n, err
+}
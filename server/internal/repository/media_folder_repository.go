@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cms/server/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrFolderNotEmpty = errors.New("folder is not empty")
+	ErrFolderCycle    = errors.New("folder cannot be moved into itself or its subfolder")
+	ErrFolderExists   = errors.New("a folder with this name already exists here")
+)
+
+type MediaFolderRepository interface {
+	// List mengembalikan subfolder langsung dari parentID (nil = root).
+	List(ctx context.Context, parentID *uuid.UUID) ([]model.MediaFolder, error)
+	Get(ctx context.Context, id uuid.UUID) (*model.MediaFolder, error)
+	Create(ctx context.Context, f *model.MediaFolder) error
+	Update(ctx context.Context, id uuid.UUID, name string, parentID *uuid.UUID) (*model.MediaFolder, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type mediaFolderRepository struct {
+	db *gorm.DB
+}
+
+func NewMediaFolderRepository(db *gorm.DB) MediaFolderRepository {
+	return &mediaFolderRepository{db: db}
+}
+
+func (r *mediaFolderRepository) List(ctx context.Context, parentID *uuid.UUID) ([]model.MediaFolder, error) {
+	var items []model.MediaFolder
+	q := r.db.WithContext(ctx)
+	if parentID == nil {
+		q = q.Where("parent_id IS NULL")
+	} else {
+		q = q.Where("parent_id = ?", *parentID)
+	}
+	err := q.Order("lower(name)").Find(&items).Error
+	return items, err
+}
+
+func (r *mediaFolderRepository) Get(ctx context.Context, id uuid.UUID) (*model.MediaFolder, error) {
+	var f model.MediaFolder
+	if err := r.db.WithContext(ctx).First(&f, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (r *mediaFolderRepository) Create(ctx context.Context, f *model.MediaFolder) error {
+	if f.ParentID != nil {
+		if _, err := r.Get(ctx, *f.ParentID); err != nil {
+			return err
+		}
+	}
+	if err := r.checkName(ctx, f.ParentID, f.Name, nil); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Create(f).Error
+}
+
+// checkName memastikan belum ada folder lain dengan nama yang sama
+// (case-insensitive) di parent yang sama.
+func (r *mediaFolderRepository) checkName(ctx context.Context, parentID *uuid.UUID, name string, except *uuid.UUID) error {
+	q := r.db.WithContext(ctx).Model(&model.MediaFolder{}).Where("lower(name) = lower(?)", name)
+	if parentID == nil {
+		q = q.Where("parent_id IS NULL")
+	} else {
+		q = q.Where("parent_id = ?", *parentID)
+	}
+	if except != nil {
+		q = q.Where("id <> ?", *except)
+	}
+	var n int64
+	if err := q.Count(&n).Error; err != nil {
+		return err
+	}
+	if n > 0 {
+		return ErrFolderExists
+	}
+	return nil
+}
+
+// Update mengganti nama dan/atau memindahkan folder ke parent lain.
+func (r *mediaFolderRepository) Update(ctx context.Context, id uuid.UUID, name string, parentID *uuid.UUID) (*model.MediaFolder, error) {
+	f, err := r.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if parentID != nil {
+		if _, err := r.Get(ctx, *parentID); err != nil {
+			return nil, err
+		}
+		// parent baru tidak boleh folder ini sendiri atau turunannya
+		var cycle bool
+		err := r.db.WithContext(ctx).Raw(`
+			WITH RECURSIVE ancestors AS (
+				SELECT id, parent_id FROM media_folders WHERE id = ?
+				UNION ALL
+				SELECT f.id, f.parent_id FROM media_folders f JOIN ancestors a ON f.id = a.parent_id
+			)
+			SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = ?)`, *parentID, id).Scan(&cycle).Error
+		if err != nil {
+			return nil, err
+		}
+		if cycle {
+			return nil, ErrFolderCycle
+		}
+	}
+
+	if err := r.checkName(ctx, parentID, name, &id); err != nil {
+		return nil, err
+	}
+
+	f.Name = name
+	f.ParentID = parentID
+	f.UpdatedAt = time.Now()
+	if err := r.db.WithContext(ctx).Model(f).
+		Select("name", "parent_id", "updated_at").
+		Updates(f).Error; err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Delete hanya menghapus folder kosong (tanpa subfolder dan tanpa asset).
+func (r *mediaFolderRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var f model.MediaFolder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&f, "id = ?", id).Error; err != nil {
+			return err
+		}
+		var n int64
+		if err := tx.Model(&model.MediaFolder{}).Where("parent_id = ?", id).Count(&n).Error; err != nil {
+			return err
+		}
+		if n == 0 {
+			if err := tx.Model(&model.MediaAsset{}).Where("folder_id = ?", id).Count(&n).Error; err != nil {
+				return err
+			}
+		}
+		if n > 0 {
+			return ErrFolderNotEmpty
+		}
+		return tx.Delete(&model.MediaFolder{}, "id = ?", id).Error
+	})
+}
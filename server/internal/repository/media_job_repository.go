@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"cms/server/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MediaJobRepository interface {
+	// Enqueue membuat job baru untuk media, kecuali masih ada job yang
+	// pending/running: job itu yang dikembalikan.
+	Enqueue(ctx context.Context, mediaID uuid.UUID) (*model.MediaJob, error)
+	// ClaimDue mengambil job yang jatuh tempo, menandainya running dan
+	// menunda next_attempt_at selama lease. Job running yang lease-nya
+	// habis (worker mati) diambil ulang.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.MediaJob, error)
+	Save(ctx context.Context, job *model.MediaJob) error
+	ListByMedia(ctx context.Context, mediaID uuid.UUID, limit int) ([]model.MediaJob, error)
+	// Pending menghitung job yang pending atau running.
+	Pending(ctx context.Context) (int64, error)
+}
+
+// NeedsProcessing melaporkan apakah media dengan mime ini diproses worker
+// (metadata & poster). Gambar dianalisis langsung saat upload.
+func NeedsProcessing(mime string) bool {
+	return strings.HasPrefix(mime, "video/") || strings.HasPrefix(mime, "audio/") || mime == "application/pdf"
+}
+
+type mediaJobRepository struct {
+	db *gorm.DB
+}
+
+func NewMediaJobRepository(db *gorm.DB) MediaJobRepository {
+	return &mediaJobRepository{db: db}
+}
+
+func (r *mediaJobRepository) Enqueue(ctx context.Context, mediaID uuid.UUID) (*model.MediaJob, error) {
+	var job model.MediaJob
+	err := r.db.WithContext(ctx).
+		Where("media_id = ? AND status IN ?", mediaID, []string{model.JobPending, model.JobRunning}).
+		Order("created_at DESC").
+		First(&job).Error
+	if err == nil {
+		return &job, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	job = model.MediaJob{
+		MediaID:       mediaID,
+		Kind:          model.MediaJobMetadata,
+		Status:        model.JobPending,
+		NextAttemptAt: time.Now(),
+	}
+	if err := r.db.WithContext(ctx).Create(&job).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *mediaJobRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.MediaJob, error) {
+	var items []model.MediaJob
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE media_jobs SET status = ?, attempts = attempts + 1, next_attempt_at = ?, updated_at = now()
+		WHERE id IN (
+			SELECT id FROM media_jobs
+			WHERE status IN (?, ?) AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		model.JobRunning, time.Now().Add(lease), model.JobPending, model.JobRunning, limit,
+	).Scan(&items).Error
+	return items, err
+}
+
+func (r *mediaJobRepository) Save(ctx context.Context, job *model.MediaJob) error {
+	job.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Model(job).
+		Select("status", "error", "next_attempt_at", "updated_at", "finished_at").
+		Updates(job).Error
+}
+
+func (r *mediaJobRepository) ListByMedia(ctx context.Context, mediaID uuid.UUID, limit int) ([]model.MediaJob, error) {
+	var items []model.MediaJob
+	err := r.db.WithContext(ctx).
+		Where("media_id = ?", mediaID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&items).Error
+	return items, err
+}
+
+func (r *mediaJobRepository) Pending(ctx context.Context) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&model.MediaJob{}).
+		Where("status IN ?", []string{model.JobPending, model.JobRunning}).Count(&n).Error
+	return n, err
+}
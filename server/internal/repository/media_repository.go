@@ -2,29 +2,119 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
 
 	"cms/server/internal/model"
 
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type MediaRepository interface {
 	Save(ctx context.Context, asset *model.MediaAsset) error
 	FindByID(ctx context.Context, id string) (*model.MediaAsset, error)
-	List(ctx context.Context, limit, offset int) ([]model.MediaAsset, int64, error)
-	Delete(ctx context.Context, id string) error
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]model.MediaAsset, error)
+	List(ctx context.Context, f MediaFilter, page Page) ([]model.MediaAsset, PageInfo, error)
+	// Delete menghapus asset. Bila asset adalah pemakai terakhir object-nya,
+	// release dipanggil (di dalam transaksi) untuk menghapus file dari
+	// storage; error dari release membatalkan penghapusan.
+	Delete(ctx context.Context, id string, release func(objectName string) error) error
+	// FindObject mencari object di storage dengan checksum yang sama.
+	FindObject(ctx context.Context, checksum string) (*model.MediaObject, error)
+	UpdateMeta(ctx context.Context, id string, meta json.RawMessage) error
+	UpdateDetails(ctx context.Context, id string, d MediaDetails) error
+	Move(ctx context.Context, ids []uuid.UUID, folderID *uuid.UUID) (int64, error)
+	Tags(ctx context.Context) ([]MediaTag, error)
+	Usage(ctx context.Context, id string) ([]MediaUsage, error)
+	// IsPublic melaporkan apakah file asset boleh diambil tanpa login.
+	IsPublic(ctx context.Context, asset *model.MediaAsset) (bool, error)
+}
+
+// MediaFilter menyaring hasil List. Field kosong berarti tidak difilter.
+type MediaFilter struct {
+	// Query dicocokkan ke filename, alt text dan caption (case-insensitive).
+	Query string
+	// Tags: asset harus memiliki semua tag ini.
+	Tags []string
+	// Mime cocok persis ("image/png") atau per tipe ("image/*").
+	Mime string
+	// FolderID membatasi ke satu folder; Root membatasi ke asset tanpa folder.
+	FolderID *uuid.UUID
+	Root     bool
+	From     *time.Time
+	To       *time.Time
+}
+
+// MediaDetails berisi metadata editorial yang bisa diubah. Field nil tidak
+// diubah.
+type MediaDetails struct {
+	AltText    *string
+	Caption    *string
+	Credit     *string
+	Tags       *[]string
+	Visibility *string
+}
+
+// MediaUsage adalah satu rujukan ke media dari field image sebuah entry.
+// Tabel media_usages diisi trigger database setiap kali data entry atau
+// versi entry ditulis.
+type MediaUsage struct {
+	EntryID     uuid.UUID `json:"entry_id"`
+	ContentType string    `json:"content_type"`
+	EntrySlug   string    `json:"entry_slug"`
+	Status      string    `json:"status"`
+	// Version 0 berarti data entry saat ini, selain itu versi lama.
+	Version int    `json:"version"`
+	Field   string `json:"field"`
+}
+
+type MediaTag struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
 }
 
 type mediaRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	events EventPublisher
 }
 
-func NewMediaRepository(db *gorm.DB) MediaRepository {
-	return &mediaRepository{db: db}
+func NewMediaRepository(db *gorm.DB, events EventPublisher) MediaRepository {
+	return &mediaRepository{db: db, events: events}
 }
 
 func (r *mediaRepository) Save(ctx context.Context, asset *model.MediaAsset) error {
-	return r.db.WithContext(ctx).Create(asset).Error
+	if asset.Tags == nil {
+		asset.Tags = pq.StringArray{}
+	}
+	// video, audio & PDF langsung masuk antrian pemrosesan, dalam transaksi
+	// yang sama (bersama event outbox) agar tidak ada asset yang terlewat
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(asset).Error; err != nil {
+			return err
+		}
+		if NeedsProcessing(asset.Mime) {
+			if err := tx.Create(&model.MediaJob{
+				MediaID:       asset.ID,
+				Kind:          model.MediaJobMetadata,
+				Status:        model.JobPending,
+				NextAttemptAt: time.Now(),
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return r.publish(ctx, tx, model.EventMediaUploaded, map[string]any{
+			"id":         asset.ID,
+			"filename":   asset.Filename,
+			"mime":       asset.Mime,
+			"size_bytes": asset.SizeBytes,
+		})
+	})
 }
 
 func (r *mediaRepository) FindByID(ctx context.Context, id string) (*model.MediaAsset, error) {
@@ -35,28 +125,247 @@ func (r *mediaRepository) FindByID(ctx context.Context, id string) (*model.Media
 	return &asset, nil
 }
 
-func (r *mediaRepository) List(ctx context.Context, limit, offset int) ([]model.MediaAsset, int64, error) {
+func (r *mediaRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]model.MediaAsset, error) {
 	var items []model.MediaAsset
-	var total int64
+	if len(ids) == 0 {
+		return items, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&items).Error
+	return items, err
+}
 
-	if err := r.db.WithContext(ctx).
-		Model(&model.MediaAsset{}).
-		Count(&total).Error; err != nil {
-		return nil, 0, err
+func (r *mediaRepository) List(ctx context.Context, f MediaFilter, page Page) ([]model.MediaAsset, PageInfo, error) {
+	q := r.db.WithContext(ctx).Model(&model.MediaAsset{})
+	if f.Query != "" {
+		like := "%" + escapeLike(f.Query) + "%"
+		q = q.Where("filename ILIKE ? OR alt_text ILIKE ? OR caption ILIKE ?", like, like, like)
+	}
+	if len(f.Tags) > 0 {
+		q = q.Where("tags @> ?", pq.StringArray(f.Tags))
+	}
+	if f.Mime != "" {
+		if typ, ok := strings.CutSuffix(f.Mime, "/*"); ok {
+			q = q.Where("mime LIKE ?", escapeLike(typ)+"/%")
+		} else {
+			q = q.Where("mime = ?", f.Mime)
+		}
 	}
+	switch {
+	case f.FolderID != nil:
+		q = q.Where("folder_id = ?", *f.FolderID)
+	case f.Root:
+		q = q.Where("folder_id IS NULL")
+	}
+	if f.From != nil {
+		q = q.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		q = q.Where("created_at < ?", *f.To)
+	}
+
+	return paginate(q, page, keyset{sort: "-created_at", column: "created_at", desc: true},
+		func(a *model.MediaAsset) (time.Time, string) { return a.CreatedAt, a.ID.String() })
+}
+
+func (r *mediaRepository) Delete(ctx context.Context, id string, release func(objectName string) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var asset model.MediaAsset
+		if err := tx.First(&asset, "id = ?", id).Error; err != nil {
+			return err
+		}
+		// baris object dikunci agar upload lain tidak ikut memakai object
+		// yang sedang dihapus
+		var obj model.MediaObject
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&obj, "object_name = ?", asset.URL).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if obj.RefCount <= 1 && release != nil {
+			if err := release(asset.URL); err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(&model.MediaAsset{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+		return r.publish(ctx, tx, model.EventMediaDeleted, map[string]any{
+			"id":       asset.ID,
+			"filename": asset.Filename,
+		})
+	})
+}
+
+// publish menulis event media ke outbox lewat tx, transaksi yang sama dengan
+// perubahannya.
+func (r *mediaRepository) publish(ctx context.Context, tx *gorm.DB, event string, data map[string]any) error {
+	if r.events == nil {
+		return nil
+	}
+	return NewEventPublisher(tx).Publish(ctx, event, data)
+}
 
-	if err := r.db.WithContext(ctx).
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&items).Error; err != nil {
-		return nil, 0, err
+func (r *mediaRepository) FindObject(ctx context.Context, checksum string) (*model.MediaObject, error) {
+	var obj model.MediaObject
+	err := r.db.WithContext(ctx).
+		Where("checksum = ? AND ref_count > 0", checksum).
+		Order("created_at").
+		First(&obj).Error
+	if err != nil {
+		return nil, err
 	}
+	return &obj, nil
+}
 
-	return items, total, nil
+func (r *mediaRepository) UpdateMeta(ctx context.Context, id string, meta json.RawMessage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.MediaAsset{}).Where("id = ?", id).Update("meta", meta).Error; err != nil {
+			return err
+		}
+		return r.publish(ctx, tx, model.EventMediaUpdated, map[string]any{"id": id, "meta": meta})
+	})
+}
+
+func (r *mediaRepository) UpdateDetails(ctx context.Context, id string, d MediaDetails) error {
+	fields := map[string]interface{}{}
+	if d.AltText != nil {
+		fields["alt_text"] = *d.AltText
+	}
+	if d.Caption != nil {
+		fields["caption"] = *d.Caption
+	}
+	if d.Credit != nil {
+		fields["credit"] = *d.Credit
+	}
+	if d.Tags != nil {
+		fields["tags"] = pq.StringArray(NormalizeTags(*d.Tags))
+	}
+	if d.Visibility != nil {
+		fields["visibility"] = *d.Visibility
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.MediaAsset{}).Where("id = ?", id).Updates(fields)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		fields["id"] = id
+		return r.publish(ctx, tx, model.EventMediaUpdated, fields)
+	})
+}
+
+// Move memindahkan asset ke folder lain; folderID nil berarti ke root.
+func (r *mediaRepository) Move(ctx context.Context, ids []uuid.UUID, folderID *uuid.UUID) (int64, error) {
+	if folderID != nil {
+		var f model.MediaFolder
+		if err := r.db.WithContext(ctx).First(&f, "id = ?", *folderID).Error; err != nil {
+			return 0, err
+		}
+	}
+	var moved int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.MediaAsset{}).
+			Where("id IN ?", ids).
+			Update("folder_id", folderID)
+		if res.Error != nil {
+			return res.Error
+		}
+		moved = res.RowsAffected
+		if moved == 0 {
+			return nil
+		}
+		return r.publish(ctx, tx, model.EventMediaMoved, map[string]any{
+			"ids":       ids,
+			"folder_id": folderID,
+		})
+	})
+	return moved, err
+}
+
+// Tags mengembalikan semua tag beserta jumlah asset, terbanyak lebih dulu.
+func (r *mediaRepository) Tags(ctx context.Context) ([]MediaTag, error) {
+	var tags []MediaTag
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT tag, count(*) AS count
+		FROM media_assets, unnest(tags) AS tag
+		GROUP BY tag
+		ORDER BY count DESC, tag`).Scan(&tags).Error
+	return tags, err
+}
+
+func (r *mediaRepository) Usage(ctx context.Context, id string) ([]MediaUsage, error) {
+	var usages []MediaUsage
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT u.entry_id, ct.slug AS content_type, e.slug AS entry_slug, e.status, u.version, u.field
+		FROM media_usages u
+		JOIN entries e ON e.id = u.entry_id
+		JOIN content_types ct ON ct.id = e.content_type_id
+		WHERE u.media_id = ?
+		ORDER BY ct.slug, e.slug, u.version, u.field`, id).Scan(&usages).Error
+	return usages, err
+}
+
+func (r *mediaRepository) IsPublic(ctx context.Context, asset *model.MediaAsset) (bool, error) {
+	switch asset.Visibility {
+	case model.MediaVisibilityPublic:
+		return true, nil
+	case model.MediaVisibilityPrivate:
+		return false, nil
+	}
+	// auto: dirujuk data terkini entry yang sudah tayang
+	var public bool
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT EXISTS (
+			SELECT 1 FROM media_usages u
+			JOIN entries e ON e.id = u.entry_id
+			WHERE u.media_id = ? AND u.version = 0
+			  AND e.status = 'published' AND (e.published_at IS NULL OR e.published_at <= now())
+		)`, asset.ID).Scan(&public).Error
+	return public, err
+}
+
+// rebuildUsage menghitung ulang rujukan media dari entry (dan versinya)
+// milik satu content type. Trigger media_usages hanya berjalan saat entry
+// ditulis, sehingga AddField memanggilnya untuk field image baru pada entry
+// lama.
+func rebuildUsage(tx *gorm.DB, contentTypeID uuid.UUID) error {
+	if err := tx.Exec(`DELETE FROM media_usages
+		WHERE entry_id IN (SELECT id FROM entries WHERE content_type_id = ?)`, contentTypeID).Error; err != nil {
+		return err
+	}
+	return tx.Exec(`INSERT INTO media_usages (media_id, entry_id, version, field)
+		SELECT r.media_id, e.id, 0, r.field
+		FROM entries e, media_refs(e.content_type_id, e.data) r
+		WHERE e.content_type_id = @ct
+		UNION
+		SELECT r.media_id, v.entry_id, v.version, r.field
+		FROM entry_versions v
+		JOIN entries e ON e.id = v.entry_id,
+		media_refs(e.content_type_id, v.data) r
+		WHERE e.content_type_id = @ct`, sql.Named("ct", contentTypeID)).Error
+}
+
+// NormalizeTags merapikan tag: huruf kecil, tanpa spasi di tepi, tanpa
+// duplikat dan tanpa tag kosong.
+func NormalizeTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	seen := map[string]bool{}
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
 }
 
-func (r *mediaRepository) Delete(ctx context.Context, id string) error {
-	return r.db.WithContext(ctx).
-		Delete(&model.MediaAsset{}, "id = ?", id).Error
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
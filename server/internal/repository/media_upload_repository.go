@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"cms/server/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MediaUploadRepository interface {
+	Create(ctx context.Context, u *model.MediaUpload) error
+	Get(ctx context.Context, id uuid.UUID) (*model.MediaUpload, error)
+	SetStatus(ctx context.Context, id uuid.UUID, status string, assetID *uuid.UUID) error
+	Complete(ctx context.Context, id uuid.UUID, asset *model.MediaAsset) error
+}
+
+type mediaUploadRepository struct {
+	db     *gorm.DB
+	events EventPublisher
+}
+
+func NewMediaUploadRepository(db *gorm.DB, events EventPublisher) MediaUploadRepository {
+	return &mediaUploadRepository{db: db, events: events}
+}
+
+func (r *mediaUploadRepository) Create(ctx context.Context, u *model.MediaUpload) error {
+	return r.db.WithContext(ctx).Create(u).Error
+}
+
+func (r *mediaUploadRepository) Get(ctx context.Context, id uuid.UUID) (*model.MediaUpload, error) {
+	var u model.MediaUpload
+	if err := r.db.WithContext(ctx).First(&u, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// SetStatus hanya mengubah sesi yang masih pending, sehingga complete/abort
+// yang dikirim bersamaan tidak saling menimpa. Mengembalikan
+// gorm.ErrRecordNotFound bila sesi sudah tidak pending.
+func (r *mediaUploadRepository) SetStatus(ctx context.Context, id uuid.UUID, status string, assetID *uuid.UUID) error {
+	res := r.db.WithContext(ctx).Model(&model.MediaUpload{}).
+		Where("id = ? AND status = ?", id, model.UploadPending).
+		Updates(map[string]interface{}{
+			"status":   status,
+			"asset_id": assetID,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Complete menandai sesi selesai dan menyimpan MediaAsset-nya dalam satu
+// transaksi, sehingga sesi yang sama tidak pernah menghasilkan dua asset.
+func (r *mediaUploadRepository) Complete(ctx context.Context, id uuid.UUID, asset *model.MediaAsset) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var events EventPublisher
+		if r.events != nil {
+			events = NewEventPublisher(tx)
+		}
+		txRepo := &mediaUploadRepository{db: tx}
+		if err := txRepo.SetStatus(ctx, id, model.UploadCompleted, nil); err != nil {
+			return err
+		}
+		if err := NewMediaRepository(tx, events).Save(ctx, asset); err != nil {
+			return err
+		}
+		return tx.Model(&model.MediaUpload{}).Where("id = ?", id).Update("asset_id", asset.ID).Error
+	})
+}
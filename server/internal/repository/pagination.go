@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxPageSize adalah jumlah item maksimum per halaman list.
+const MaxPageSize = 100
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Page adalah permintaan satu halaman list. Cursor (dari PageInfo.Next atau
+// Prev) bersifat opaque; tanpa Cursor halaman dimulai dari awal, digeser
+// Offset untuk client lama.
+type Page struct {
+	Limit  int
+	Cursor string
+	Offset int
+	// Total meminta jumlah total item (satu COUNT tambahan).
+	Total bool
+}
+
+// PageInfo adalah navigasi hasil list; Next/Prev nil bila tidak ada
+// halaman berikut/sebelumnya.
+type PageInfo struct {
+	Limit int
+	Next  *string
+	Prev  *string
+	Total *int64
+}
+
+// keyset adalah urutan list: kolom sort lalu id sebagai pemecah seri,
+// dengan arah yang sama. Cursor menyimpan nilai keduanya dari item terakhir
+// (atau pertama) sehingga halaman berikut tidak bergeser saat ada item
+// baru, dan tidak perlu OFFSET.
+type keyset struct {
+	// sort adalah nama urutan; cursor dari urutan lain ditolak
+	sort string
+	// column adalah kolom waktu urutan; kosong berarti hanya id (bigint)
+	column string
+	desc   bool
+}
+
+type cursor struct {
+	Sort  string    `json:"s"`
+	Value time.Time `json:"v,omitempty"`
+	ID    string    `json:"id"`
+	// Prev berarti cursor menuju halaman sebelumnya
+	Prev bool `json:"p,omitempty"`
+}
+
+func (k keyset) encode(value time.Time, id string, prev bool) *string {
+	b, _ := json.Marshal(cursor{Sort: k.sort, Value: value, ID: id, Prev: prev})
+	s := base64.RawURLEncoding.EncodeToString(b)
+	return &s
+}
+
+func (k keyset) decode(s string) (*cursor, []any, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, nil, ErrInvalidCursor
+	}
+	var c cursor
+	if json.Unmarshal(b, &c) != nil || c.Sort != k.sort {
+		return nil, nil, ErrInvalidCursor
+	}
+	if k.column == "" {
+		id, err := strconv.ParseUint(c.ID, 10, 64)
+		if err != nil {
+			return nil, nil, ErrInvalidCursor
+		}
+		return &c, []any{id}, nil
+	}
+	id, err := uuid.Parse(c.ID)
+	if err != nil {
+		return nil, nil, ErrInvalidCursor
+	}
+	return &c, []any{c.Value, id}, nil
+}
+
+// paginate menjalankan query list db (yang sudah berisi filter) untuk
+// satu halaman. Limit dibatasi 1..MaxPageSize. key mengembalikan nilai kolom sort dan id sebuah item.
+func paginate[T any](db *gorm.DB, page Page, k keyset, key func(*T) (time.Time, string)) ([]T, PageInfo, error) {
+	page.Limit = min(max(page.Limit, 1), MaxPageSize)
+	info := PageInfo{Limit: page.Limit}
+	var cur *cursor
+	var args []any
+	if page.Cursor != "" {
+		var err error
+		if cur, args, err = k.decode(page.Cursor); err != nil {
+			return nil, info, err
+		}
+	}
+
+	if page.Total {
+		var total int64
+		if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+			return nil, info, err
+		}
+		info.Total = &total
+	}
+
+	// halaman sebelumnya diambil dengan urutan terbalik lalu dibalik lagi
+	backward := cur != nil && cur.Prev
+	desc := k.desc != backward
+	op, dir := ">", "ASC"
+	if desc {
+		op, dir = "<", "DESC"
+	}
+	q := db.Session(&gorm.Session{})
+	switch {
+	case cur != nil && k.column == "":
+		q = q.Where("id "+op+" ?", args...)
+	case cur != nil:
+		q = q.Where("("+k.column+", id) "+op+" (?, ?)", args...)
+	case page.Offset > 0:
+		q = q.Offset(page.Offset)
+	}
+	if k.column != "" {
+		q = q.Order(k.column + " " + dir)
+	}
+	var items []T
+	if err := q.Order("id " + dir).Limit(page.Limit + 1).Find(&items).Error; err != nil {
+		return nil, info, err
+	}
+
+	more := len(items) > page.Limit
+	if more {
+		items = items[:page.Limit]
+	}
+	hasNext, hasPrev := more, cur != nil || page.Offset > 0
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+		hasNext, hasPrev = true, more
+	}
+	if len(items) > 0 {
+		if hasNext {
+			v, id := key(&items[len(items)-1])
+			info.Next = k.encode(v, id, false)
+		}
+		if hasPrev {
+			v, id := key(&items[0])
+			info.Prev = k.encode(v, id, true)
+		}
+	}
+	return items, info, nil
+}
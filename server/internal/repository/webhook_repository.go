@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cms/server/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WebhookRepository interface {
+	List(ctx context.Context) ([]model.Webhook, error)
+	Get(ctx context.Context, id uuid.UUID) (*model.Webhook, error)
+	Create(ctx context.Context, wh *model.Webhook) error
+	Update(ctx context.Context, wh *model.Webhook) error
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit, offset int) ([]model.WebhookDelivery, int64, error)
+	GetDelivery(ctx context.Context, webhookID, id uuid.UUID) (*model.WebhookDelivery, error)
+	Redeliver(ctx context.Context, d *model.WebhookDelivery) (*model.WebhookDelivery, error)
+
+	// ClaimDue mengambil delivery yang sudah waktunya dikirim dan menunda
+	// next_attempt_at selama lease agar tidak diambil worker lain.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error)
+	SaveAttempt(ctx context.Context, d *model.WebhookDelivery) error
+	// Pending menghitung delivery yang belum terkirim (termasuk yang
+	// menunggu retry).
+	Pending(ctx context.Context) (int64, error)
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) List(ctx context.Context) ([]model.Webhook, error) {
+	var list []model.Webhook
+	err := r.db.WithContext(ctx).Order("created_at DESC").Find(&list).Error
+	return list, err
+}
+
+func (r *webhookRepository) Get(ctx context.Context, id uuid.UUID) (*model.Webhook, error) {
+	var wh model.Webhook
+	if err := r.db.WithContext(ctx).First(&wh, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &wh, nil
+}
+
+func (r *webhookRepository) Create(ctx context.Context, wh *model.Webhook) error {
+	return r.db.WithContext(ctx).Create(wh).Error
+}
+
+func (r *webhookRepository) Update(ctx context.Context, wh *model.Webhook) error {
+	return r.db.WithContext(ctx).Model(wh).
+		Select("name", "url", "secret", "events", "active", "updated_at").
+		Updates(wh).Error
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&model.Webhook{}, "id = ?", id).Error
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit, offset int) ([]model.WebhookDelivery, int64, error) {
+	var items []model.WebhookDelivery
+	var total int64
+	db := r.db.WithContext(ctx).Model(&model.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := db.Order("created_at DESC").Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (r *webhookRepository) GetDelivery(ctx context.Context, webhookID, id uuid.UUID) (*model.WebhookDelivery, error) {
+	var d model.WebhookDelivery
+	if err := r.db.WithContext(ctx).First(&d, "webhook_id = ? AND id = ?", webhookID, id).Error; err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Redeliver membuat baris delivery baru dengan payload yang sama sehingga
+// riwayat percobaan sebelumnya tetap tersimpan.
+func (r *webhookRepository) Redeliver(ctx context.Context, d *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	nd := &model.WebhookDelivery{
+		WebhookID:     d.WebhookID,
+		EventID:       d.EventID,
+		Event:         d.Event,
+		Payload:       d.Payload,
+		Status:        model.DeliveryPending,
+		NextAttemptAt: time.Now(),
+	}
+	if err := r.db.WithContext(ctx).Create(nd).Error; err != nil {
+		return nil, err
+	}
+	return nd, nil
+}
+
+func (r *webhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	var items []model.WebhookDelivery
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		time.Now().Add(lease), model.DeliveryPending, limit,
+	).Scan(&items).Error
+	return items, err
+}
+
+func (r *webhookRepository) SaveAttempt(ctx context.Context, d *model.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Model(d).
+		Select("status", "attempts", "next_attempt_at", "last_attempt_at", "last_status_code",
+			"last_error", "last_response", "duration_ms", "delivered_at").
+		Updates(d).Error
+}
+
+func (r *webhookRepository) Pending(ctx context.Context) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&model.WebhookDelivery{}).
+		Where("status = ?", model.DeliveryPending).Count(&n).Error
+	return n, err
+}
@@ -13,6 +13,14 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrNoRole berarti user ada tetapi belum diberi role, sehingga belum
+	// aktif dan tidak bisa login.
+	ErrNoRole = errors.New("failed to get user role")
+)
+
 type AuthService interface {
 	Authenticate(ctx context.Context, email, password string) (*model.AuthUser, error)
 	Register(ctx context.Context, name, email, password string) (*model.AuthUser, error)
@@ -34,14 +42,17 @@ func (s *authService) Authenticate(ctx context.Context, email, password string)
 
 	user, err := s.repo.FindByEmail(ctx, email)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, ErrInvalidCredentials
 	}
 
 	role, err := s.repo.GetRoleByUserID(ctx, user.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNoRole
+	}
 	if err != nil {
 		return nil, errors.New("failed to get user role")
 	}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"crypto/sha256"
+	"errors"
+	"time"
+
+	"cms/server/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultPreviewTTL = time.Hour
+	MaxPreviewTTL     = 7 * 24 * time.Hour
+)
+
+var ErrInvalidPreviewToken = errors.New("invalid or expired preview token")
+
+// PreviewClaims memberi akses baca ke draft satu entry (atau satu versinya)
+// lewat public API tanpa kredensial admin.
+type PreviewClaims struct {
+	ContentType string `json:"ct"`
+	Version     int    `json:"ver,omitempty"`
+	jwt.RegisteredClaims
+}
+
+func (c PreviewClaims) EntryID() (uuid.UUID, error) {
+	return uuid.Parse(c.Subject)
+}
+
+// Covers melaporkan apakah token berlaku untuk entry id milik content type
+// slug; token untuk entry lain tidak boleh membuka draft-nya.
+func (c PreviewClaims) Covers(slug string, id uuid.UUID) bool {
+	entryID, err := c.EntryID()
+	return err == nil && entryID == id && c.ContentType == slug
+}
+
+type PreviewService interface {
+	Issue(slug string, entryID uuid.UUID, version int, ttl time.Duration) (string, time.Time, error)
+	Verify(token string) (*PreviewClaims, error)
+}
+
+type previewService struct {
+	key []byte
+}
+
+// NewPreviewService memakai key turunan dari JWT secret sehingga token
+// preview tidak bisa dipakai sebagai token login, dan sebaliknya.
+func NewPreviewService(cfg config.Config) PreviewService {
+	sum := sha256.Sum256([]byte("preview:" + cfg.JWTSecret))
+	return &previewService{key: sum[:]}
+}
+
+func (s *previewService) Issue(slug string, entryID uuid.UUID, version int, ttl time.Duration) (string, time.Time, error) {
+	if ttl <= 0 {
+		ttl = DefaultPreviewTTL
+	}
+	if ttl > MaxPreviewTTL {
+		ttl = MaxPreviewTTL
+	}
+	now := time.Now()
+	exp := now.Add(ttl)
+	t := jwt.NewWithClaims(jwt.SigningMethodHS256, PreviewClaims{
+		ContentType: slug,
+		Version:     version,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   entryID.String(),
+			Audience:  jwt.ClaimStrings{"preview"},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(exp),
+		},
+	})
+	signed, err := t.SignedString(s.key)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, exp, nil
+}
+
+func (s *previewService) Verify(token string) (*PreviewClaims, error) {
+	var claims PreviewClaims
+	t, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		return s.key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience("preview"), jwt.WithExpirationRequired())
+	if err != nil || !t.Valid {
+		return nil, ErrInvalidPreviewToken
+	}
+	if _, err := claims.EntryID(); err != nil {
+		return nil, ErrInvalidPreviewToken
+	}
+	return &claims, nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cms/server/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const testSecret = "test-jwt-secret"
+
+func newTestPreview() *previewService {
+	return NewPreviewService(config.Config{JWTSecret: testSecret}).(*previewService)
+}
+
+func TestPreviewIssueVerify(t *testing.T) {
+	svc := newTestPreview()
+	id := uuid.New()
+
+	token, exp, err := svc.Issue("post", id, 3, 0)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if d := time.Until(exp); d < DefaultPreviewTTL-time.Minute || d > DefaultPreviewTTL {
+		t.Errorf("default ttl = %v, want %v", d, DefaultPreviewTTL)
+	}
+	claims, err := svc.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !claims.Covers("post", id) || claims.Version != 3 {
+		t.Errorf("claims = %+v, want post/%s version 3", claims, id)
+	}
+}
+
+func TestPreviewTTLIsCapped(t *testing.T) {
+	svc := newTestPreview()
+	token, exp, err := svc.Issue("post", uuid.New(), 0, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if d := time.Until(exp); d > MaxPreviewTTL || d < MaxPreviewTTL-time.Minute {
+		t.Errorf("ttl = %v, want capped to %v", d, MaxPreviewTTL)
+	}
+	claims, err := svc.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if got := claims.ExpiresAt.Time; !got.Equal(exp.Truncate(time.Second)) {
+		t.Errorf("exp claim = %v, want %v", got, exp)
+	}
+}
+
+func TestPreviewVerifyRejects(t *testing.T) {
+	svc := newTestPreview()
+	now := time.Now()
+	valid := func() PreviewClaims {
+		return PreviewClaims{
+			ContentType: "post",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   uuid.NewString(),
+				Audience:  jwt.ClaimStrings{"preview"},
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			},
+		}
+	}
+	sign := func(method jwt.SigningMethod, claims PreviewClaims, key []byte) string {
+		t.Helper()
+		s, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	wrongAudience := valid()
+	wrongAudience.Audience = jwt.ClaimStrings{"login"}
+	expired := valid()
+	expired.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute))
+	noExpiry := valid()
+	noExpiry.ExpiresAt = nil
+	badSubject := valid()
+	badSubject.Subject = "not-a-uuid"
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"wrong audience", sign(jwt.SigningMethodHS256, wrongAudience, svc.key)},
+		{"expired", sign(jwt.SigningMethodHS256, expired, svc.key)},
+		{"no expiry", sign(jwt.SigningMethodHS256, noExpiry, svc.key)},
+		{"signed with plain jwt secret", sign(jwt.SigningMethodHS256, valid(), []byte(testSecret))},
+		{"other hmac algorithm", sign(jwt.SigningMethodHS512, valid(), svc.key)},
+		{"subject is not an entry id", sign(jwt.SigningMethodHS256, badSubject, svc.key)},
+		{"garbage", "not.a.token"},
+	}
+	for _, tt := range tests {
+		if _, err := svc.Verify(tt.token); !errors.Is(err, ErrInvalidPreviewToken) {
+			t.Errorf("%s: Verify error = %v, want ErrInvalidPreviewToken", tt.name, err)
+		}
+	}
+}
+
+func TestPreviewClaimsCovers(t *testing.T) {
+	id := uuid.New()
+	token, _, err := newTestPreview().Issue("post", id, 0, time.Minute)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	claims, err := newTestPreview().Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	tests := []struct {
+		name string
+		slug string
+		id   uuid.UUID
+		want bool
+	}{
+		{"same entry", "post", id, true},
+		{"other entry", "post", uuid.New(), false},
+		{"other content type", "page", id, false},
+	}
+	for _, tt := range tests {
+		if got := claims.Covers(tt.slug, tt.id); got != tt.want {
+			t.Errorf("%s: Covers = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
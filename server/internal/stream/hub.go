@@ -0,0 +1,185 @@
+// Package stream meneruskan event dari Postgres LISTEN/NOTIFY ke client yang
+// sedang terhubung (mis. lewat Server-Sent Events). Karena sumbernya NOTIFY,
+// event dari instance API mana pun sampai ke semua client.
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"cms/server/internal/model"
+
+	"github.com/lib/pq"
+)
+
+// EventResync dikirim hub setelah listener tersambung ulang: event selama
+// koneksi terputus hilang, sehingga subscriber yang menyimpan turunan data
+// (mis. cache) harus membuang semuanya.
+const EventResync = "stream.resync"
+
+// Message adalah satu event yang diterima dari NOTIFY.
+type Message struct {
+	ID          string
+	Event       string
+	ContentType string
+	Payload     []byte
+}
+
+// Filter menentukan event mana yang dikirim ke subscriber.
+type Filter struct {
+	// Types berisi slug content type; kosong berarti semua.
+	Types map[string]bool
+	Media bool
+	// ContentTypes menerima perubahan definisi content type.
+	ContentTypes bool
+	// Resync menerima EventResync.
+	Resync bool
+}
+
+func (f Filter) match(m Message) bool {
+	switch {
+	case m.Event == EventResync:
+		return f.Resync
+	case strings.HasPrefix(m.Event, "media."):
+		return f.Media
+	case m.Event == model.EventContentTypeChanged:
+		return f.ContentTypes
+	case strings.HasPrefix(m.Event, "entry."):
+		return len(f.Types) == 0 || f.Types[m.ContentType]
+	}
+	return false
+}
+
+type Subscription struct {
+	C      <-chan Message
+	ch     chan Message
+	filter Filter
+}
+
+type Hub struct {
+	dsn string
+
+	mu   sync.RWMutex
+	subs map[*Subscription]struct{}
+	// closed diset saat Run berhenti; subscriber baru langsung ditutup
+	closed bool
+}
+
+func NewHub(dsn string) *Hub {
+	return &Hub{dsn: dsn, subs: map[*Subscription]struct{}{}}
+}
+
+// Subscribe mendaftarkan subscriber baru. Panggil Unsubscribe saat client
+// terputus.
+func (h *Hub) Subscribe(f Filter) *Subscription {
+	ch := make(chan Message, 64)
+	s := &Subscription{C: ch, ch: ch, filter: f}
+	h.mu.Lock()
+	if h.closed {
+		close(ch)
+	} else {
+		h.subs[s] = struct{}{}
+	}
+	h.mu.Unlock()
+	return s
+}
+
+func (h *Hub) Unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	if _, ok := h.subs[s]; ok {
+		delete(h.subs, s)
+		close(s.ch)
+	}
+	h.mu.Unlock()
+}
+
+func (h *Hub) broadcast(m Message) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for s := range h.subs {
+		if !s.filter.match(m) {
+			continue
+		}
+		select {
+		case s.ch <- m:
+		default:
+			// client terlalu lambat, event dilewati agar hub tidak tertahan
+		}
+	}
+}
+
+// close menutup semua subscription sehingga stream SSE yang masih terbuka
+// selesai dan server bisa berhenti.
+func (h *Hub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for s := range h.subs {
+		delete(h.subs, s)
+		close(s.ch)
+	}
+}
+
+// Run mendengarkan channel NOTIFY sampai ctx dibatalkan, lalu menutup semua
+// subscription. pq.Listener otomatis reconnect bila koneksi ke Postgres
+// terputus.
+func (h *Hub) Run(ctx context.Context) {
+	defer h.close()
+	listener := pq.NewListener(h.dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Error("stream listener", "error", err)
+		}
+	})
+	defer listener.Close()
+	// Listen menunggu sampai Postgres terhubung; Close saat ctx dibatalkan
+	// membuatnya kembali sehingga Run tetap bisa berhenti
+	stop := context.AfterFunc(ctx, func() { _ = listener.Close() })
+	defer stop()
+
+	if err := listener.Listen(model.EventsChannel); err != nil && ctx.Err() == nil {
+		slog.ErrorContext(ctx, "stream listener: listen", "channel", model.EventsChannel, "error", err)
+	}
+
+	ping := time.NewTicker(90 * time.Second)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-listener.Notify:
+			// n == nil setelah reconnect; event selama terputus hilang
+			if n == nil {
+				h.broadcast(Message{Event: EventResync})
+				continue
+			}
+			if m, ok := parse(n.Extra); ok {
+				h.broadcast(m)
+			}
+		case <-ping.C:
+			go listener.Ping()
+		}
+	}
+}
+
+func parse(payload string) (Message, bool) {
+	var ev struct {
+		ID    string `json:"id"`
+		Event string `json:"event"`
+		Data  struct {
+			ContentType string `json:"content_type"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(payload), &ev); err != nil {
+		return Message{}, false
+	}
+	return Message{
+		ID:          ev.ID,
+		Event:       ev.Event,
+		ContentType: ev.Data.ContentType,
+		Payload:     []byte(payload),
+	}, true
+}
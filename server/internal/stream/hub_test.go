@@ -0,0 +1,78 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCloseEndsSubscriptions(t *testing.T) {
+	h := NewHub("")
+	sub := h.Subscribe(Filter{})
+	h.close()
+
+	if _, ok := <-sub.C; ok {
+		t.Fatal("subscription masih terbuka setelah hub ditutup")
+	}
+	// Unsubscribe setelah close tidak boleh menutup channel dua kali
+	h.Unsubscribe(sub)
+}
+
+func TestSubscribeAfterClose(t *testing.T) {
+	h := NewHub("")
+	h.close()
+
+	sub := h.Subscribe(Filter{})
+	if _, ok := <-sub.C; ok {
+		t.Fatal("subscription baru setelah hub ditutup seharusnya langsung tertutup")
+	}
+	h.Unsubscribe(sub)
+}
+
+func TestRunStopsWithoutDatabase(t *testing.T) {
+	// port 1 tidak menerima koneksi, sehingga Listen menunggu reconnect
+	h := NewHub("host=127.0.0.1 port=1 sslmode=disable connect_timeout=1")
+	sub := h.Subscribe(Filter{})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		h.Run(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run tidak berhenti setelah ctx dibatalkan saat Postgres tidak bisa dihubungi")
+	}
+	if _, ok := <-sub.C; ok {
+		t.Fatal("subscription masih terbuka setelah Run berhenti")
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		msg    Message
+		want   bool
+	}{
+		{"entry all types", Filter{}, Message{Event: "entry.updated", ContentType: "post"}, true},
+		{"entry filtered type", Filter{Types: map[string]bool{"page": true}}, Message{Event: "entry.updated", ContentType: "post"}, false},
+		{"media uploaded", Filter{Media: true}, Message{Event: "media.uploaded"}, true},
+		{"media updated", Filter{Media: true}, Message{Event: "media.updated"}, true},
+		{"media moved", Filter{Media: true}, Message{Event: "media.moved"}, true},
+		{"media deleted", Filter{Media: true}, Message{Event: "media.deleted"}, true},
+		{"media disabled", Filter{}, Message{Event: "media.deleted"}, false},
+		{"content type changed", Filter{ContentTypes: true}, Message{Event: "content_type.changed"}, true},
+		{"content type not subscribed", Filter{Media: true}, Message{Event: "content_type.changed"}, false},
+		{"resync", Filter{ContentTypes: true, Resync: true}, Message{Event: EventResync}, true},
+		{"resync not subscribed", Filter{Types: map[string]bool{"post": true}, Media: true, ContentTypes: true}, Message{Event: EventResync}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.match(tt.msg); got != tt.want {
+			t.Errorf("%s: match(%s) = %v, want %v", tt.name, tt.msg.Event, got, tt.want)
+		}
+	}
+}
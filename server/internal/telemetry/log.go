@@ -0,0 +1,103 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewLogger membuat logger ke stderr sesuai cfg.LogFormat dan
+// cfg.LogLevel, dengan atribut request dari context.
+func NewLogger(cfg Config) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: parseLevel(cfg.LogLevel)}
+	var h slog.Handler
+	if strings.ToLower(cfg.LogFormat) == "text" {
+		h = slog.NewTextHandler(os.Stderr, opts)
+	} else {
+		h = slog.NewJSONHandler(os.Stderr, opts)
+	}
+	return slog.New(contextHandler{h})
+}
+
+func parseLevel(s string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// requestInfo disimpan sebagai pointer di context agar user_id yang baru
+// diketahui setelah autentikasi ikut terlihat oleh semua log berikutnya.
+type requestInfo struct {
+	id    string
+	route string
+
+	mu     sync.RWMutex
+	userID string
+}
+
+type requestKey struct{}
+
+// WithRequest menandai ctx sebagai milik satu request HTTP.
+func WithRequest(ctx context.Context, id, route string) context.Context {
+	return context.WithValue(ctx, requestKey{}, &requestInfo{id: id, route: route})
+}
+
+// RequestID mengembalikan id request di ctx, atau "" di luar request.
+func RequestID(ctx context.Context) string {
+	if info, ok := ctx.Value(requestKey{}).(*requestInfo); ok {
+		return info.id
+	}
+	return ""
+}
+
+// SetUser mencatat user yang sudah login untuk log dan span request.
+func SetUser(ctx context.Context, userID string) {
+	if info, ok := ctx.Value(requestKey{}).(*requestInfo); ok {
+		info.mu.Lock()
+		info.userID = userID
+		info.mu.Unlock()
+	}
+	trace.SpanFromContext(ctx).SetAttributes(semconv.UserID(userID))
+}
+
+// contextHandler menambahkan request_id, route, user_id, trace_id dan
+// span_id dari context ke setiap record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if info, ok := ctx.Value(requestKey{}).(*requestInfo); ok {
+		r.AddAttrs(slog.String("request_id", info.id))
+		if info.route != "" {
+			r.AddAttrs(slog.String("route", info.route))
+		}
+		info.mu.RLock()
+		if info.userID != "" {
+			r.AddAttrs(slog.String("user_id", info.userID))
+		}
+		info.mu.RUnlock()
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
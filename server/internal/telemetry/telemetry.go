@@ -0,0 +1,84 @@
+// Package telemetry menyiapkan log terstruktur (log/slog) dan tracing
+// OpenTelemetry. Setiap baris log yang ditulis dengan context request
+// otomatis membawa request_id, route, user_id dan trace_id.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// instrumentation adalah nama tracer untuk span yang dibuat package ini.
+const instrumentation = "cms/server"
+
+type Config struct {
+	ServiceName string
+	// LogFormat: json (default) atau text.
+	LogFormat string
+	// LogLevel: debug, info (default), warn atau error.
+	LogLevel string
+	// TracesExporter: none (default), stdout atau otlp. Tujuan otlp diatur
+	// lewat env standar OTEL_EXPORTER_OTLP_ENDPOINT (default
+	// http://localhost:4318).
+	TracesExporter string
+}
+
+// Setup memasang logger slog sebagai default (termasuk output package log)
+// dan tracer provider global. shutdown mengirim span yang tersisa dan
+// harus dipanggil sebelum proses berhenti.
+func Setup(ctx context.Context, cfg Config) (shutdown func(context.Context) error, err error) {
+	slog.SetDefault(NewLogger(cfg))
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	switch strings.ToLower(cfg.TracesExporter) {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stderr))
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown traces exporter %q (expected none, stdout or otlp)", cfg.TracesExporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil && !errors.Is(err, resource.ErrSchemaURLConflict) {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+func tracer() trace.Tracer { return otel.Tracer(instrumentation) }
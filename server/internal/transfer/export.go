@@ -0,0 +1,226 @@
+package transfer
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"cms/server/internal/model"
+	"cms/server/pkg/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Service struct {
+	db    *gorm.DB
+	store storage.Storage
+}
+
+// New membuat service transfer. store boleh nil; file media tidak akan
+// disertakan saat export dan tidak di-upload saat import.
+func New(db *gorm.DB, store storage.Storage) *Service {
+	return &Service{db: db, store: store}
+}
+
+type ExportOptions struct {
+	// Types berisi slug content type yang diexport, kosong berarti semua.
+	Types    []string
+	Versions bool
+	Media    bool
+}
+
+const exportBatchSize = 500
+
+// Export menulis arsip zip ke w.
+func (s *Service) Export(ctx context.Context, w io.Writer, opts ExportOptions) error {
+	db := s.db.WithContext(ctx)
+
+	var cts []model.ContentType
+	q := db.Preload("Fields").Order("slug")
+	if len(opts.Types) > 0 {
+		q = q.Where("slug IN ?", opts.Types)
+	}
+	if err := q.Find(&cts).Error; err != nil {
+		return err
+	}
+	if len(opts.Types) > 0 && len(cts) != len(opts.Types) {
+		return fmt.Errorf("some content types not found: %v", opts.Types)
+	}
+
+	zw := zip.NewWriter(w)
+
+	ctRecords := make([]ContentTypeRecord, 0, len(cts))
+	slugs := make([]string, 0, len(cts))
+	for _, ct := range cts {
+		rec := ContentTypeRecord{ID: ct.ID, Name: ct.Name, Slug: ct.Slug, Fields: []FieldRecord{}}
+		for _, f := range ct.Fields {
+			rec.Fields = append(rec.Fields, FieldRecord{Name: f.Name, Kind: f.Kind, Options: f.Options})
+		}
+		ctRecords = append(ctRecords, rec)
+		slugs = append(slugs, ct.Slug)
+	}
+	if err := writeJSON(zw, fileContentTypes, ctRecords); err != nil {
+		return err
+	}
+
+	var entryIDs []uuid.UUID
+	mediaIDs := map[uuid.UUID]struct{}{}
+	ew, err := zw.Create(fileEntries)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(ew)
+	for _, ct := range cts {
+		fields := fieldKinds(ct.Fields)
+		var batch []model.Entry
+		err := db.Where("content_type_id = ?", ct.ID).
+			FindInBatches(&batch, exportBatchSize, func(_ *gorm.DB, _ int) error {
+				for _, e := range batch {
+					if err := enc.Encode(EntryRecord{
+						ID:              e.ID,
+						ContentTypeSlug: ct.Slug,
+						Slug:            e.Slug,
+						Status:          e.Status,
+						Data:            e.Data,
+						PublishedAt:     e.PublishedAt,
+						CreatedAt:       e.CreatedAt,
+						UpdatedAt:       e.UpdatedAt,
+					}); err != nil {
+						return err
+					}
+					entryIDs = append(entryIDs, e.ID)
+					if opts.Media {
+						collectRefs(e.Data, fields, kindImage, func(id uuid.UUID) { mediaIDs[id] = struct{}{} })
+					}
+				}
+				return nil
+			}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.Versions {
+		vw, err := zw.Create(fileVersions)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(vw)
+		for start := 0; start < len(entryIDs); start += exportBatchSize {
+			end := min(start+exportBatchSize, len(entryIDs))
+			var versions []model.EntryVersion
+			if err := db.Where("entry_id IN ?", entryIDs[start:end]).
+				Order("entry_id, version").Find(&versions).Error; err != nil {
+				return err
+			}
+			for _, v := range versions {
+				if err := enc.Encode(VersionRecord{
+					EntryID:   v.EntryID,
+					Version:   v.Version,
+					Data:      v.Data,
+					CreatedAt: v.CreatedAt,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	withMedia := opts.Media && s.store != nil
+	if withMedia {
+		if err := s.exportMedia(ctx, zw, mediaIDs); err != nil {
+			return err
+		}
+	}
+
+	if err := writeJSON(zw, fileManifest, Manifest{
+		Format:     FormatName,
+		Version:    FormatVersion,
+		ExportedAt: time.Now().UTC(),
+		Types:      slugs,
+		Versions:   opts.Versions,
+		Media:      withMedia,
+	}); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func (s *Service) exportMedia(ctx context.Context, zw *zip.Writer, ids map[uuid.UUID]struct{}) error {
+	list := make([]uuid.UUID, 0, len(ids))
+	for id := range ids {
+		list = append(list, id)
+	}
+
+	var assets []model.MediaAsset
+	if len(list) > 0 {
+		if err := s.db.WithContext(ctx).Where("id IN ?", list).Order("created_at").Find(&assets).Error; err != nil {
+			return err
+		}
+	}
+
+	records := make([]MediaRecord, 0, len(assets))
+	for _, a := range assets {
+		rec := MediaRecord{
+			ID:        a.ID,
+			Filename:  a.Filename,
+			Mime:      a.Mime,
+			SizeBytes: a.SizeBytes,
+			Meta:      a.Meta,
+			Tags:      a.Tags,
+			AltText:   a.AltText,
+			Caption:   a.Caption,
+			Credit:    a.Credit,
+			Checksum:  a.Checksum,
+			File:      dirMedia + a.ID.String() + filepath.Ext(a.URL),
+		}
+		data, err := storage.Download(ctx, s.store, a.URL)
+		if err != nil {
+			return fmt.Errorf("download media %s: %w", a.ID, err)
+		}
+		fw, err := zw.Create(rec.File)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(data); err != nil {
+			return err
+		}
+		records = append(records, rec)
+	}
+
+	mw, err := zw.Create(fileMedia)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(mw)
+	for _, rec := range records {
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSON(zw *zip.Writer, name string, v any) error {
+	fw, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(fw)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func fieldKinds(fields []model.ContentField) map[string]string {
+	m := make(map[string]string, len(fields))
+	for _, f := range fields {
+		m[f.Name] = f.Kind
+	}
+	return m
+}
@@ -0,0 +1,164 @@
+// Package transfer memindahkan content type, entry dan media antar instance CMS
+// menggunakan arsip zip yang portabel.
+//
+// Isi arsip:
+//
+//	manifest.json        informasi format & apa saja yang disertakan
+//	content_types.json   skema content type beserta field-nya
+//	entries.ndjson       satu entry per baris
+//	versions.ndjson      riwayat versi entry (opsional)
+//	media.ndjson         metadata media yang dirujuk entry (opsional)
+//	media/<id><ext>      file media (opsional)
+package transfer
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	FormatName    = "cms-export"
+	FormatVersion = 1
+
+	fileManifest     = "manifest.json"
+	fileContentTypes = "content_types.json"
+	fileEntries      = "entries.ndjson"
+	fileVersions     = "versions.ndjson"
+	fileMedia        = "media.ndjson"
+	dirMedia         = "media/"
+)
+
+type Manifest struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	Types      []string  `json:"types"`
+	Versions   bool      `json:"versions"`
+	Media      bool      `json:"media"`
+}
+
+type ContentTypeRecord struct {
+	ID     uuid.UUID     `json:"id"`
+	Name   string        `json:"name"`
+	Slug   string        `json:"slug"`
+	Fields []FieldRecord `json:"fields"`
+}
+
+type FieldRecord struct {
+	Name    string          `json:"name"`
+	Kind    string          `json:"kind"`
+	Options json.RawMessage `json:"options,omitempty"`
+}
+
+type EntryRecord struct {
+	ID              uuid.UUID       `json:"id"`
+	ContentTypeSlug string          `json:"content_type"`
+	Slug            string          `json:"slug"`
+	Status          string          `json:"status"`
+	Data            json.RawMessage `json:"data"`
+	PublishedAt     *time.Time      `json:"published_at,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+type VersionRecord struct {
+	EntryID   uuid.UUID       `json:"entry_id"`
+	Version   int             `json:"version"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type MediaRecord struct {
+	ID        uuid.UUID       `json:"id"`
+	Filename  string          `json:"filename"`
+	Mime      string          `json:"mime"`
+	SizeBytes int64           `json:"size_bytes"`
+	Meta      json.RawMessage `json:"meta,omitempty"`
+	Tags      []string        `json:"tags,omitempty"`
+	AltText   string          `json:"alt_text,omitempty"`
+	Caption   string          `json:"caption,omitempty"`
+	Credit    string          `json:"credit,omitempty"`
+	Checksum  string          `json:"checksum,omitempty"`
+	File      string          `json:"file,omitempty"`
+}
+
+// Field kind yang nilainya berupa id dan perlu di-remap saat import.
+const (
+	kindImage    = "image"
+	kindRelation = "relation"
+)
+
+// collectRefs mengambil semua id yang dirujuk field dengan kind tertentu.
+// Nilai field boleh berupa string id, array string id, atau object {"id": ...}.
+func collectRefs(data json.RawMessage, fields map[string]string, kind string, fn func(uuid.UUID)) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return
+	}
+	for name, k := range fields {
+		if k != kind {
+			continue
+		}
+		walkRefs(doc[name], func(s string) string {
+			if id, err := uuid.Parse(s); err == nil {
+				fn(id)
+			}
+			return s
+		})
+	}
+}
+
+// remapRefs mengganti id lama pada field dengan kind tertentu menggunakan mapping.
+func remapRefs(data json.RawMessage, fields map[string]string, kind string, mapping map[uuid.UUID]uuid.UUID) json.RawMessage {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return data
+	}
+	changed := false
+	for name, k := range fields {
+		if k != kind {
+			continue
+		}
+		if v, ok := doc[name]; ok {
+			doc[name] = walkRefs(v, func(s string) string {
+				id, err := uuid.Parse(s)
+				if err != nil {
+					return s
+				}
+				if to, ok := mapping[id]; ok && to != id {
+					changed = true
+					return to.String()
+				}
+				return s
+			})
+		}
+	}
+	if !changed {
+		return data
+	}
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+func walkRefs(v any, fn func(string) string) any {
+	switch t := v.(type) {
+	case string:
+		return fn(t)
+	case []any:
+		for i := range t {
+			t[i] = walkRefs(t[i], fn)
+		}
+		return t
+	case map[string]any:
+		if s, ok := t["id"].(string); ok {
+			t["id"] = fn(s)
+		}
+		return t
+	}
+	return v
+}
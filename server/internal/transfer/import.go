@@ -0,0 +1,862 @@
+package transfer
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+
+	"cms/server/internal/model"
+	"cms/server/internal/repository"
+	"cms/server/internal/upload"
+	"cms/server/pkg/storage"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// Strategi saat data yang diimport sudah ada di tujuan.
+// Content type dicocokkan berdasarkan slug, entry berdasarkan (content type, slug),
+// media berdasarkan id.
+const (
+	StrategySkip      = "skip"
+	StrategyOverwrite = "overwrite"
+	StrategyRename    = "rename"
+)
+
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionSkipped = "skipped"
+	ActionRenamed = "renamed"
+	ActionFailed  = "failed"
+)
+
+type ImportOptions struct {
+	Strategy string
+	DryRun   bool
+	ActorID  *uuid.UUID
+}
+
+type Counts struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+	Renamed int `json:"renamed"`
+	Failed  int `json:"failed"`
+}
+
+func (c *Counts) add(action string) {
+	switch action {
+	case ActionCreated:
+		c.Created++
+	case ActionUpdated:
+		c.Updated++
+	case ActionSkipped:
+		c.Skipped++
+	case ActionRenamed:
+		c.Renamed++
+	case ActionFailed:
+		c.Failed++
+	}
+}
+
+type ReportItem struct {
+	Kind   string     `json:"kind"`
+	Key    string     `json:"key"`
+	Action string     `json:"action"`
+	OldID  uuid.UUID  `json:"old_id"`
+	NewID  *uuid.UUID `json:"new_id,omitempty"`
+	Error  string     `json:"error,omitempty"`
+}
+
+type Report struct {
+	DryRun       bool         `json:"dry_run"`
+	Strategy     string       `json:"strategy"`
+	ContentTypes Counts       `json:"content_types"`
+	Entries      Counts       `json:"entries"`
+	Media        Counts       `json:"media"`
+	Items        []ReportItem `json:"items"`
+}
+
+func (r *Report) record(kind, key, action string, oldID uuid.UUID, newID *uuid.UUID, err error) {
+	item := ReportItem{Kind: kind, Key: key, Action: action, OldID: oldID, NewID: newID}
+	if err != nil {
+		item.Action = ActionFailed
+		item.Error = err.Error()
+	}
+	switch kind {
+	case "content_type":
+		r.ContentTypes.add(item.Action)
+	case "entry":
+		r.Entries.add(item.Action)
+	case "media":
+		r.Media.add(item.Action)
+	}
+	r.Items = append(r.Items, item)
+}
+
+var (
+	ErrInvalidArchive  = errors.New("invalid export archive")
+	ErrInvalidStrategy = errors.New("invalid conflict strategy")
+	errDryRun          = errors.New("dry run")
+)
+
+type importer struct {
+	tx     *gorm.DB
+	store  storage.Storage
+	opts   ImportOptions
+	report *Report
+
+	ctIDs    map[string]uuid.UUID         // slug di arsip -> id di tujuan
+	ctSlugs  map[string]string            // slug di arsip -> slug di tujuan
+	ctFields map[string]map[string]string // slug di arsip -> nama field -> kind
+	mediaMap map[uuid.UUID]uuid.UUID      // id media lama -> baru
+	entryMap map[uuid.UUID]uuid.UUID      // id entry lama -> baru
+	created  map[uuid.UUID]string         // id entry lama yang dibuat baru -> slug content type
+	pending  map[uuid.UUID]pendingEntry   // id entry baru -> data yang punya relasi
+
+	// perubahan yang diumumkan lewat EventPublisher setelah import selesai
+	changedCTs     []changedContentType
+	changedEntries []changedEntry
+	uploaded       []model.MediaAsset
+	// object yang di-upload ke storage selama import
+	written []string
+}
+
+type changedContentType struct {
+	action string
+	id     uuid.UUID
+	slug   string
+}
+
+type changedEntry struct {
+	event  string
+	id     uuid.UUID
+	ctSlug string // slug content type di tujuan
+}
+
+type pendingEntry struct {
+	data   json.RawMessage
+	fields map[string]string
+}
+
+// Import membaca arsip hasil Export dan menuliskannya ke database dalam satu
+// transaksi. Pada mode dry-run transaksi selalu di-rollback dan file media
+// tidak di-upload, sehingga hanya laporan yang dihasilkan.
+func (s *Service) Import(ctx context.Context, r io.ReaderAt, size int64, opts ImportOptions) (*Report, error) {
+	if opts.Strategy == "" {
+		opts.Strategy = StrategySkip
+	}
+	switch opts.Strategy {
+	case StrategySkip, StrategyOverwrite, StrategyRename:
+	default:
+		return nil, ErrInvalidStrategy
+	}
+
+	files, cts, err := openArchive(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{DryRun: opts.DryRun, Strategy: opts.Strategy, Items: []ReportItem{}}
+	var imp *importer
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		imp = &importer{
+			tx:       tx,
+			store:    s.store,
+			opts:     opts,
+			report:   report,
+			ctIDs:    map[string]uuid.UUID{},
+			ctSlugs:  map[string]string{},
+			ctFields: map[string]map[string]string{},
+			mediaMap: map[uuid.UUID]uuid.UUID{},
+			entryMap: map[uuid.UUID]uuid.UUID{},
+			created:  map[uuid.UUID]string{},
+			pending:  map[uuid.UUID]pendingEntry{},
+		}
+		if opts.DryRun {
+			imp.store = nil
+		}
+		for _, ct := range cts {
+			imp.importContentType(ct)
+		}
+		if err := imp.importMedia(ctx, files); err != nil {
+			return err
+		}
+		if err := imp.importEntries(files); err != nil {
+			return err
+		}
+		if err := imp.importVersions(files); err != nil {
+			return err
+		}
+		if err := imp.resolveRelations(); err != nil {
+			return err
+		}
+		if !opts.DryRun {
+			if err := imp.publishEvents(ctx); err != nil {
+				return err
+			}
+		}
+
+		meta, _ := json.Marshal(map[string]any{
+			"strategy":      opts.Strategy,
+			"content_types": report.ContentTypes,
+			"entries":       report.Entries,
+			"media":         report.Media,
+		})
+		if err := tx.Create(&model.AuditLog{
+			ActorID:  opts.ActorID,
+			Action:   "import",
+			Resource: "transfer",
+			Meta:     meta,
+		}).Error; err != nil {
+			return err
+		}
+
+		if opts.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if imp != nil && len(imp.written) > 0 {
+		s.discardUnused(ctx, imp.written)
+	}
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return report, nil
+}
+
+// savepoint menjalankan fn dalam nested transaction agar kegagalan satu item
+// tidak membatalkan seluruh import.
+func (imp *importer) savepoint(fn func(tx *gorm.DB) error) error {
+	return imp.tx.Transaction(fn)
+}
+
+func (imp *importer) importContentType(rec ContentTypeRecord) {
+	var newID uuid.UUID
+	action, destSlug := ActionCreated, rec.Slug
+	err := imp.savepoint(func(tx *gorm.DB) error {
+		var existing model.ContentType
+		err := tx.Where("slug = ?", rec.Slug).First(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		found := err == nil
+
+		name, slug := rec.Name, rec.Slug
+		if found {
+			switch imp.opts.Strategy {
+			case StrategySkip:
+				newID, action = existing.ID, ActionSkipped
+				return nil
+			case StrategyOverwrite:
+				newID, action = existing.ID, ActionUpdated
+				if err := tx.Model(&existing).Update("name", rec.Name).Error; err != nil {
+					return err
+				}
+				if err := tx.Where("content_type_id = ?", existing.ID).Delete(&model.ContentField{}).Error; err != nil {
+					return err
+				}
+				return createFields(tx, existing.ID, rec.Fields)
+			case StrategyRename:
+				action = ActionRenamed
+				if name, slug, err = uniqueContentType(tx, rec.Name, rec.Slug); err != nil {
+					return err
+				}
+			}
+		} else {
+			// slug belum ada, tapi nama content type juga unik
+			var n int64
+			if err := tx.Model(&model.ContentType{}).Where("name = ?", name).Count(&n).Error; err != nil {
+				return err
+			}
+			if n > 0 {
+				action = ActionRenamed
+				if name, slug, err = uniqueContentType(tx, rec.Name, rec.Slug); err != nil {
+					return err
+				}
+			}
+		}
+
+		ct := model.ContentType{Name: name, Slug: slug}
+		if err := tx.Create(&ct).Error; err != nil {
+			return err
+		}
+		newID, destSlug = ct.ID, ct.Slug
+		return createFields(tx, ct.ID, rec.Fields)
+	})
+
+	fields := make(map[string]string, len(rec.Fields))
+	for _, f := range rec.Fields {
+		fields[f.Name] = f.Kind
+	}
+	imp.ctFields[rec.Slug] = fields
+	if err == nil {
+		imp.ctIDs[rec.Slug] = newID
+		imp.ctSlugs[rec.Slug] = destSlug
+		switch action {
+		case ActionCreated, ActionRenamed:
+			imp.changedCTs = append(imp.changedCTs, changedContentType{"created", newID, destSlug})
+		case ActionUpdated:
+			imp.changedCTs = append(imp.changedCTs, changedContentType{"updated", newID, destSlug})
+		}
+		imp.report.record("content_type", rec.Slug, action, rec.ID, &newID, nil)
+	} else {
+		imp.report.record("content_type", rec.Slug, action, rec.ID, nil, err)
+	}
+}
+
+func createFields(tx *gorm.DB, ctID uuid.UUID, fields []FieldRecord) error {
+	for _, f := range fields {
+		opts := f.Options
+		if len(opts) == 0 {
+			opts = json.RawMessage(`{}`)
+		}
+		if err := tx.Create(&model.ContentField{
+			ContentTypeID: ctID,
+			Name:          f.Name,
+			Kind:          f.Kind,
+			Options:       opts,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func uniqueContentType(tx *gorm.DB, name, slug string) (string, string, error) {
+	for i := 1; ; i++ {
+		suffix := "-imported"
+		nameSuffix := " (imported)"
+		if i > 1 {
+			suffix += "-" + strconv.Itoa(i)
+			nameSuffix = " (imported " + strconv.Itoa(i) + ")"
+		}
+		var n int64
+		if err := tx.Model(&model.ContentType{}).
+			Where("slug = ? OR name = ?", slug+suffix, name+nameSuffix).
+			Count(&n).Error; err != nil {
+			return "", "", err
+		}
+		if n == 0 {
+			return name + nameSuffix, slug + suffix, nil
+		}
+	}
+}
+
+func (imp *importer) importMedia(ctx context.Context, files map[string]*zip.File) error {
+	return eachLine(files, fileMedia, func(line []byte) error {
+		var rec MediaRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, fileMedia, err)
+		}
+
+		var newID uuid.UUID
+		action := ActionCreated
+		err := imp.savepoint(func(tx *gorm.DB) error {
+			var existing model.MediaAsset
+			err := tx.First(&existing, "id = ?", rec.ID).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if err == nil && imp.opts.Strategy != StrategyRename {
+				newID = existing.ID
+				if imp.opts.Strategy == StrategySkip {
+					action = ActionSkipped
+					return nil
+				}
+				action = ActionUpdated
+				// object lama bisa dipakai asset lain, jadi file baru tidak
+				// menimpanya; object yang tidak lagi dipakai dibersihkan
+				// oleh `cms media-reconcile`
+				objectName, checksum, err := imp.putObject(ctx, tx, files, rec)
+				if err != nil {
+					return err
+				}
+				updates := map[string]any{
+					"filename":   rec.Filename,
+					"mime":       rec.Mime,
+					"size_bytes": rec.SizeBytes,
+					"meta":       metaOrEmpty(rec.Meta),
+					"tags":       pq.StringArray(repository.NormalizeTags(rec.Tags)),
+					"alt_text":   rec.AltText,
+					"caption":    rec.Caption,
+					"credit":     rec.Credit,
+				}
+				if objectName != "" {
+					updates["url"] = objectName
+					updates["checksum"] = checksum
+				}
+				if err := tx.Model(&existing).Updates(updates).Error; err != nil {
+					return err
+				}
+				return imp.enqueueProcessing(ctx, tx, existing.ID, rec.Mime)
+			}
+			if err == nil {
+				action = ActionRenamed
+			}
+
+			objectName, checksum, err := imp.putObject(ctx, tx, files, rec)
+			if err != nil {
+				return err
+			}
+			if objectName == "" {
+				// tanpa file, asset baru akan menunjuk object yang tidak ada
+				if !imp.opts.DryRun {
+					if rec.File == "" {
+						return errors.New("media file is not included in the archive (export with media)")
+					}
+					return errors.New("no media storage configured")
+				}
+				objectName = uuid.New().String() + filepath.Ext(rec.File)
+				checksum = rec.Checksum
+			}
+			asset := model.MediaAsset{
+				Filename:  rec.Filename,
+				Mime:      rec.Mime,
+				SizeBytes: rec.SizeBytes,
+				URL:       objectName,
+				Checksum:  checksum,
+				Meta:      metaOrEmpty(rec.Meta),
+				Tags:      repository.NormalizeTags(rec.Tags),
+				AltText:   rec.AltText,
+				Caption:   rec.Caption,
+				Credit:    rec.Credit,
+				CreatedBy: imp.opts.ActorID,
+			}
+			if err := tx.Create(&asset).Error; err != nil {
+				return err
+			}
+			newID = asset.ID
+			if err := imp.enqueueProcessing(ctx, tx, asset.ID, rec.Mime); err != nil {
+				return err
+			}
+			imp.uploaded = append(imp.uploaded, asset)
+			return nil
+		})
+		if err != nil {
+			imp.report.record("media", rec.Filename, action, rec.ID, nil, err)
+			return nil
+		}
+		imp.mediaMap[rec.ID] = newID
+		imp.report.record("media", rec.Filename, action, rec.ID, &newID, nil)
+		return nil
+	})
+}
+
+// enqueueProcessing menjadwalkan ulang ekstraksi metadata & poster. Poster
+// (renditions/<id>/) tidak ikut diekspor sehingga harus dibuat lagi.
+func (imp *importer) enqueueProcessing(ctx context.Context, tx *gorm.DB, id uuid.UUID, mime string) error {
+	if !repository.NeedsProcessing(mime) {
+		return nil
+	}
+	_, err := repository.NewMediaJobRepository(tx).Enqueue(ctx, id)
+	return err
+}
+
+// putObject menyimpan file rec dari arsip ke object content-addressed dan
+// mengembalikan nama object beserta checksum-nya. Bila isi yang sama sudah
+// tersimpan, object itu dipakai ulang. Tanpa store atau file, nama kosong.
+func (imp *importer) putObject(ctx context.Context, tx *gorm.DB, files map[string]*zip.File, rec MediaRecord) (string, string, error) {
+	if imp.store == nil || rec.File == "" {
+		return "", "", nil
+	}
+	f, ok := files[rec.File]
+	if !ok {
+		return "", "", fmt.Errorf("file %s not found in archive", rec.File)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return "", "", err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return "", "", err
+	}
+	checksum := upload.Checksum(data)
+	var obj model.MediaObject
+	err = tx.Where("checksum = ? AND ref_count > 0", checksum).Order("created_at").First(&obj).Error
+	if err == nil {
+		return obj.ObjectName, checksum, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", "", err
+	}
+	objectName := upload.ObjectName(checksum, filepath.Ext(rec.File))
+	imp.written = append(imp.written, objectName)
+	return objectName, checksum, storage.Upload(ctx, imp.store, objectName, rec.Mime, data)
+}
+
+// discardUnused menghapus object yang di-upload import ini tetapi tidak
+// dirujuk asset mana pun setelah transaksi selesai, yaitu karena transaksi
+// di-rollback atau item media-nya gagal. Kegagalan hanya dicatat; sisanya
+// dibersihkan oleh `cms media-reconcile`.
+func (s *Service) discardUnused(ctx context.Context, written []string) {
+	ctx = context.WithoutCancel(ctx)
+	for _, name := range written {
+		var n int64
+		if err := s.db.WithContext(ctx).Model(&model.MediaAsset{}).Where("url = ?", name).Count(&n).Error; err != nil {
+			slog.WarnContext(ctx, "import: cek object", "object", name, "error", err)
+			continue
+		}
+		if n > 0 {
+			continue
+		}
+		if err := s.store.Delete(ctx, name); err != nil && !storage.IsNotFound(err) {
+			slog.WarnContext(ctx, "import: hapus object yang tidak dipakai", "object", name, "error", err)
+		}
+	}
+}
+
+func (imp *importer) importEntries(files map[string]*zip.File) error {
+	return eachLine(files, fileEntries, func(line []byte) error {
+		var rec EntryRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, fileEntries, err)
+		}
+
+		ctID, ok := imp.ctIDs[rec.ContentTypeSlug]
+		if !ok {
+			imp.report.record("entry", rec.ContentTypeSlug+"/"+rec.Slug, ActionFailed, rec.ID, nil,
+				fmt.Errorf("content type %s was not imported", rec.ContentTypeSlug))
+			return nil
+		}
+		fields := imp.ctFields[rec.ContentTypeSlug]
+		data := remapRefs(rec.Data, fields, kindImage, imp.mediaMap)
+
+		var newID uuid.UUID
+		action := ActionCreated
+		err := imp.savepoint(func(tx *gorm.DB) error {
+			var existing model.Entry
+			err := tx.Where("content_type_id = ? AND slug = ?", ctID, rec.Slug).First(&existing).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			slug := rec.Slug
+			if err == nil {
+				switch imp.opts.Strategy {
+				case StrategySkip:
+					newID, action = existing.ID, ActionSkipped
+					return nil
+				case StrategyOverwrite:
+					newID, action = existing.ID, ActionUpdated
+					if err := tx.Model(&existing).Updates(map[string]any{
+						"status":       rec.Status,
+						"data":         data,
+						"published_at": rec.PublishedAt,
+						"updated_by":   imp.opts.ActorID,
+					}).Error; err != nil {
+						return err
+					}
+					var latest int
+					if err := tx.Model(&model.EntryVersion{}).Where("entry_id = ?", existing.ID).
+						Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+						return err
+					}
+					return tx.Create(&model.EntryVersion{
+						EntryID:  existing.ID,
+						Version:  latest + 1,
+						Data:     data,
+						EditorID: imp.opts.ActorID,
+					}).Error
+				case StrategyRename:
+					action = ActionRenamed
+					if slug, err = uniqueEntrySlug(tx, ctID, rec.Slug); err != nil {
+						return err
+					}
+				}
+			}
+
+			e := model.Entry{
+				ContentTypeID: ctID,
+				Slug:          slug,
+				Status:        rec.Status,
+				Data:          data,
+				PublishedAt:   rec.PublishedAt,
+				CreatedBy:     imp.opts.ActorID,
+				UpdatedBy:     imp.opts.ActorID,
+			}
+			if err := tx.Create(&e).Error; err != nil {
+				return err
+			}
+			newID = e.ID
+			return nil
+		})
+		if err != nil {
+			imp.report.record("entry", rec.ContentTypeSlug+"/"+rec.Slug, action, rec.ID, nil, err)
+			return nil
+		}
+
+		imp.entryMap[rec.ID] = newID
+		ctSlug := imp.ctSlugs[rec.ContentTypeSlug]
+		switch action {
+		case ActionCreated, ActionRenamed:
+			imp.created[rec.ID] = rec.ContentTypeSlug
+			imp.changedEntries = append(imp.changedEntries, changedEntry{model.EventEntryCreated, newID, ctSlug})
+		case ActionUpdated:
+			imp.changedEntries = append(imp.changedEntries, changedEntry{model.EventEntryUpdated, newID, ctSlug})
+		}
+		if action != ActionSkipped && hasKind(fields, kindRelation) {
+			imp.pending[newID] = pendingEntry{data: data, fields: fields}
+		}
+		imp.report.record("entry", rec.ContentTypeSlug+"/"+rec.Slug, action, rec.ID, &newID, nil)
+		return nil
+	})
+}
+
+func uniqueEntrySlug(tx *gorm.DB, ctID uuid.UUID, slug string) (string, error) {
+	for i := 1; ; i++ {
+		candidate := slug + "-imported"
+		if i > 1 {
+			candidate += "-" + strconv.Itoa(i)
+		}
+		var n int64
+		if err := tx.Model(&model.Entry{}).
+			Where("content_type_id = ? AND slug = ?", ctID, candidate).
+			Count(&n).Error; err != nil {
+			return "", err
+		}
+		if n == 0 {
+			return candidate, nil
+		}
+	}
+}
+
+// importVersions menyalin riwayat versi untuk entry yang baru dibuat. Entry
+// yang dibuat tanpa riwayat di arsip diberi versi 1 dari datanya sekarang.
+func (imp *importer) importVersions(files map[string]*zip.File) error {
+	restored := map[uuid.UUID]bool{}
+	err := eachLine(files, fileVersions, func(line []byte) error {
+		var rec VersionRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, fileVersions, err)
+		}
+		ctSlug, ok := imp.created[rec.EntryID]
+		if !ok {
+			return nil
+		}
+		v := model.EntryVersion{
+			EntryID:   imp.entryMap[rec.EntryID],
+			Version:   rec.Version,
+			Data:      remapRefs(rec.Data, imp.ctFields[ctSlug], kindImage, imp.mediaMap),
+			CreatedAt: rec.CreatedAt,
+		}
+		if err := imp.tx.Create(&v).Error; err != nil {
+			return err
+		}
+		restored[rec.EntryID] = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for oldID := range imp.created {
+		if restored[oldID] {
+			continue
+		}
+		newID := imp.entryMap[oldID]
+		var e model.Entry
+		if err := imp.tx.First(&e, "id = ?", newID).Error; err != nil {
+			return err
+		}
+		if err := imp.tx.Create(&model.EntryVersion{
+			EntryID:  newID,
+			Version:  1,
+			Data:     e.Data,
+			EditorID: imp.opts.ActorID,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveRelations me-remap field relation setelah semua entry punya id baru,
+// karena entry bisa merujuk entry lain yang muncul belakangan di arsip.
+func (imp *importer) resolveRelations() error {
+	for id, p := range imp.pending {
+		remapped := remapRefs(p.data, p.fields, kindRelation, imp.entryMap)
+		if string(remapped) == string(p.data) {
+			continue
+		}
+		if err := imp.tx.Model(&model.Entry{}).Where("id = ?", id).Update("data", remapped).Error; err != nil {
+			return err
+		}
+		if err := imp.tx.Model(&model.EntryVersion{}).
+			Where("entry_id = ? AND version = (SELECT MAX(version) FROM entry_versions WHERE entry_id = ?)", id, id).
+			Update("data", remapped).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishEvents mengumumkan hasil import lewat EventPublisher di transaksi
+// import, sehingga cache, webhook dan SSE ikut diperbarui seperti perubahan
+// lewat API biasa.
+func (imp *importer) publishEvents(ctx context.Context) error {
+	events := repository.NewEventPublisher(imp.tx)
+	for _, ct := range imp.changedCTs {
+		if err := events.Publish(ctx, model.EventContentTypeChanged, map[string]any{
+			"action": ct.action,
+			"id":     ct.id,
+			"slug":   ct.slug,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, a := range imp.uploaded {
+		if err := events.Publish(ctx, model.EventMediaUploaded, map[string]any{
+			"id":         a.ID,
+			"filename":   a.Filename,
+			"mime":       a.Mime,
+			"size_bytes": a.SizeBytes,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, c := range imp.changedEntries {
+		// dibaca ulang agar data sudah berisi relasi yang di-remap
+		var e model.Entry
+		if err := imp.tx.First(&e, "id = ?", c.id).Error; err != nil {
+			return err
+		}
+		if err := events.Publish(ctx, c.event, map[string]any{
+			"id":           e.ID,
+			"content_type": c.ctSlug,
+			"slug":         e.Slug,
+			"status":       e.Status,
+			"data":         e.Data,
+			"published_at": e.PublishedAt,
+			"updated_at":   e.UpdatedAt,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasKind(fields map[string]string, kind string) bool {
+	for _, k := range fields {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// openArchive memeriksa manifest lalu mengembalikan isi arsip beserta
+// content type di dalamnya.
+func openArchive(r io.ReaderAt, size int64) (map[string]*zip.File, []ContentTypeRecord, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var manifest Manifest
+	if err := readJSON(files, fileManifest, &manifest); err != nil {
+		return nil, nil, err
+	}
+	if manifest.Format != FormatName || manifest.Version > FormatVersion {
+		return nil, nil, fmt.Errorf("%w: unsupported format %s v%d", ErrInvalidArchive, manifest.Format, manifest.Version)
+	}
+	var cts []ContentTypeRecord
+	if err := readJSON(files, fileContentTypes, &cts); err != nil {
+		return nil, nil, err
+	}
+	for _, ct := range cts {
+		for i := range ct.Fields {
+			ct.Fields[i].Kind = model.NormalizeKind(ct.Fields[i].Kind)
+		}
+	}
+	return files, cts, nil
+}
+
+// ReadContentTypes membaca definisi content type dari arsip export tanpa
+// menyentuh database.
+func ReadContentTypes(r io.ReaderAt, size int64) ([]model.ContentType, error) {
+	_, cts, err := openArchive(r, size)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]model.ContentType, 0, len(cts))
+	for _, rec := range cts {
+		ct := model.ContentType{ID: rec.ID, Name: rec.Name, Slug: rec.Slug}
+		for _, f := range rec.Fields {
+			ct.Fields = append(ct.Fields, model.ContentField{
+				ContentTypeID: rec.ID,
+				Name:          f.Name,
+				Kind:          f.Kind,
+				Options:       f.Options,
+			})
+		}
+		out = append(out, ct)
+	}
+	return out, nil
+}
+
+func metaOrEmpty(meta json.RawMessage) json.RawMessage {
+	if len(meta) == 0 {
+		return json.RawMessage(`{}`)
+	}
+	return meta
+}
+
+func readJSON(files map[string]*zip.File, name string, v any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%w: missing %s", ErrInvalidArchive, name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := json.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, name, err)
+	}
+	return nil
+}
+
+// eachLine memanggil fn untuk setiap baris NDJSON. File yang tidak ada
+// dianggap kosong karena versions & media bersifat opsional.
+func eachLine(files map[string]*zip.File, name string, fn func([]byte) error) error {
+	f, ok := files[name]
+	if !ok {
+		return nil
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	sc := bufio.NewScanner(rc)
+	sc.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
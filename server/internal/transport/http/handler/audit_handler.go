@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+
+	"cms/server/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AuditHandler struct {
+	repo repository.AuditRepository
+}
+
+func NewAuditHandler(repo repository.AuditRepository) *AuditHandler {
+	return &AuditHandler{repo: repo}
+}
+
+// GET /api/admin/audit?actor=<user id>&action=&resource=&limit=50&cursor=&total=true
+// Audit log terbaru lebih dulu.
+func (h *AuditHandler) List(c *gin.Context) {
+	f := repository.AuditFilter{
+		Action:   c.Query("action"),
+		Resource: c.Query("resource"),
+	}
+	if actor := c.Query("actor"); actor != "" {
+		id, err := uuid.Parse(actor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "actor harus uuid"})
+			return
+		}
+		f.ActorID = &id
+	}
+	items, page, err := h.repo.List(c.Request.Context(), f, pageQuery(c, 50))
+	if err != nil {
+		pageError(c, err, "gagal mengambil audit log")
+		return
+	}
+	c.JSON(http.StatusOK, pageResponse(items, page))
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"cms/server/internal/config"
+	"cms/server/internal/metrics"
 	"cms/server/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -37,6 +39,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	// ✅ validasi lewat service
 	user, err := h.svc.Authenticate(c.Request.Context(), in.Email, in.Password)
 	if err != nil {
+		metrics.LoginFailed(loginFailureReason(err))
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
@@ -88,3 +91,15 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		},
 	})
 }
+
+// loginFailureReason memetakan error Authenticate ke label metrik yang
+// jumlahnya tetap. User yang tidak ada dicatat sama dengan password salah.
+func loginFailureReason(err error) string {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrInvalidCredentials):
+		return metrics.LoginInvalidCredentials
+	case errors.Is(err, service.ErrNoRole):
+		return metrics.LoginInactive
+	}
+	return metrics.LoginOther
+}
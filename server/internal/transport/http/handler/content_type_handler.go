@@ -3,6 +3,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"cms/server/internal/model"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type ContentTypeHandler struct {
@@ -123,15 +125,33 @@ func (h *ContentTypeHandler) AddField(c *gin.Context) {
 		return
 	}
 
-	// validasi kind (boleh tambahkan image & wysiwyg)
+	// validasi kind (boleh tambahkan image & wysiwyg); nama dari admin UI
+	// seperti "boolean" disimpan sebagai kind kanonik
+	in.Kind = model.NormalizeKind(in.Kind)
 	allowedKinds := map[string]bool{
 		"text": true, "string": true, "number": true, "bool": true, "date": true,
-		"json": true, "select": true, "image": true, "wysiwyg": true,
+		"json": true, "select": true, "image": true, "wysiwyg": true, "relation": true,
 	}
 	if !allowedKinds[in.Kind] {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid field kind"})
 		return
 	}
+	// relation wajib menunjuk content type yang ada lewat options.target
+	if in.Kind == "relation" {
+		target, _ := in.Options["target"].(string)
+		if target == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "relation field requires options.target"})
+			return
+		}
+		if _, err := h.repo.GetBySlug(c.Request.Context(), target); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "relation target content type not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
 
 	// marshal options ke JSON (default: {} bila nil)
 	var optsBytes []byte
@@ -3,21 +3,27 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"cms/server/internal/model"
 	"cms/server/internal/repository"
+	"cms/server/internal/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
-type EntryHandler struct{ repo repository.EntryRepository }
+type EntryHandler struct {
+	repo     repository.EntryRepository
+	previews service.PreviewService
+}
 
-func NewEntryHandler(repo repository.EntryRepository) *EntryHandler {
-	return &EntryHandler{repo: repo}
+func NewEntryHandler(repo repository.EntryRepository, previews service.PreviewService) *EntryHandler {
+	return &EntryHandler{repo: repo, previews: previews}
 }
 
 // POST /api/entries/:slug
@@ -51,17 +57,15 @@ func (h *EntryHandler) Create(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"data": e})
 }
 
-// GET /api/entries/:slug?limit=20&offset=0
+// GET /api/entries/:slug?limit=20&cursor=&total=true
 func (h *EntryHandler) List(c *gin.Context) {
 	slug := c.Param("slug")
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	items, total, err := h.repo.List(c.Request.Context(), slug, limit, offset)
+	items, page, err := h.repo.List(c.Request.Context(), slug, pageQuery(c, 20))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		pageError(c, err, err.Error())
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": items, "total": total, "limit": limit, "offset": offset})
+	c.JSON(http.StatusOK, pageResponse(items, page))
 }
 
 // GET /api/entries/:slug/:id
@@ -128,6 +132,10 @@ func (h *EntryHandler) Delete(c *gin.Context) {
 		}
 	}
 	if err := h.repo.Delete(c.Request.Context(), slug, id, editorID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "entry not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -184,3 +192,126 @@ func (h *EntryHandler) Rollback(c *gin.Context) {
 	}
 	c.Status(http.StatusOK)
 }
+
+// POST /api/entries/:slug/:id/unpublish
+func (h *EntryHandler) Unpublish(c *gin.Context) {
+	slug := c.Param("slug")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid uuid"})
+		return
+	}
+	var editorID *uuid.UUID
+	if v, ok := c.Get("user_id"); ok {
+		if s, ok := v.(string); ok {
+			if eid, err := uuid.Parse(s); err == nil {
+				editorID = &eid
+			}
+		}
+	}
+	if err := h.repo.Unpublish(c.Request.Context(), slug, id, editorID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+const maxBulkItems = 500
+
+// POST /api/entries/:slug/bulk
+func (h *EntryHandler) Bulk(c *gin.Context) {
+	slug := c.Param("slug")
+	var in struct {
+		Action string      `json:"action" binding:"required"`
+		IDs    []uuid.UUID `json:"ids" binding:"required"`
+		Status string      `json:"status"`
+		Atomic bool        `json:"atomic"`
+	}
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(in.IDs) > maxBulkItems {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "too many ids, max " + strconv.Itoa(maxBulkItems)})
+		return
+	}
+	op := repository.BulkOperation{Action: in.Action, IDs: in.IDs, Status: in.Status, Atomic: in.Atomic}
+	if err := op.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var editorID *uuid.UUID
+	if v, ok := c.Get("user_id"); ok {
+		if s, ok := v.(string); ok {
+			if eid, err := uuid.Parse(s); err == nil {
+				editorID = &eid
+			}
+		}
+	}
+	results, err := h.repo.Bulk(c.Request.Context(), slug, op, editorID)
+	if errors.Is(err, repository.ErrBulkAborted) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "results": results})
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "content type not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	failed := 0
+	for _, res := range results {
+		if !res.OK {
+			failed++
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"results": results, "succeeded": len(results) - failed, "failed": failed})
+}
+
+// POST /api/entries/:slug/:id/preview-token
+func (h *EntryHandler) PreviewToken(c *gin.Context) {
+	slug := c.Param("slug")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid uuid"})
+		return
+	}
+	var in struct {
+		Version    int `json:"version"`
+		TTLSeconds int `json:"ttl_seconds"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&in); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if in.Version < 0 || in.TTLSeconds < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version and ttl_seconds must be positive"})
+		return
+	}
+
+	if _, err := h.repo.Get(c.Request.Context(), slug, id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if in.Version > 0 {
+		if _, err := h.repo.GetVersion(c.Request.Context(), id, in.Version); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "version not found"})
+			return
+		}
+	}
+
+	token, exp, err := h.previews.Issue(slug, id, in.Version, time.Duration(in.TTLSeconds)*time.Second)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"token":      token,
+		"expires_at": exp,
+		"url":        "/api/public/" + slug + "/" + id.String() + "?preview_token=" + token,
+	})
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"cms/server/internal/csvimport"
+	"cms/server/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	maxImportFileSize = 20 << 20
+	// file dengan baris lebih banyak dari ini selalu diproses di background
+	syncImportMaxRows = 200
+)
+
+type EntryImportHandler struct {
+	importer *csvimport.Importer
+	jobs     repository.ImportJobRepository
+}
+
+func NewEntryImportHandler(importer *csvimport.Importer, jobs repository.ImportJobRepository) *EntryImportHandler {
+	return &EntryImportHandler{importer: importer, jobs: jobs}
+}
+
+// POST /api/entries/:slug/import
+// multipart form: file, mapping (JSON kolom -> field), slug_column, status_column, mode, async
+func (h *EntryImportHandler) Import(c *gin.Context) {
+	slug := c.Param("slug")
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+		return
+	}
+
+	opts := csvimport.Options{
+		SlugColumn:   c.PostForm("slug_column"),
+		StatusColumn: c.PostForm("status_column"),
+		Mode:         c.DefaultPostForm("mode", csvimport.ModeUpsert),
+	}
+	if m := c.PostForm("mapping"); m != "" {
+		if err := json.Unmarshal([]byte(m), &opts.Mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid mapping json"})
+			return
+		}
+	}
+	if v, ok := c.Get("user_id"); ok {
+		if s, ok := v.(string); ok {
+			if eid, err := uuid.Parse(s); err == nil {
+				opts.EditorID = &eid
+			}
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+	header, rows, err := csvimport.Read(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid csv: " + err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if c.PostForm("async") == "true" || len(rows) > syncImportMaxRows {
+		job, err := h.importer.Start(ctx, slug, fileHeader.Filename, header, rows, opts)
+		if err != nil {
+			h.importError(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"data": job})
+		return
+	}
+
+	results, err := h.importer.Run(ctx, slug, header, rows, opts, nil)
+	if err != nil {
+		h.importError(c, err)
+		return
+	}
+	failed := 0
+	for _, res := range results {
+		if res.Action == csvimport.ActionFailed {
+			failed++
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"results":   results,
+		"total":     len(results),
+		"succeeded": len(results) - failed,
+		"failed":    failed,
+	}})
+}
+
+func (h *EntryImportHandler) importError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, csvimport.ErrInvalidMapping):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "content type not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// GET /api/entries/:slug/import/:job
+func (h *EntryImportHandler) Status(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("job"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid uuid"})
+		return
+	}
+	job, err := h.jobs.Get(c.Request.Context(), id)
+	if err != nil || job.ContentTypeSlug != c.Param("slug") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "import job not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": job})
+}
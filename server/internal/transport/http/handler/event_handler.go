@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"cms/server/internal/stream"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EventHandler struct {
+	hub *stream.Hub
+}
+
+func NewEventHandler(hub *stream.Hub) *EventHandler {
+	return &EventHandler{hub: hub}
+}
+
+// GET /api/events?types=post,page&media=true
+// Server-Sent Events berisi perubahan entry (dan media) untuk live preview.
+func (h *EventHandler) Stream(c *gin.Context) {
+	filter := stream.Filter{
+		Types: map[string]bool{},
+		Media: c.DefaultQuery("media", "true") == "true",
+	}
+	for _, t := range strings.Split(c.Query("types"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			filter.Types[t] = true
+		}
+	}
+
+	sub := h.hub.Subscribe(filter)
+	defer h.hub.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	_, _ = io.WriteString(c.Writer, "retry: 3000\n\n")
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case m, ok := <-sub.C:
+			if !ok {
+				return false
+			}
+			_, _ = io.WriteString(w, "id: "+m.ID+"\nevent: "+m.Event+"\ndata: ")
+			_, _ = w.Write(m.Payload)
+			_, _ = io.WriteString(w, "\n\n")
+			return true
+		case <-heartbeat.C:
+			_, _ = io.WriteString(w, ": ping\n\n")
+			return true
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+	"path"
+	"strings"
+
+	"cms/server/pkg/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FileHandler melayani URL unduhan sementara dari storage local/memory,
+// pengganti presigned URL milik MinIO/S3.
+type FileHandler struct {
+	store  storage.Storage
+	signer *storage.URLSigner
+}
+
+func NewFileHandler(store storage.Storage, signer *storage.URLSigner) *FileHandler {
+	return &FileHandler{store: store, signer: signer}
+}
+
+// GET /api/files/*name?expires=&signature=
+func (h *FileHandler) Serve(c *gin.Context) {
+	name := strings.TrimPrefix(c.Param("name"), "/")
+	if !h.signer.Verify(name, c.Query("expires"), c.Query("signature")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "url tidak valid atau sudah kedaluwarsa"})
+		return
+	}
+	ctx := c.Request.Context()
+	info, err := h.store.Stat(ctx, name)
+	if err != nil {
+		if storage.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "file tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "storage tidak tersedia"})
+		return
+	}
+	f, _, err := h.store.Open(ctx, name)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "storage tidak tersedia"})
+		return
+	}
+	defer f.Close()
+	c.Header("Content-Type", info.ContentType)
+	http.ServeContent(c.Writer, c.Request, path.Base(name), info.LastModified, f)
+}
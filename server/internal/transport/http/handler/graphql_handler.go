@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"cms/server/internal/gql"
+	"cms/server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GraphQLHandler struct {
+	svc      *gql.Service
+	previews service.PreviewService
+}
+
+func NewGraphQLHandler(svc *gql.Service, previews service.PreviewService) *GraphQLHandler {
+	return &GraphQLHandler{svc: svc, previews: previews}
+}
+
+// GET  /api/graphql?query=...&variables=...&operationName=...
+// POST /api/graphql  body: {"query":"...","variables":{...},"operationName":"..."}
+// Hanya data yang sudah publish, kecuali dengan preview token (header
+// X-Preview-Token atau ?preview_token=). Error mengikuti format GraphQL:
+// {"errors":[{"message":"..."}]}.
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req gql.Request
+	if c.Request.Method == http.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if v := c.Query("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				graphQLError(c, http.StatusBadRequest, "variables harus berupa object JSON")
+				return
+			}
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		graphQLError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Query == "" {
+		graphQLError(c, http.StatusBadRequest, "query wajib diisi")
+		return
+	}
+
+	var claims *service.PreviewClaims
+	if token := previewToken(c); token != "" {
+		var err error
+		if claims, err = h.previews.Verify(token); err != nil {
+			graphQLError(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+		// draft tidak boleh tersimpan di cache publik maupun terindeks
+		c.Header("Cache-Control", "private, no-store")
+		c.Header("X-Robots-Tag", "noindex")
+	}
+
+	res, err := h.svc.Execute(c.Request.Context(), req, claims)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "graphql", "error", err)
+		graphQLError(c, http.StatusInternalServerError, "gagal menyiapkan schema GraphQL")
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func graphQLError(c *gin.Context, status int, msg string) {
+	c.JSON(status, gin.H{"errors": []gin.H{{"message": msg}}})
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"cms/server/pkg/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readyTimeout membatasi setiap pengecekan dependency di /readyz.
+const readyTimeout = 2 * time.Second
+
+type HealthHandler struct {
+	// stopping dibatalkan saat server mulai berhenti
+	stopping context.Context
+	db       *sql.DB
+	store    storage.Storage
+}
+
+func NewHealthHandler(stopping context.Context, db *sql.DB, store storage.Storage) *HealthHandler {
+	return &HealthHandler{stopping: stopping, db: db, store: store}
+}
+
+// GET /livez (juga /healthz)
+// Proses masih hidup; tidak mengecek dependency agar restart tidak dipicu
+// oleh database atau storage yang sedang mati.
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// GET /readyz
+// Instance siap menerima traffic: Postgres dan storage bisa dihubungi, dan
+// server belum mulai berhenti.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	if h.stopping.Err() != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "shutting down"})
+		return
+	}
+	checks := []struct {
+		name string
+		ping func(context.Context) error
+	}{
+		{"database", h.db.PingContext},
+		{"storage", h.store.Ping},
+	}
+	status, results := http.StatusOK, gin.H{}
+	for _, check := range checks {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readyTimeout)
+		err := check.ping(ctx)
+		cancel()
+		if err != nil {
+			// detail error hanya di log, endpoint ini bisa diakses publik
+			slog.WarnContext(c.Request.Context(), "readiness", "check", check.name, "error", err)
+			status = http.StatusServiceUnavailable
+			results[check.name] = "unavailable"
+			continue
+		}
+		results[check.name] = "ok"
+	}
+	if status != http.StatusOK {
+		c.JSON(status, gin.H{"status": "unavailable", "checks": results})
+		return
+	}
+	c.JSON(status, gin.H{"status": "ok", "checks": results})
+}
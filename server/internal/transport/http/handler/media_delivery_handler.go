@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"time"
+
+	"cms/server/internal/model"
+	"cms/server/internal/repository"
+	"cms/server/pkg/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// masa berlaku presigned URL pada mode redirect; response redirect sendiri
+// hanya di-cache sebentar agar URL tidak kedaluwarsa di cache
+const (
+	deliveryRedirectTTL    = time.Hour
+	deliveryRedirectMaxAge = 10 * time.Minute
+)
+
+// MediaDeliveryHandler mengirim file media ke publik (tanpa login) untuk
+// disematkan di konten yang sudah publish.
+type MediaDeliveryHandler struct {
+	store    storage.Storage
+	repo     repository.MediaRepository
+	maxAge   time.Duration
+	redirect bool
+}
+
+// NewMediaDeliveryHandler: redirect true mengarahkan ke presigned URL
+// storage alih-alih men-stream file lewat API.
+func NewMediaDeliveryHandler(store storage.Storage, repo repository.MediaRepository, maxAge time.Duration, redirect bool) *MediaDeliveryHandler {
+	return &MediaDeliveryHandler{store: store, repo: repo, maxAge: maxAge, redirect: redirect}
+}
+
+// GET /media/:id/:filename
+// Asset private, atau auto yang belum dipakai konten publish, dijawab 404.
+// Mendukung ETag/If-None-Match dan Range (untuk video/audio).
+func (h *MediaDeliveryHandler) Serve(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "file tidak ditemukan"})
+		return
+	}
+	ctx := c.Request.Context()
+	asset, err := h.repo.FindByID(ctx, id.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "file tidak ditemukan"})
+		return
+	}
+	public, err := h.repo.IsPublic(ctx, asset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal memeriksa akses media"})
+		return
+	}
+	if !public {
+		c.JSON(http.StatusNotFound, gin.H{"error": "file tidak ditemukan"})
+		return
+	}
+	if c.Param("filename") != asset.Filename {
+		c.Redirect(http.StatusMovedPermanently, mediaPublicPath(asset))
+		return
+	}
+
+	if h.redirect {
+		u, err := h.store.PresignGet(ctx, asset.URL, deliveryRedirectTTL)
+		if err != nil {
+			slog.ErrorContext(ctx, "media: presign", "object", asset.URL, "error", err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "storage tidak tersedia"})
+			return
+		}
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(deliveryRedirectMaxAge.Seconds())))
+		c.Redirect(http.StatusFound, u)
+		return
+	}
+
+	f, _, err := h.store.Open(ctx, asset.URL)
+	if err != nil {
+		if storage.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "file tidak ditemukan"})
+			return
+		}
+		slog.ErrorContext(ctx, "media: open", "object", asset.URL, "error", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "storage tidak tersedia"})
+		return
+	}
+	defer f.Close()
+
+	hdr := c.Writer.Header()
+	if asset.Checksum != "" {
+		hdr.Set("ETag", `"`+asset.Checksum+`"`)
+	} else if info, err := h.store.Stat(ctx, asset.URL); err == nil && info.ETag != "" {
+		hdr.Set("ETag", `"`+info.ETag+`"`)
+	}
+	hdr.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.maxAge.Seconds())))
+	hdr.Set("Content-Type", asset.Mime)
+	hdr.Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": asset.Filename}))
+	hdr.Set("X-Content-Type-Options", "nosniff")
+	if asset.Mime == "image/svg+xml" {
+		// SVG sudah disanitasi saat upload; CSP mencegah script bila dibuka langsung
+		hdr.Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+	}
+	http.ServeContent(c.Writer, c.Request, asset.Filename, asset.CreatedAt, f)
+}
+
+// mediaPublicPath adalah URL publik asset (relatif terhadap host API).
+func mediaPublicPath(asset *model.MediaAsset) string {
+	return "/media/" + asset.ID.String() + "/" + url.PathEscape(asset.Filename)
+}
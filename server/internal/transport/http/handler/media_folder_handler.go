@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"cms/server/internal/model"
+	"cms/server/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MediaFolderHandler struct {
+	repo repository.MediaFolderRepository
+}
+
+func NewMediaFolderHandler(repo repository.MediaFolderRepository) *MediaFolderHandler {
+	return &MediaFolderHandler{repo: repo}
+}
+
+type folderReq struct {
+	Name     string     `json:"name" binding:"required,max=200"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+// GET /api/media/folders?parent=<id>
+// Tanpa parent mengembalikan folder di root.
+func (h *MediaFolderHandler) List(c *gin.Context) {
+	var parentID *uuid.UUID
+	if p := c.Query("parent"); p != "" {
+		id, err := uuid.Parse(p)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid uuid"})
+			return
+		}
+		parentID = &id
+	}
+	items, err := h.repo.List(c.Request.Context(), parentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal mengambil folder"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
+
+// POST /api/media/folders
+// body: {"name":"Banner","parent_id":"uuid"}
+func (h *MediaFolderHandler) Create(c *gin.Context) {
+	var req folderReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name, ok := folderName(c, req.Name)
+	if !ok {
+		return
+	}
+	f := &model.MediaFolder{Name: name, ParentID: req.ParentID}
+	if v, ok := c.Get("user_id"); ok {
+		if s, ok := v.(string); ok {
+			if uid, err := uuid.Parse(s); err == nil {
+				f.CreatedBy = &uid
+			}
+		}
+	}
+	if err := h.repo.Create(c.Request.Context(), f); err != nil {
+		folderError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": f})
+}
+
+// PUT /api/media/folders/:id
+// body: {"name":"Banner","parent_id":null} — ganti nama dan/atau pindahkan folder
+func (h *MediaFolderHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid uuid"})
+		return
+	}
+	var req folderReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name, ok := folderName(c, req.Name)
+	if !ok {
+		return
+	}
+	f, err := h.repo.Update(c.Request.Context(), id, name, req.ParentID)
+	if err != nil {
+		folderError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": f})
+}
+
+// DELETE /api/media/folders/:id
+// Hanya folder kosong yang bisa dihapus.
+func (h *MediaFolderHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid uuid"})
+		return
+	}
+	if err := h.repo.Delete(c.Request.Context(), id); err != nil {
+		folderError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "folder berhasil dihapus"})
+}
+
+func folderName(c *gin.Context, name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, "/\\") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "nama folder tidak valid"})
+		return "", false
+	}
+	return name, true
+}
+
+func folderError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "folder tidak ditemukan"})
+	case errors.Is(err, repository.ErrFolderExists), errors.Is(err, repository.ErrFolderNotEmpty):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrFolderCycle):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,88 +1,197 @@
 package handler
 
 import (
+	"cms/server/internal/imageproc"
 	"cms/server/internal/model"
 	"cms/server/internal/repository"
-	"cms/server/pkg/minio"
+	"cms/server/internal/upload"
+	"cms/server/pkg/storage"
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
+	"log/slog"
 	"net/http"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// file gambar yang lebih besar dari ini tidak dianalisis (blurhash, dimensi)
+// saat upload agar tidak perlu dibaca ke memori
+const maxAnalyzeSize = 32 << 20
+
 type MediaHandler struct {
-	MinioClient *minio.Client
-	Repository  repository.MediaRepository
+	Storage    storage.Storage
+	Repository repository.MediaRepository
+	Jobs       repository.MediaJobRepository
+	Guard      *upload.Guard
 }
 
-func NewMediaHandler(m *minio.Client, repo repository.MediaRepository) *MediaHandler {
+func NewMediaHandler(store storage.Storage, repo repository.MediaRepository, jobs repository.MediaJobRepository, guard *upload.Guard) *MediaHandler {
 	return &MediaHandler{
-		MinioClient: m,
-		Repository:  repo,
+		Storage:    store,
+		Repository: repo,
+		Jobs:       jobs,
+		Guard:      guard,
 	}
 }
 
 func (h *MediaHandler) Upload(c *gin.Context) {
-	fileHeader, err := c.FormFile("file")
+	// batasi body sebelum multipart dibaca; batas per mime dicek saat isi
+	// file di-stream
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.Guard.Limits.Max()+1<<20)
+	ctx := c.Request.Context()
+
+	filename, tmpName, err := h.receiveFile(ctx, c.Request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "file tidak ditemukan"})
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file terlalu besar"})
+		case errors.Is(err, upload.ErrTooLarge):
+			guardError(c, err)
+		case errors.Is(err, errNoFile):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file tidak ditemukan"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "upload ke storage gagal"})
+		}
 		return
 	}
+	// object sementara dibuang setelah isinya dipindah atau ditolak
+	defer func() {
+		if err := h.Storage.Delete(context.WithoutCancel(ctx), tmpName); err != nil && !storage.IsNotFound(err) {
+			slog.WarnContext(ctx, "media: delete temporary object", "object", tmpName, "error", err)
+		}
+	}()
 
-	file, err := fileHeader.Open()
+	file, size, err := h.Storage.Open(ctx, tmpName)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal membuka file"})
 		return
 	}
 	defer file.Close()
 
-	buf, err := io.ReadAll(file)
+	// mime & ekstensi diambil dari isi file, bukan dari header client
+	checked, err := h.Guard.Inspect(ctx, filename, file, size)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal membaca file"})
+		guardError(c, err)
 		return
 	}
+	if checked.Data != nil {
+		size = int64(len(checked.Data))
+	}
 
-	objectName := uuid.New().String() + filepath.Ext(fileHeader.Filename)
-
-	err = h.MinioClient.Upload(c.Request.Context(), objectName, fileHeader.Header.Get("Content-Type"), buf)
+	// isi yang sama disimpan sekali; asset baru memakai object yang sudah ada
+	objectName, reused, err := objectFor(ctx, h.Repository, checked)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "upload ke minio gagal"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal memeriksa object"})
 		return
 	}
+	if !reused {
+		if err := h.putChecked(ctx, objectName, tmpName, checked); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "upload ke storage gagal"})
+			return
+		}
+	}
 
 	meta := map[string]any{
 		"source":        "upload",
 		"uploaded_from": c.ClientIP(),
 	}
+	if imageproc.IsImage(checked.Mime) && size <= maxAnalyzeSize {
+		buf := checked.Data
+		if buf == nil {
+			if _, err := file.Seek(0, io.SeekStart); err == nil {
+				buf, _ = io.ReadAll(file)
+			}
+		}
+		if info, err := imageproc.Analyze(buf); err == nil {
+			mergeImageInfo(meta, info)
+		}
+	}
 	metaJSON, _ := json.Marshal(meta)
 
 	asset := &model.MediaAsset{
-		Filename:  fileHeader.Filename,
-		Mime:      fileHeader.Header.Get("Content-Type"),
-		SizeBytes: fileHeader.Size,
+		Filename:  filename,
+		Mime:      checked.Mime,
+		SizeBytes: size,
 		URL:       objectName,
+		Checksum:  checked.Checksum,
 		Meta:      metaJSON,
 		CreatedAt: time.Now(),
 	}
 
-	if err := h.Repository.Save(c.Request.Context(), asset); err != nil {
+	if err := h.Repository.Save(ctx, asset); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal simpan metadata"})
 		return
 	}
+	if reused {
+		// object bisa saja terhapus tepat sebelum asset ini tersimpan;
+		// setelah Save ref_count > 0 sehingga cukup dicek sekali
+		if ok, err := storage.Exists(ctx, h.Storage, objectName); err == nil && !ok {
+			err = h.putChecked(ctx, objectName, tmpName, checked)
+			if err != nil {
+				slog.ErrorContext(ctx, "media: restore object", "object", objectName, "error", err)
+			}
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"id":       asset.ID,
 		"filename": asset.Filename,
 		"url":      asset.URL,
+		"checksum": asset.Checksum,
+		"reused":   reused,
 		"meta":     meta,
 	})
 }
 
+var errNoFile = errors.New("multipart form has no file part")
+
+// receiveFile meng-stream part "file" dari body multipart ke object
+// sementara di storage, tanpa ditampung di memori atau disk lokal. Isi
+// yang melebihi batas ukuran untuk mime-nya dihentikan dengan
+// upload.ErrTooLarge.
+func (h *MediaHandler) receiveFile(ctx context.Context, r *http.Request) (filename, object string, err error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return "", "", errNoFile
+	}
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return "", "", errNoFile
+		}
+		if err != nil {
+			return "", "", err
+		}
+		if part.FormName() != "file" || part.FileName() == "" {
+			// field lain tidak dipakai; NextPart membuang sisanya
+			_ = part.Close()
+			continue
+		}
+
+		body := h.Guard.Limits.Reader(part)
+		object = "tmp/" + uuid.NewString() + filepath.Ext(part.FileName())
+		err = h.Storage.Put(ctx, object, body.Mime(), body, -1)
+		_ = part.Close()
+		if err != nil {
+			_ = h.Storage.Delete(context.WithoutCancel(ctx), object)
+			if body.Err() != nil {
+				return "", "", body.Err()
+			}
+			return "", "", err
+		}
+		return part.FileName(), object, nil
+	}
+}
+
 func (h *MediaHandler) Preview(c *gin.Context) {
 	id := c.Param("id")
 
@@ -92,7 +201,7 @@ func (h *MediaHandler) Preview(c *gin.Context) {
 		return
 	}
 
-	url, err := h.MinioClient.GenerateSignedURL(c.Request.Context(), asset.URL, 1*time.Hour)
+	url, err := h.Storage.PresignGet(c.Request.Context(), asset.URL, 1*time.Hour)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal generate signed url"})
 		return
@@ -101,40 +210,405 @@ func (h *MediaHandler) Preview(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"preview_url": url})
 }
 
+// GET /api/media?q=&tags=a,b&mime=image/*&folder=<id>|root&from=&to=&limit=&cursor=&total=true
 func (h *MediaHandler) List(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	f := repository.MediaFilter{
+		Query: strings.TrimSpace(c.Query("q")),
+		Mime:  strings.ToLower(strings.TrimSpace(c.Query("mime"))),
+	}
+	if tags := c.Query("tags"); tags != "" {
+		f.Tags = repository.NormalizeTags(strings.Split(tags, ","))
+	}
+	switch folder := c.Query("folder"); folder {
+	case "":
+	case "root":
+		f.Root = true
+	default:
+		id, err := uuid.Parse(folder)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "folder harus uuid atau root"})
+			return
+		}
+		f.FolderID = &id
+	}
+	for param, dst := range map[string]**time.Time{"from": &f.From, "to": &f.To} {
+		v := c.Query(param)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			// tanggal saja: "to" mencakup seluruh hari tersebut
+			if t, err = time.Parse("2006-01-02", v); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": param + " harus berformat YYYY-MM-DD atau RFC3339"})
+				return
+			}
+			if param == "to" {
+				t = t.AddDate(0, 0, 1)
+			}
+		}
+		*dst = &t
+	}
 
-	items, total, err := h.Repository.List(c.Request.Context(), limit, offset)
+	items, page, err := h.Repository.List(c.Request.Context(), f, pageQuery(c, 10))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal mengambil media"})
+		pageError(c, err, "gagal mengambil media")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   items,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
+	c.JSON(http.StatusOK, pageResponse(items, page))
+}
+
+// PUT /api/media/:id
+// body: {"alt_text":"...","caption":"...","credit":"...","tags":["a","b"]}
+func (h *MediaHandler) Update(c *gin.Context) {
+	var in struct {
+		AltText    *string   `json:"alt_text"`
+		Caption    *string   `json:"caption"`
+		Credit     *string   `json:"credit"`
+		Tags       *[]string `json:"tags"`
+		Visibility *string   `json:"visibility" binding:"omitempty,oneof=auto public private"`
+	}
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	err := h.Repository.UpdateDetails(ctx, c.Param("id"), repository.MediaDetails{
+		AltText:    in.AltText,
+		Caption:    in.Caption,
+		Credit:     in.Credit,
+		Tags:       in.Tags,
+		Visibility: in.Visibility,
 	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "media tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal update media"})
+		return
+	}
+	asset, err := h.Repository.FindByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "media tidak ditemukan"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": asset})
 }
 
+// POST /api/media/move
+// body: {"ids":["uuid",...],"folder_id":"uuid"} — folder_id null berarti ke root
+func (h *MediaHandler) Move(c *gin.Context) {
+	var in struct {
+		IDs      []uuid.UUID `json:"ids" binding:"required,min=1,max=500"`
+		FolderID *uuid.UUID  `json:"folder_id"`
+	}
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	moved, err := h.Repository.Move(c.Request.Context(), in.IDs, in.FolderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "folder tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal memindahkan media"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"moved": moved}})
+}
+
+// GET /api/media/tags
+func (h *MediaHandler) Tags(c *gin.Context) {
+	tags, err := h.Repository.Tags(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal mengambil tag"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": tags})
+}
+
+// GET /api/media/:id
+// Mengembalikan asset beserta daftar entry/versi yang memakainya.
+func (h *MediaHandler) Detail(c *gin.Context) {
+	id := c.Param("id")
+	asset, err := h.Repository.FindByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "media tidak ditemukan"})
+		return
+	}
+	usages, err := h.Repository.Usage(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal mengambil pemakaian media"})
+		return
+	}
+	jobs, err := h.Jobs.ListByMedia(c.Request.Context(), asset.ID, 5)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal mengambil status pemrosesan media"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": asset, "used_by": usages, "jobs": jobs, "public_url": mediaPublicPath(asset)})
+}
+
+// POST /api/media/:id/process
+// Menjadwalkan ulang ekstraksi metadata & poster untuk video, audio dan PDF.
+func (h *MediaHandler) Process(c *gin.Context) {
+	ctx := c.Request.Context()
+	asset, err := h.Repository.FindByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "media tidak ditemukan"})
+		return
+	}
+	if !repository.NeedsProcessing(asset.Mime) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "hanya video, audio dan PDF yang diproses"})
+		return
+	}
+	job, err := h.Jobs.Enqueue(ctx, asset.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal menjadwalkan pemrosesan media"})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"data": job})
+}
+
+// GET /api/media/:id/poster
+// Poster (frame video, cover audio, halaman pertama PDF) hasil pemrosesan.
+func (h *MediaHandler) Poster(c *gin.Context) {
+	ctx := c.Request.Context()
+	asset, err := h.Repository.FindByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "media tidak ditemukan"})
+		return
+	}
+	var meta struct {
+		Poster string `json:"poster"`
+	}
+	_ = json.Unmarshal(asset.Meta, &meta)
+	if meta.Poster == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "poster belum tersedia"})
+		return
+	}
+	data, err := storage.Download(ctx, h.Storage, meta.Poster)
+	if err != nil {
+		if storage.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "poster belum tersedia"})
+			return
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"error": "gagal mengambil poster"})
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "image/jpeg", data)
+}
+
+// DELETE /api/media/:id?force=true
+// Media yang masih dipakai entry ditolak dengan 409 beserta daftar
+// pemakaiannya; force=true (khusus Admin) tetap menghapusnya.
 func (h *MediaHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
+	ctx := c.Request.Context()
 
-	// Hapus dari MinIO (opsional)
-	asset, err := h.Repository.FindByID(c.Request.Context(), id)
+	asset, err := h.Repository.FindByID(ctx, id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "media tidak ditemukan"})
 		return
 	}
-	_ = h.MinioClient.Delete(c.Request.Context(), asset.URL) // ignore error
 
-	// Hapus dari DB
-	if err := h.Repository.Delete(c.Request.Context(), id); err != nil {
+	usages, err := h.Repository.Usage(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal mengambil pemakaian media"})
+		return
+	}
+	if len(usages) > 0 {
+		if c.Query("force") != "true" {
+			c.JSON(http.StatusConflict, gin.H{"error": "media masih dipakai", "used_by": usages})
+			return
+		}
+		role, _ := c.Get("user_role")
+		if roleStr, _ := role.(string); roleStr != "Admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "hanya Admin yang boleh menghapus media yang masih dipakai"})
+			return
+		}
+	}
+
+	// File di storage hanya dihapus bila tidak ada asset lain yang berbagi
+	// object yang sama. Bila gagal, row DB tetap ada sehingga penghapusan
+	// bisa diulang dan file tidak menjadi yatim.
+	var storageErr error
+	err = h.Repository.Delete(ctx, id, func(objectName string) error {
+		if err := h.Storage.Delete(ctx, objectName); err != nil && !storage.IsNotFound(err) {
+			storageErr = err
+			return err
+		}
+		return nil
+	})
+	if storageErr != nil {
+		slog.WarnContext(ctx, "media: delete object", "object", asset.URL, "error", storageErr)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "gagal hapus file dari storage"})
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "media tidak ditemukan"})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal hapus media"})
 		return
 	}
+	if err := storage.DeletePrefix(ctx, h.Storage, renditionPrefix(asset)); err != nil {
+		// rendition yang tertinggal dibersihkan oleh rekonsiliasi
+		slog.WarnContext(ctx, "media: delete renditions", "media_id", asset.ID, "error", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "media berhasil dihapus", "used_by": usages})
+}
+
+// GET /api/media/:id/render?w=&h=&fit=cover|contain|fill&format=jpeg|png|webp&q=
+// Rendition disimpan di bucket (renditions/<id>/...) sehingga request
+// berikutnya dengan opsi yang sama tidak perlu memproses ulang gambar.
+func (h *MediaHandler) Render(c *gin.Context) {
+	opts := imageproc.Options{
+		Fit:    c.Query("fit"),
+		Format: c.Query("format"),
+	}
+	for param, dst := range map[string]*int{"w": &opts.Width, "h": &opts.Height, "q": &opts.Quality} {
+		if v := c.Query(param); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": param + " harus berupa angka"})
+				return
+			}
+			*dst = n
+		}
+	}
+
+	ctx := c.Request.Context()
+	asset, err := h.Repository.FindByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "media tidak ditemukan"})
+		return
+	}
+	if !imageproc.IsImage(asset.Mime) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "media bukan gambar"})
+		return
+	}
+	if err := opts.Normalize(strings.TrimPrefix(asset.Mime, "image/")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	key := renditionPrefix(asset) + opts.Key()
+	if ok, _ := storage.Exists(ctx, h.Storage, key); ok {
+		if data, err := storage.Download(ctx, h.Storage, key); err == nil {
+			writeRendition(c, opts, data)
+			return
+		}
+	}
+
+	src, err := storage.Download(ctx, h.Storage, asset.URL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal membaca file dari storage"})
+		return
+	}
+	data, opts, err := imageproc.Render(src, opts)
+	switch {
+	case errors.Is(err, imageproc.ErrNotImage):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, imageproc.ErrInvalidOptions), errors.Is(err, imageproc.ErrUnsupportedFormat):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal memproses gambar"})
+		return
+	}
+
+	// gagal menyimpan cache tidak menggagalkan request
+	if err := storage.Upload(ctx, h.Storage, key, imageproc.ContentType(opts.Format), data); err != nil {
+		slog.WarnContext(ctx, "media: simpan rendition", "media_id", asset.ID, "key", key, "error", err)
+	}
+	h.backfillImageInfo(c, asset, src)
+	writeRendition(c, opts, data)
+}
+
+// backfillImageInfo melengkapi Meta untuk media lama yang diupload sebelum
+// dimensi & blurhash dihitung saat upload.
+func (h *MediaHandler) backfillImageInfo(c *gin.Context, asset *model.MediaAsset, src []byte) {
+	meta := map[string]any{}
+	if len(asset.Meta) > 0 {
+		_ = json.Unmarshal(asset.Meta, &meta)
+	}
+	if _, ok := meta["blurhash"]; ok {
+		return
+	}
+	info, err := imageproc.Analyze(src)
+	if err != nil {
+		return
+	}
+	mergeImageInfo(meta, info)
+	raw, _ := json.Marshal(meta)
+	if err := h.Repository.UpdateMeta(c.Request.Context(), asset.ID.String(), raw); err != nil {
+		slog.WarnContext(c.Request.Context(), "media: update meta", "media_id", asset.ID, "error", err)
+	}
+}
+
+// objectFor memilih nama object untuk hasil pemeriksaan upload: object
+// yang sudah ada dengan checksum yang sama (reused), atau nama
+// content-addressed yang baru bila belum ada. Error database selain
+// not found dikembalikan agar tidak diam-diam membuat object ganda.
+func objectFor(ctx context.Context, repo repository.MediaRepository, checked *upload.Result) (string, bool, error) {
+	obj, err := repo.FindObject(ctx, checked.Checksum)
+	if err == nil {
+		return obj.ObjectName, true, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", false, err
+	}
+	return upload.ObjectName(checked.Checksum, checked.Ext), false, nil
+}
+
+// putChecked menyimpan isi yang sudah diperiksa Guard ke name: Data bila
+// file diubah, selain itu salinan object sementara tmp.
+func (h *MediaHandler) putChecked(ctx context.Context, name, tmp string, checked *upload.Result) error {
+	if checked.Data != nil {
+		return storage.Upload(ctx, h.Storage, name, checked.Mime, checked.Data)
+	}
+	return h.Storage.Copy(ctx, tmp, name)
+}
+
+// guardError memetakan hasil pemeriksaan upload.Guard ke status HTTP.
+func guardError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, upload.ErrTypeNotAllowed):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "tipe file tidak diizinkan: " + err.Error()})
+	case errors.Is(err, upload.ErrExtensionMismatch), errors.Is(err, upload.ErrInvalidContent):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, upload.ErrTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, upload.ErrInfected):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "file ditolak oleh scanner: " + err.Error()})
+	case errors.Is(err, upload.ErrScanFailed):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "scan file gagal, coba lagi nanti"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal memeriksa file"})
+	}
+}
+
+func mergeImageInfo(meta map[string]any, info *imageproc.Info) {
+	meta["width"] = info.Width
+	meta["height"] = info.Height
+	meta["blurhash"] = info.Blurhash
+	meta["dominant_color"] = info.DominantColor
+}
+
+func renditionPrefix(asset *model.MediaAsset) string {
+	return "renditions/" + asset.ID.String() + "/"
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "media berhasil dihapus"})
+func writeRendition(c *gin.Context, opts imageproc.Options, data []byte) {
+	// rendition untuk opsi yang sama tidak pernah berubah selama media ada
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.Data(http.StatusOK, imageproc.ContentType(opts.Format), data)
 }
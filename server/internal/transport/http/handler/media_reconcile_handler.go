@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"cms/server/internal/mediacheck"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MediaReconcileHandler struct {
+	svc *mediacheck.Service
+}
+
+func NewMediaReconcileHandler(svc *mediacheck.Service) *MediaReconcileHandler {
+	return &MediaReconcileHandler{svc: svc}
+}
+
+// POST /api/admin/media/reconcile?delete=true&grace=1h
+// Tanpa delete=true object yatim hanya dilaporkan.
+func (h *MediaReconcileHandler) Reconcile(c *gin.Context) {
+	opts := mediacheck.Options{Delete: c.Query("delete") == "true"}
+	if g := c.Query("grace"); g != "" {
+		d, err := time.ParseDuration(g)
+		if err != nil || d <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid grace duration"})
+			return
+		}
+		opts.Grace = d
+	}
+	report, err := h.svc.Reconcile(c.Request.Context(), opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "data": report})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
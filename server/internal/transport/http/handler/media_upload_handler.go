@@ -0,0 +1,390 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"cms/server/internal/imageproc"
+	"cms/server/internal/model"
+	"cms/server/internal/repository"
+	"cms/server/internal/upload"
+	"cms/server/pkg/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	multipartUploadTTL = 24 * time.Hour
+	presignedUploadTTL = time.Hour
+)
+
+// MediaUploadHandler menangani upload file besar: multipart upload S3 yang
+// bisa dilanjutkan, atau presigned POST langsung dari browser ke storage.
+// Keduanya diakhiri dengan complete yang mendaftarkan MediaAsset.
+type MediaUploadHandler struct {
+	store   storage.Storage
+	uploads repository.MediaUploadRepository
+	media   repository.MediaRepository
+	guard   *upload.Guard
+}
+
+func NewMediaUploadHandler(store storage.Storage, uploads repository.MediaUploadRepository, media repository.MediaRepository, guard *upload.Guard) *MediaUploadHandler {
+	return &MediaUploadHandler{store: store, uploads: uploads, media: media, guard: guard}
+}
+
+type createUploadReq struct {
+	Filename string `json:"filename" binding:"required"`
+	Mime     string `json:"mime" binding:"required"`
+	Size     int64  `json:"size" binding:"required,gt=0"`
+	Method   string `json:"method"`
+}
+
+// POST /api/media/uploads
+// body: {"filename":"video.mp4","mime":"video/mp4","size":734003200,"method":"multipart|presigned"}
+func (h *MediaUploadHandler) Create(c *gin.Context) {
+	var req createUploadReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Method == "" {
+		req.Method = model.UploadMethodMultipart
+	}
+	if req.Method != model.UploadMethodMultipart && req.Method != model.UploadMethodPresigned {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "method harus multipart atau presigned"})
+		return
+	}
+	presigner, canPresign := h.store.(storage.PostPresigner)
+	if req.Method == model.UploadMethodPresigned && !canPresign {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "storage ini tidak mendukung presigned upload, gunakan multipart"})
+		return
+	}
+	// pemeriksaan awal berdasarkan data dari client; isi file diperiksa ulang
+	// saat complete
+	if !h.guard.Allowed.Allows(req.Mime) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "tipe file tidak diizinkan: " + req.Mime})
+		return
+	}
+	if err := upload.CheckExtension(req.Filename, req.Mime); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit := h.guard.Limits.For(req.Mime)
+	if req.Size > limit {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file terlalu besar (maks " + upload.FormatSize(limit) + " untuk " + req.Mime + ")"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	u := &model.MediaUpload{
+		Method:     req.Method,
+		ObjectName: uuid.New().String() + filepath.Ext(req.Filename),
+		Filename:   req.Filename,
+		Mime:       req.Mime,
+		SizeBytes:  req.Size,
+		Status:     model.UploadPending,
+		CreatedAt:  time.Now(),
+	}
+	if v, ok := c.Get("user_id"); ok {
+		if s, ok := v.(string); ok {
+			if uid, err := uuid.Parse(s); err == nil {
+				u.CreatedBy = &uid
+			}
+		}
+	}
+
+	resp := gin.H{"upload": u}
+	if req.Method == model.UploadMethodPresigned {
+		u.ExpiresAt = u.CreatedAt.Add(presignedUploadTTL)
+		url, fields, err := presigner.PresignedPost(ctx, u.ObjectName, u.Mime, limit, presignedUploadTTL)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal membuat presigned url"})
+			return
+		}
+		resp["url"] = url
+		resp["fields"] = fields
+	} else {
+		u.ExpiresAt = u.CreatedAt.Add(multipartUploadTTL)
+		u.PartSize = upload.PartSize(u.SizeBytes)
+		uploadID, err := h.store.NewMultipartUpload(ctx, u.ObjectName, u.Mime)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal memulai multipart upload"})
+			return
+		}
+		u.UploadID = uploadID
+		resp["part_count"] = upload.PartCount(u.SizeBytes, u.PartSize)
+	}
+
+	if err := h.uploads.Create(ctx, u); err != nil {
+		if u.UploadID != "" {
+			_ = h.store.AbortMultipartUpload(ctx, u.ObjectName, u.UploadID)
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal menyimpan sesi upload"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": resp})
+}
+
+// GET /api/media/uploads/:id
+// Untuk melanjutkan upload: daftar part yang sudah diterima storage.
+func (h *MediaUploadHandler) Detail(c *gin.Context) {
+	u, ok := h.load(c)
+	if !ok {
+		return
+	}
+	resp := gin.H{"upload": u}
+	if u.Method == model.UploadMethodMultipart && u.Status == model.UploadPending {
+		parts, err := h.store.ListParts(c.Request.Context(), u.ObjectName, u.UploadID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal membaca part dari storage"})
+			return
+		}
+		resp["part_count"] = upload.PartCount(u.SizeBytes, u.PartSize)
+		resp["parts"] = parts
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// PUT /api/media/uploads/:id/parts/:part
+// Body berisi byte mentah part ke-n (mulai 1). Part yang sama boleh dikirim
+// ulang; versi terakhir yang dipakai.
+func (h *MediaUploadHandler) PutPart(c *gin.Context) {
+	u, ok := h.loadPending(c)
+	if !ok {
+		return
+	}
+	if u.Method != model.UploadMethodMultipart {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sesi ini memakai presigned upload"})
+		return
+	}
+	n, err := strconv.Atoi(c.Param("part"))
+	count := upload.PartCount(u.SizeBytes, u.PartSize)
+	if err != nil || n < 1 || n > count {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("nomor part harus 1..%d", count)})
+		return
+	}
+	expected := upload.ExpectedPartSize(u.SizeBytes, u.PartSize, n)
+	if c.Request.ContentLength < 0 {
+		c.JSON(http.StatusLengthRequired, gin.H{"error": "header Content-Length wajib diisi"})
+		return
+	}
+	if c.Request.ContentLength != expected {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("ukuran part %d harus %d byte", n, expected)})
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, expected)
+	part, err := h.store.PutPart(c.Request.Context(), u.ObjectName, u.UploadID, n, body, expected)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "gagal upload part ke storage"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": part})
+}
+
+// POST /api/media/uploads/:id/complete
+// Menggabungkan part (multipart) atau memeriksa object yang diupload lewat
+// presigned POST, lalu mendaftarkan MediaAsset.
+func (h *MediaUploadHandler) Complete(c *gin.Context) {
+	u, ok := h.loadPending(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+
+	if u.Method == model.UploadMethodMultipart {
+		// object sudah ada bila complete sebelumnya gagal setelah part digabung
+		if _, err := h.store.Stat(ctx, u.ObjectName); err != nil {
+			parts, err := h.store.ListParts(ctx, u.ObjectName, u.UploadID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal membaca part dari storage"})
+				return
+			}
+			if missing := missingParts(u, parts); len(missing) > 0 {
+				c.JSON(http.StatusConflict, gin.H{"error": "masih ada part yang belum lengkap", "missing_parts": missing})
+				return
+			}
+			if err := h.store.CompleteMultipartUpload(ctx, u.ObjectName, u.UploadID, parts); err != nil {
+				c.JSON(http.StatusBadGateway, gin.H{"error": "gagal menggabungkan part"})
+				return
+			}
+		}
+	}
+
+	obj, size, err := h.store.Open(ctx, u.ObjectName)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "file belum diupload ke storage"})
+		return
+	}
+	defer obj.Close()
+
+	// isi file diperiksa dengan aturan yang sama seperti upload biasa
+	checked, err := h.guard.Inspect(ctx, u.Filename, obj, size)
+	if err != nil {
+		// scan yang gagal boleh diulang; selain itu file dibuang
+		if !errors.Is(err, upload.ErrScanFailed) {
+			_ = h.store.Delete(ctx, u.ObjectName)
+			_ = h.uploads.SetStatus(ctx, u.ID, model.UploadAborted, nil)
+		}
+		guardError(c, err)
+		return
+	}
+	if checked.Data != nil {
+		size = int64(len(checked.Data))
+	}
+
+	// file dipindah ke nama content-addressed, atau dibuang bila isi yang
+	// sama sudah tersimpan; object sementara dihapus setelah asset tercatat
+	// agar complete tetap bisa diulang bila gagal di tengah jalan
+	objectName, reused, err := objectFor(ctx, h.media, checked)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal memeriksa object"})
+		return
+	}
+	if !reused {
+		if err := h.storeChecked(ctx, u, objectName, checked); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "upload ke storage gagal"})
+			return
+		}
+	}
+
+	meta := map[string]any{
+		"source":        u.Method,
+		"uploaded_from": c.ClientIP(),
+	}
+	if imageproc.IsImage(checked.Mime) && size <= maxAnalyzeSize {
+		buf := checked.Data
+		if buf == nil {
+			if _, err := obj.Seek(0, io.SeekStart); err == nil {
+				buf, _ = io.ReadAll(obj)
+			}
+		}
+		if info, err := imageproc.Analyze(buf); err == nil {
+			mergeImageInfo(meta, info)
+		}
+	}
+	metaJSON, _ := json.Marshal(meta)
+
+	asset := &model.MediaAsset{
+		Filename:  u.Filename,
+		Mime:      checked.Mime,
+		SizeBytes: size,
+		URL:       objectName,
+		Checksum:  checked.Checksum,
+		Meta:      metaJSON,
+		CreatedBy: u.CreatedBy,
+		CreatedAt: time.Now(),
+	}
+	if err := h.uploads.Complete(ctx, u.ID, asset); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusConflict, gin.H{"error": "sesi upload sudah selesai"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal simpan metadata"})
+		return
+	}
+	if reused {
+		// lihat MediaHandler.Upload: object bisa terhapus sebelum asset tersimpan
+		if ok, err := storage.Exists(ctx, h.store, objectName); err == nil && !ok {
+			if err := h.storeChecked(ctx, u, objectName, checked); err != nil {
+				slog.ErrorContext(ctx, "media: restore object", "object", objectName, "error", err)
+			}
+		}
+	}
+	if objectName != u.ObjectName {
+		if err := h.store.Delete(ctx, u.ObjectName); err != nil {
+			slog.WarnContext(ctx, "media: delete temporary upload", "object", u.ObjectName, "error", err)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": asset, "reused": reused})
+}
+
+// storeChecked menyimpan isi upload u yang sudah diperiksa ke object name.
+func (h *MediaUploadHandler) storeChecked(ctx context.Context, u *model.MediaUpload, name string, checked *upload.Result) error {
+	if checked.Data != nil {
+		return storage.Upload(ctx, h.store, name, checked.Mime, checked.Data)
+	}
+	if name == u.ObjectName {
+		return nil
+	}
+	return h.store.Copy(ctx, u.ObjectName, name)
+}
+
+// DELETE /api/media/uploads/:id
+func (h *MediaUploadHandler) Abort(c *gin.Context) {
+	u, ok := h.load(c)
+	if !ok {
+		return
+	}
+	if u.Status != model.UploadPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "sesi upload sudah " + u.Status})
+		return
+	}
+	ctx := c.Request.Context()
+	if u.Method == model.UploadMethodMultipart {
+		_ = h.store.AbortMultipartUpload(ctx, u.ObjectName, u.UploadID)
+	} else {
+		_ = h.store.Delete(ctx, u.ObjectName)
+	}
+	if err := h.uploads.SetStatus(ctx, u.ID, model.UploadAborted, nil); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal membatalkan upload"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "upload dibatalkan"})
+}
+
+func (h *MediaUploadHandler) load(c *gin.Context) (*model.MediaUpload, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid uuid"})
+		return nil, false
+	}
+	u, err := h.uploads.Get(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "sesi upload tidak ditemukan"})
+		return nil, false
+	}
+	return u, true
+}
+
+func (h *MediaUploadHandler) loadPending(c *gin.Context) (*model.MediaUpload, bool) {
+	u, ok := h.load(c)
+	if !ok {
+		return nil, false
+	}
+	if u.Status != model.UploadPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "sesi upload sudah " + u.Status})
+		return nil, false
+	}
+	if time.Now().After(u.ExpiresAt) {
+		c.JSON(http.StatusGone, gin.H{"error": "sesi upload sudah kedaluwarsa"})
+		return nil, false
+	}
+	return u, true
+}
+
+// missingParts mengembalikan nomor part yang belum ada atau ukurannya tidak
+// sesuai.
+func missingParts(u *model.MediaUpload, parts []storage.Part) []int {
+	got := make(map[int]int64, len(parts))
+	for _, p := range parts {
+		got[p.Number] = p.Size
+	}
+	var missing []int
+	for n := 1; n <= upload.PartCount(u.SizeBytes, u.PartSize); n++ {
+		if size, ok := got[n]; !ok || size != upload.ExpectedPartSize(u.SizeBytes, u.PartSize, n) {
+			missing = append(missing, n)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+
+	"cms/server/internal/openapi"
+	"cms/server/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OpenAPIHandler struct {
+	routes func() gin.RoutesInfo
+	types  repository.ContentTypeRepository
+}
+
+// routes biasanya engine.Routes, dipanggil setiap request agar route yang
+// didaftarkan setelah handler ini ikut terdokumentasi.
+func NewOpenAPIHandler(routes func() gin.RoutesInfo, types repository.ContentTypeRepository) *OpenAPIHandler {
+	return &OpenAPIHandler{routes: routes, types: types}
+}
+
+// GET /api/openapi.json
+// Dokumen OpenAPI 3 dari route yang terdaftar dan content type saat ini.
+func (h *OpenAPIHandler) Spec(c *gin.Context) {
+	types, err := h.types.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal mengambil content type"})
+		return
+	}
+	c.Header("Cache-Control", "no-cache")
+	c.JSON(http.StatusOK, openapi.Build(h.routes(), types))
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"cms/server/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pageQuery membaca ?limit=&cursor=&total=true. limit dibatasi
+// repository.MaxPageSize; offset masih diterima untuk client lama dan
+// diabaikan bila ada cursor.
+func pageQuery(c *gin.Context, defaultLimit int) repository.Page {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	total, _ := strconv.ParseBool(c.Query("total"))
+	return repository.Page{
+		Limit:  min(limit, repository.MaxPageSize),
+		Cursor: c.Query("cursor"),
+		Offset: max(offset, 0),
+		Total:  total,
+	}
+}
+
+// pageResponse: {"data", "limit", "next", "prev"} ditambah "total" bila
+// diminta.
+func pageResponse(data any, info repository.PageInfo) gin.H {
+	res := gin.H{"data": data, "limit": info.Limit, "next": info.Next, "prev": info.Prev}
+	if info.Total != nil {
+		res["total"] = *info.Total
+	}
+	return res
+}
+
+// pageError menjawab 400 untuk cursor yang rusak atau dari urutan lain.
+func pageError(c *gin.Context, err error, msg string) {
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+}
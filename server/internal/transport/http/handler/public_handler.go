@@ -2,39 +2,37 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
+	"time"
 
+	"cms/server/internal/cache"
 	"cms/server/internal/repository"
+	"cms/server/internal/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
-type PublicHandler struct{ repo repository.EntryRepository }
+type PublicHandler struct {
+	repo     repository.EntryRepository
+	previews service.PreviewService
+}
 
-func NewPublicHandler(repo repository.EntryRepository) *PublicHandler {
-	return &PublicHandler{repo: repo}
+func NewPublicHandler(repo repository.EntryRepository, previews service.PreviewService) *PublicHandler {
+	return &PublicHandler{repo: repo, previews: previews}
 }
 
-// GET /api/public/:slug
+// GET /api/public/:slug?limit=10&cursor=&sort=-published_at&total=true
 func (h *PublicHandler) ListPublished(c *gin.Context) {
 	slug := c.Param("slug")
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 	sort := c.DefaultQuery("sort", "-published_at")
 
-	items, total, err := h.repo.ListPublished(c.Request.Context(), slug, limit, offset, sort)
+	items, page, err := h.repo.ListPublished(c.Request.Context(), slug, pageQuery(c, 10), sort)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		pageError(c, err, err.Error())
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   items,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
-	})
+	c.JSON(http.StatusOK, pageResponse(items, page))
 }
 
 // GET /api/public/:slug/:id
@@ -45,6 +43,10 @@ func (h *PublicHandler) GetPublished(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid uuid"})
 		return
 	}
+	if token := previewToken(c); token != "" {
+		h.getPreview(c, slug, id, token)
+		return
+	}
 	item, err := h.repo.GetPublished(c.Request.Context(), slug, id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
@@ -52,3 +54,77 @@ func (h *PublicHandler) GetPublished(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"data": item})
 }
+
+// CacheTags adalah tag cache response publik: daftar ditandai dengan
+// content type-nya, detail dengan id entry.
+func (h *PublicHandler) CacheTags(c *gin.Context) []string {
+	slug := c.Param("slug")
+	tags := []string{cache.TagAll, cache.TypeTag(slug)}
+	if id := c.Param("id"); id != "" {
+		return append(tags, cache.EntryTag(id))
+	}
+	return append(tags, cache.ListTag(slug))
+}
+
+// CacheExpires membatasi umur cache daftar sampai entry terjadwal
+// berikutnya tayang, karena saat itu tidak ada event yang dikirim.
+func (h *PublicHandler) CacheExpires(c *gin.Context) time.Time {
+	if c.Param("id") != "" {
+		return time.Time{}
+	}
+	next, err := h.repo.NextScheduled(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		// tanpa jadwal yang pasti, jangan simpan sama sekali
+		return time.Now()
+	}
+	if next == nil {
+		return time.Time{}
+	}
+	return *next
+}
+
+// IsPreview melaporkan apakah request membawa preview token; response
+// draft tidak boleh masuk cache publik.
+func (h *PublicHandler) IsPreview(c *gin.Context) bool {
+	return previewToken(c) != ""
+}
+
+func previewToken(c *gin.Context) string {
+	if t := c.GetHeader("X-Preview-Token"); t != "" {
+		return t
+	}
+	return c.Query("preview_token")
+}
+
+// getPreview mengembalikan draft terbaru (atau versi tertentu) dari entry
+// yang tercantum di preview token.
+func (h *PublicHandler) getPreview(c *gin.Context, slug string, id uuid.UUID, token string) {
+	claims, err := h.previews.Verify(token)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if !claims.Covers(slug, id) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "preview token is not valid for this entry"})
+		return
+	}
+
+	// draft tidak boleh tersimpan di cache publik maupun terindeks
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("X-Robots-Tag", "noindex")
+
+	item, err := h.repo.Get(c.Request.Context(), slug, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if claims.Version > 0 {
+		v, err := h.repo.GetVersion(c.Request.Context(), id, claims.Version)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "version not found"})
+			return
+		}
+		item.Data = v.Data
+	}
+	c.JSON(http.StatusOK, gin.H{"data": item, "preview": true, "version": claims.Version})
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"cms/server/internal/transfer"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TransferHandler struct {
+	svc *transfer.Service
+}
+
+func NewTransferHandler(svc *transfer.Service) *TransferHandler {
+	return &TransferHandler{svc: svc}
+}
+
+// GET /api/admin/export?types=post,page&versions=true&media=true
+func (h *TransferHandler) Export(c *gin.Context) {
+	opts := transfer.ExportOptions{
+		Versions: c.Query("versions") == "true",
+		Media:    c.Query("media") == "true",
+	}
+	if types := c.Query("types"); types != "" {
+		opts.Types = strings.Split(types, ",")
+	}
+
+	filename := "cms-export-" + time.Now().UTC().Format("20060102-150405") + ".zip"
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	// arsip ditulis langsung ke response, jadi error di tengah jalan hanya
+	// bisa menghentikan stream
+	if err := h.svc.Export(c.Request.Context(), c.Writer, opts); err != nil {
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
+
+// POST /api/admin/import?strategy=skip|overwrite|rename&dry_run=true
+func (h *TransferHandler) Import(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	var actorID *uuid.UUID
+	if v, ok := c.Get("user_id"); ok {
+		if s, ok := v.(string); ok {
+			if id, err := uuid.Parse(s); err == nil {
+				actorID = &id
+			}
+		}
+	}
+
+	report, err := h.svc.Import(c.Request.Context(), file, fileHeader.Size, transfer.ImportOptions{
+		Strategy: c.DefaultQuery("strategy", transfer.StrategySkip),
+		DryRun:   c.Query("dry_run") == "true",
+		ActorID:  actorID,
+	})
+	if errors.Is(err, transfer.ErrInvalidArchive) || errors.Is(err, transfer.ErrInvalidStrategy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
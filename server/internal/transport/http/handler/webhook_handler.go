@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"cms/server/internal/model"
+	"cms/server/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WebhookHandler struct {
+	repo repository.WebhookRepository
+}
+
+func NewWebhookHandler(repo repository.WebhookRepository) *WebhookHandler {
+	return &WebhookHandler{repo: repo}
+}
+
+type webhookInput struct {
+	Name   string   `json:"name" binding:"required"`
+	URL    string   `json:"url" binding:"required"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events" binding:"required"`
+	Active *bool    `json:"active"`
+}
+
+func (in webhookInput) validate() string {
+	u, err := url.Parse(in.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "invalid url"
+	}
+	if len(in.Events) == 0 {
+		return "events is required"
+	}
+	for _, ev := range in.Events {
+		if ev != "*" && !slices.Contains(model.Events, ev) {
+			return "unknown event " + ev
+		}
+	}
+	return ""
+}
+
+func newWebhookSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// GET /api/admin/webhooks
+func (h *WebhookHandler) List(c *gin.Context) {
+	list, err := h.repo.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "events": model.Events})
+}
+
+// POST /api/admin/webhooks
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var in webhookInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if msg := in.validate(); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	wh := &model.Webhook{
+		Name:   in.Name,
+		URL:    in.URL,
+		Secret: in.Secret,
+		Events: in.Events,
+		Active: in.Active == nil || *in.Active,
+	}
+	if wh.Secret == "" {
+		wh.Secret = newWebhookSecret()
+	}
+	if v, ok := c.Get("user_id"); ok {
+		if s, ok := v.(string); ok {
+			if id, err := uuid.Parse(s); err == nil {
+				wh.CreatedBy = &id
+			}
+		}
+	}
+	if err := h.repo.Create(c.Request.Context(), wh); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	// secret hanya ditampilkan sekali saat webhook dibuat
+	c.JSON(http.StatusCreated, gin.H{"data": wh, "secret": wh.Secret})
+}
+
+// GET /api/admin/webhooks/:id
+func (h *WebhookHandler) Detail(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid uuid"})
+		return
+	}
+	wh, err := h.repo.Get(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": wh})
+}
+
+// PUT /api/admin/webhooks/:id
+func (h *WebhookHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid uuid"})
+		return
+	}
+	wh, err := h.repo.Get(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	var in webhookInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if msg := in.validate(); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+	wh.Name = in.Name
+	wh.URL = in.URL
+	wh.Events = in.Events
+	if in.Secret != "" {
+		wh.Secret = in.Secret
+	}
+	if in.Active != nil {
+		wh.Active = *in.Active
+	}
+	wh.UpdatedAt = time.Now()
+	if err := h.repo.Update(c.Request.Context(), wh); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": wh})
+}
+
+// DELETE /api/admin/webhooks/:id
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid uuid"})
+		return
+	}
+	if err := h.repo.Delete(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "webhook deleted"})
+}
+
+// GET /api/admin/webhooks/:id/deliveries?limit=20&offset=0
+func (h *WebhookHandler) Deliveries(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid uuid"})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	items, total, err := h.repo.ListDeliveries(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": items, "total": total, "limit": limit, "offset": offset})
+}
+
+// POST /api/admin/webhooks/:id/deliveries/:delivery/redeliver
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid uuid"})
+		return
+	}
+	deliveryID, err := uuid.Parse(c.Param("delivery"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid uuid"})
+		return
+	}
+	d, err := h.repo.GetDelivery(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	nd, err := h.repo.Redeliver(c.Request.Context(), d)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"data": nd})
+}
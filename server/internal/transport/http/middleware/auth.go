@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"cms/server/internal/config"
+	"cms/server/internal/telemetry"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -37,7 +39,21 @@ func AuthMiddleware(cfg config.Config) gin.HandlerFunc {
 
 		c.Set("user_id", claims["sub"])
 		c.Set("user_role", claims["role"])
+		telemetry.SetUser(c.Request.Context(), fmt.Sprint(claims["sub"]))
 
 		c.Next()
 	}
 }
+
+// TokenFromQuery memindahkan token dari query string ke header Authorization
+// untuk client yang tidak bisa mengirim header, seperti EventSource di browser.
+func TokenFromQuery(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query(param); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"cms/server/internal/cache"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResponseCacheConfig struct {
+	// Store boleh nil; response tetap diberi ETag dan dijawab 304 bila
+	// cocok, hanya saja selalu dihitung ulang.
+	Store cache.Store
+	TTL   time.Duration
+	// Tags mengembalikan tag response, juga dikirim sebagai Surrogate-Key.
+	Tags func(c *gin.Context) []string
+	// Expires (opsional) mengembalikan saat response harus dihitung ulang
+	// walau tidak ada event, mis. jadwal publish berikutnya; nol berarti
+	// cukup TTL.
+	Expires func(c *gin.Context) time.Time
+	// Skip (opsional) melewati cache untuk request ini, mis. preview.
+	Skip func(c *gin.Context) bool
+}
+
+// ResponseCache menyimpan response 200 dari GET berdasarkan path dan query.
+// Response diberi ETag dari isinya dan "Cache-Control: public, no-cache"
+// sehingga browser dan CDN selalu revalidasi, dan invalidasi di Store
+// langsung terlihat oleh mereka.
+func ResponseCache(cfg ResponseCacheConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet || (cfg.Skip != nil && cfg.Skip(c)) {
+			c.Next()
+			return
+		}
+		ctx := c.Request.Context()
+		key := "response:" + c.Request.URL.Path + "?" + c.Request.URL.Query().Encode()
+		tags := cfg.Tags(c)
+
+		// generation dibaca sebelum handler membaca database: invalidasi
+		// yang terjadi di antaranya membuat response ini tidak disimpan
+		var (
+			gen   uint64
+			genOK bool
+		)
+		if cfg.Store != nil {
+			var err error
+			if gen, err = cfg.Store.Generation(ctx); err != nil {
+				slog.ErrorContext(ctx, "cache: generation", "error", err)
+			} else {
+				genOK = true
+			}
+			value, ok, err := cfg.Store.Get(ctx, key)
+			if err != nil {
+				slog.ErrorContext(ctx, "cache: get", "key", key, "error", err)
+			}
+			if etag, contentType, body, valid := decodeCached(value); ok && valid {
+				c.Header("X-Cache", "HIT")
+				serveCached(c, etag, contentType, body, tags)
+				c.Abort()
+				return
+			}
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if w.status != http.StatusOK || c.Writer.Header().Get("Cache-Control") != "" {
+			// error dan response yang mengatur cache sendiri diteruskan apa adanya
+			c.Writer.WriteHeader(w.status)
+			_, _ = c.Writer.Write(w.body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(w.body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		contentType := c.Writer.Header().Get("Content-Type")
+		if cfg.Store != nil && genOK {
+			ttl := cfg.TTL
+			if cfg.Expires != nil {
+				if at := cfg.Expires(c); !at.IsZero() && time.Until(at) < ttl {
+					ttl = time.Until(at)
+				}
+			}
+			value := encodeCached(etag, contentType, w.body.Bytes())
+			if err := cfg.Store.Set(ctx, key, value, ttl, tags, gen); err != nil {
+				slog.ErrorContext(ctx, "cache: set", "key", key, "error", err)
+			}
+			c.Header("X-Cache", "MISS")
+		}
+		serveCached(c, etag, contentType, w.body.Bytes(), tags)
+	}
+}
+
+// serveCached menulis body dengan ETag; http.ServeContent menjawab 304
+// bila If-None-Match cocok.
+func serveCached(c *gin.Context, etag, contentType string, body []byte, tags []string) {
+	hdr := c.Writer.Header()
+	hdr.Set("ETag", etag)
+	hdr.Set("Content-Type", contentType)
+	hdr.Set("Cache-Control", "public, no-cache")
+	hdr.Set("Surrogate-Key", strings.Join(tags, " "))
+	http.ServeContent(c.Writer, c.Request, "", time.Time{}, bytes.NewReader(body))
+}
+
+// Nilai di Store: baris ETag, baris Content-Type, lalu body.
+func encodeCached(etag, contentType string, body []byte) []byte {
+	value := make([]byte, 0, len(etag)+len(contentType)+2+len(body))
+	value = append(value, etag...)
+	value = append(value, '\n')
+	value = append(value, contentType...)
+	value = append(value, '\n')
+	return append(value, body...)
+}
+
+func decodeCached(value []byte) (etag, contentType string, body []byte, ok bool) {
+	etagLine, rest, ok := bytes.Cut(value, []byte{'\n'})
+	if !ok {
+		return "", "", nil, false
+	}
+	typeLine, body, ok := bytes.Cut(rest, []byte{'\n'})
+	if !ok {
+		return "", "", nil, false
+	}
+	return string(etagLine), string(typeLine), body, true
+}
+
+// bufferedWriter menahan response handler agar bisa disimpan dan diberi
+// ETag sebelum dikirim.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) { w.status = code }
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+
+func (w *bufferedWriter) WriteString(s string) (int, error) { return w.body.WriteString(s) }
+
+func (w *bufferedWriter) Status() int { return w.status }
+
+func (w *bufferedWriter) Size() int { return w.body.Len() }
+
+func (w *bufferedWriter) Written() bool { return w.body.Len() > 0 }
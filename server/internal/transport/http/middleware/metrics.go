@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"time"
+
+	"cms/server/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics mencatat durasi dan status setiap request per route. Request ke
+// path yang tidak punya route digabung sebagai "unmatched".
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveHTTP(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"cms/server/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader membawa API key client untuk kuota di RATE_LIMIT_API_KEYS.
+const APIKeyHeader = "X-API-Key"
+
+type RateLimitConfig struct {
+	Store  ratelimit.Store
+	Policy ratelimit.Policy
+	// Key mengembalikan identitas client, mis. ByIP atau ByUser.
+	Key func(c *gin.Context) string
+	// APIKeys (opsional): request dengan X-API-Key yang terdaftar memakai
+	// bucket dan policy milik key itu. Key yang tidak dikenal diabaikan.
+	APIKeys ratelimit.Keys
+}
+
+// RateLimit menolak request dengan 429 bila bucket client kosong. Setiap
+// response diberi header RateLimit-Limit, RateLimit-Remaining,
+// RateLimit-Reset dan RateLimit-Policy; 429 juga diberi Retry-After.
+// Policy yang Off tidak membatasi apa pun.
+func RateLimit(cfg RateLimitConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy, client := cfg.Policy, ""
+		if key := c.GetHeader(APIKeyHeader); key != "" {
+			if p, ok := cfg.APIKeys[key]; ok {
+				policy, client = p, "key:"+ratelimit.KeyID(key)
+			}
+		}
+		if policy.Off() {
+			c.Next()
+			return
+		}
+		if client == "" {
+			client = cfg.Key(c)
+		}
+
+		res, err := cfg.Store.Allow(c.Request.Context(), policy.Name+":"+client, policy)
+		if err != nil {
+			// rate limit tidak boleh membuat API ikut mati
+			slog.ErrorContext(c.Request.Context(), "ratelimit", "error", err)
+			c.Next()
+			return
+		}
+		hdr := c.Writer.Header()
+		hdr.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		hdr.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		hdr.Set("RateLimit-Reset", seconds(res.Reset))
+		hdr.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%s", policy.Limit, seconds(policy.Window)))
+		if !res.Allowed {
+			hdr.Set("Retry-After", seconds(res.RetryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// ByIP memakai alamat client (lihat TRUSTED_PROXIES).
+func ByIP(c *gin.Context) string { return "ip:" + c.ClientIP() }
+
+// ByUser memakai user_id dari AuthMiddleware, atau alamat client bila
+// request belum login.
+func ByUser(c *gin.Context) string {
+	if id, ok := c.Get("user_id"); ok {
+		return fmt.Sprintf("user:%v", id)
+	}
+	return ByIP(c)
+}
+
+// seconds membulatkan ke atas agar client tidak mencoba terlalu cepat.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -1,59 +1,120 @@
 package http
 
 import (
-	"net/http"
+	"context"
+	"log"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
 
+	"cms/server/internal/cache"
 	"cms/server/internal/config"
+	"cms/server/internal/csvimport"
+	database "cms/server/internal/db"
+	"cms/server/internal/gql"
+	"cms/server/internal/mediacheck"
+	"cms/server/internal/metrics"
+	"cms/server/internal/ratelimit"
 	"cms/server/internal/repository"
+	"cms/server/internal/service"
+	"cms/server/internal/stream"
+	"cms/server/internal/transfer"
 	"cms/server/internal/transport/http/handler"
 	"cms/server/internal/transport/http/middleware"
-	"cms/server/pkg/minio"
+	"cms/server/internal/upload"
+	"cms/server/pkg/storage"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"gorm.io/gorm"
 )
 
-func NewRouter(cfg config.Config, db *gorm.DB) *gin.Engine {
-	r := gin.Default()
+// NewRouter membuat router API. store dipakai bersama worker media di main
+// agar poster & metadata yang ditulis worker terlihat oleh API. Pekerjaan
+// background (hub SSE, invalidasi cache) berjalan sampai ctx dibatalkan;
+// setelah itu /readyz menjawab 503. wait menunggu import CSV yang masih
+// berjalan saat server berhenti.
+func NewRouter(ctx context.Context, cfg config.Config, db *gorm.DB, store storage.Storage) (r *gin.Engine, wait func(context.Context) error) {
+	r = gin.New()
+	// span per request (kecuali health check), lalu log akses dengan
+	// request_id dan trace_id dari span itu
+	r.Use(
+		otelgin.Middleware(cfg.ServiceName, otelgin.WithGinFilter(func(c *gin.Context) bool {
+			switch c.FullPath() {
+			case "/healthz", "/livez", "/readyz":
+				return false
+			}
+			return true
+		})),
+		middleware.RequestLogger(),
+		middleware.Metrics(),
+		middleware.Recovery(),
+	)
+	registerMetrics(cfg, r, db)
+	if err := r.SetTrustedProxies(trustedProxies(cfg.TrustedProxies)); err != nil {
+		log.Fatalf("TRUSTED_PROXIES: %v", err)
+	}
 
 	// Middleware CORS
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", middleware.APIKeyHeader},
+		ExposeHeaders:    []string{"Content-Length", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
-	// Health check
-	r.GET("/healthz", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
-	})
+	// Health check: liveness dan readiness (Postgres + storage)
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("database: %v", err)
+	}
+	health := handler.NewHealthHandler(ctx, sqlDB, store)
+	r.GET("/healthz", health.Live)
+	r.GET("/livez", health.Live)
+	r.GET("/readyz", health.Ready)
 
-	// Swagger UI (opsional)
-	// r.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	// Rate limit per kelompok route: auth per IP, publik per API key atau
+	// IP, route yang butuh login per user
+	limits := newRateLimitStore(cfg)
+	apiKeys, err := ratelimit.ParseKeys(cfg.RateLimitAPIKeys)
+	if err != nil {
+		log.Fatalf("RATE_LIMIT_API_KEYS: %v", err)
+	}
+	authLimit := rateLimit(limits, "auth", cfg.RateLimitAuth, middleware.ByIP, nil)
+	publicLimit := rateLimit(limits, "public", cfg.RateLimitPublic, middleware.ByIP, apiKeys)
+	apiLimit := rateLimit(limits, "api", cfg.RateLimitAPI, middleware.ByUser, nil)
 
 	// Public API
 	api := r.Group("/api")
 
 	// Auth endpoints
 	auth := handler.NewAuthHandler(cfg, db)
-	api.POST("/auth/login", auth.Login)
-	api.POST("/auth/register", auth.Register) // demo
+	api.POST("/auth/login", authLimit, auth.Login)
+	api.POST("/auth/register", authLimit, auth.Register) // demo
+
+	// Live preview: SSE stream perubahan konten dari Postgres LISTEN/NOTIFY
+	hub := stream.NewHub(database.DSN(cfg))
+	go hub.Run(ctx)
+	events := handler.NewEventHandler(hub)
+	// payload berisi draft, jadi hanya untuk role yang boleh membaca draft
+	api.GET("/events", middleware.TokenFromQuery("access_token"), middleware.AuthMiddleware(cfg),
+		middleware.RequireRole("Editor", "Admin"), events.Stream)
 
 	// Protected routes (require JWT)
 	protected := api.Group("/")
-	protected.Use(middleware.AuthMiddleware(cfg))
+	protected.Use(middleware.AuthMiddleware(cfg), apiLimit)
 
 	auditRepo := repository.NewAuditRepository(db)
+	previews := service.NewPreviewService(cfg)
+	publisher := repository.NewEventPublisher(db)
 
 	{
 		// ContentType handler (editor & admin)
-		ctRepo := repository.NewContentTypeRepository(db)
+		ctRepo := repository.NewContentTypeRepository(db, publisher)
 		ct := handler.NewContentTypeHandler(ctRepo)
 		ctGroup := protected.Group("/content-types")
 		ctGroup.Use(middleware.RequireRole("Editor", "Admin"))
@@ -65,36 +126,87 @@ func NewRouter(cfg config.Config, db *gorm.DB) *gin.Engine {
 		ctGroup.POST("/:id/fields", ct.AddField)
 
 		// Entry handler
-		entryRepo := repository.NewEntryRepository(db, auditRepo)
-		entry := handler.NewEntryHandler(entryRepo)
+		entryRepo := repository.NewEntryRepository(db, auditRepo, publisher)
+		entry := handler.NewEntryHandler(entryRepo, previews)
 		entryGroup := protected.Group("/entries/:slug")
 		entryGroup.Use(middleware.RequireRole("Editor", "Admin"))
 		entryGroup.POST("", entry.Create)
+		entryGroup.POST("/bulk", entry.Bulk)
 		entryGroup.GET("", entry.List)
 		entryGroup.GET("/:id", entry.Detail)
 		entryGroup.PUT("/:id", entry.Update)
 		entryGroup.DELETE("/:id", entry.Delete)
 		entryGroup.POST("/:id/publish", entry.Publish)
+		entryGroup.POST("/:id/unpublish", entry.Unpublish)
 		entryGroup.POST("/:id/rollback/:version", entry.Rollback)
+		entryGroup.POST("/:id/preview-token", entry.PreviewToken)
+
+		// CSV import
+		importJobRepo := repository.NewImportJobRepository(db)
+		importer := csvimport.New(ctRepo, entryRepo, importJobRepo)
+		wait = importer.Wait
+		entryImport := handler.NewEntryImportHandler(importer, importJobRepo)
+		entryGroup.POST("/import", entryImport.Import)
+		entryGroup.GET("/import/:job", entryImport.Status)
 
 		// Media handler
-		minioClient := minio.New(
-			cfg.MinIOEndpoint,
-			cfg.MinIOAccessKey,
-			cfg.MinIOSecretKey,
-			cfg.MinIOBucket,
-			cfg.MinIOUseSSL,
-		)
-		mediaRepo := repository.NewMediaRepository(db)
-		media := handler.NewMediaHandler(minioClient, mediaRepo)
+		if s, ok := store.(storage.SignedURLs); ok {
+			files := handler.NewFileHandler(store, s.Signer())
+			api.GET("/files/*name", files.Serve) // storage.FilesPath
+		}
+		uploadLimits, err := upload.ParseLimits(cfg.UploadMaxSizes)
+		if err != nil {
+			slog.Warn("UPLOAD_MAX_SIZES tidak valid, memakai default", "error", err, "default", upload.DefaultLimits)
+			uploadLimits, _ = upload.ParseLimits(upload.DefaultLimits)
+		}
+		scanner, err := upload.NewScanner(cfg.ClamAVAddr)
+		if err != nil {
+			log.Fatalf("CLAMAV_ADDR: %v", err)
+		}
+		guard := &upload.Guard{
+			Allowed: upload.ParseAllowlist(cfg.UploadAllowedTypes),
+			Limits:  uploadLimits,
+			Scanner: scanner,
+		}
+		mediaRepo := repository.NewMediaRepository(db, publisher)
+		media := handler.NewMediaHandler(store, mediaRepo, repository.NewMediaJobRepository(db), guard)
+		mediaUploads := handler.NewMediaUploadHandler(store, repository.NewMediaUploadRepository(db, publisher), mediaRepo, guard)
 
 		mediaGroup := protected.Group("/media")
 		mediaGroup.Use(middleware.RequireRole("Editor", "Admin"))
 		mediaGroup.POST("", media.Upload)
 		mediaGroup.GET("/preview/:id", media.Preview)
+		mediaGroup.GET("/:id/render", media.Render)
+		mediaGroup.GET("/:id/poster", media.Poster)
+		mediaGroup.POST("/:id/process", media.Process)
+		mediaGroup.POST("/uploads", mediaUploads.Create)
+		mediaGroup.GET("/uploads/:id", mediaUploads.Detail)
+		mediaGroup.PUT("/uploads/:id/parts/:part", mediaUploads.PutPart)
+		mediaGroup.POST("/uploads/:id/complete", mediaUploads.Complete)
+		mediaGroup.DELETE("/uploads/:id", mediaUploads.Abort)
 		mediaGroup.GET("", media.List)
+		mediaGroup.GET("/tags", media.Tags)
+		mediaGroup.POST("/move", media.Move)
+		mediaGroup.GET("/:id", media.Detail)
+		mediaGroup.PUT("/:id", media.Update)
 		mediaGroup.DELETE("/:id", media.Delete)
 
+		// Public media delivery (tanpa login) untuk konten yang sudah publish
+		cacheMaxAge, err := time.ParseDuration(cfg.MediaCacheMaxAge)
+		if err != nil || cacheMaxAge < 0 {
+			slog.Warn("MEDIA_CACHE_MAX_AGE tidak valid, memakai 24h", "value", cfg.MediaCacheMaxAge)
+			cacheMaxAge = 24 * time.Hour
+		}
+		delivery := handler.NewMediaDeliveryHandler(store, mediaRepo, cacheMaxAge, cfg.MediaDelivery == "redirect")
+		r.GET("/media/:id/:filename", delivery.Serve)
+		r.HEAD("/media/:id/:filename", delivery.Serve)
+
+		folders := handler.NewMediaFolderHandler(repository.NewMediaFolderRepository(db))
+		mediaGroup.GET("/folders", folders.List)
+		mediaGroup.POST("/folders", folders.Create)
+		mediaGroup.PUT("/folders/:id", folders.Update)
+		mediaGroup.DELETE("/folders/:id", folders.Delete)
+
 		// // Admin-only endpoints
 		admin := protected.Group("/admin")
 		admin.Use(middleware.RequireRole("Admin"))
@@ -109,12 +221,165 @@ func NewRouter(cfg config.Config, db *gorm.DB) *gin.Engine {
 		admin.GET("/users", user.List)
 		admin.GET("/users/:id/roles", user.GetRoles)
 		admin.POST("/users/:id/roles", user.SetRoles)
+
+		transferSvc := transfer.New(db, store)
+		transferHandler := handler.NewTransferHandler(transferSvc)
+		admin.GET("/export", transferHandler.Export)
+		admin.POST("/import", transferHandler.Import)
+
+		audit := handler.NewAuditHandler(auditRepo)
+		admin.GET("/audit", audit.List)
+
+		reconcile := handler.NewMediaReconcileHandler(mediacheck.New(db, store))
+		admin.POST("/media/reconcile", reconcile.Reconcile)
+
+		webhookRepo := repository.NewWebhookRepository(db)
+		webhooks := handler.NewWebhookHandler(webhookRepo)
+		admin.GET("/webhooks", webhooks.List)
+		admin.POST("/webhooks", webhooks.Create)
+		admin.GET("/webhooks/:id", webhooks.Detail)
+		admin.PUT("/webhooks/:id", webhooks.Update)
+		admin.DELETE("/webhooks/:id", webhooks.Delete)
+		admin.GET("/webhooks/:id/deliveries", webhooks.Deliveries)
+		admin.POST("/webhooks/:id/deliveries/:delivery/redeliver", webhooks.Redeliver)
+	}
+
+	entryRepo := repository.NewEntryRepository(db, auditRepo, publisher)
+	publicHandler := handler.NewPublicHandler(entryRepo, previews)
+	publicCache := middleware.ResponseCache(middleware.ResponseCacheConfig{
+		Store:   newCacheStore(ctx, cfg, hub),
+		TTL:     publicCacheTTL(cfg),
+		Tags:    publicHandler.CacheTags,
+		Expires: publicHandler.CacheExpires,
+		Skip:    publicHandler.IsPreview,
+	})
+	r.GET("/api/public/:slug", publicLimit, publicCache, publicHandler.ListPublished)
+	r.GET("/api/public/:slug/:id", publicLimit, publicCache, publicHandler.GetPublished)
+
+	// GraphQL (baca saja); schema dibangun ulang saat content type berubah
+	graph := gql.New(
+		repository.NewContentTypeRepository(db, publisher),
+		entryRepo,
+		repository.NewMediaRepository(db, publisher),
+	)
+	go graph.Watch(ctx, hub)
+	graphQL := handler.NewGraphQLHandler(graph, previews)
+	r.GET("/api/graphql", publicLimit, graphQL.Query)
+	r.POST("/api/graphql", publicLimit, graphQL.Query)
+
+	// OpenAPI dibangun dari r.Routes() saat request, jadi semua route di
+	// atas ikut terdokumentasi
+	spec := handler.NewOpenAPIHandler(r.Routes, repository.NewContentTypeRepository(db, publisher))
+	r.GET("/api/openapi.json", publicLimit, spec.Spec)
+
+	return r, wait
+}
+
+// registerMetrics mendaftarkan metrik database dan antrian. Tanpa
+// METRICS_ADDR, /metrics dipasang di router ini hanya bila METRICS_TOKEN
+// diisi, agar metrik tidak terbuka untuk publik.
+func registerMetrics(cfg config.Config, r *gin.Engine, db *gorm.DB) {
+	if sqlDB, err := db.DB(); err == nil {
+		if err := metrics.RegisterDB(sqlDB); err != nil {
+			slog.Warn("metrics: database", "error", err)
+		}
+	}
+	err := metrics.RegisterQueues(map[string]metrics.QueueDepth{
+		"webhook_deliveries": repository.NewWebhookRepository(db).Pending,
+		"media_jobs":         repository.NewMediaJobRepository(db).Pending,
+		"import_jobs":        repository.NewImportJobRepository(db).Pending,
+	})
+	if err != nil {
+		slog.Warn("metrics: queues", "error", err)
+	}
+	switch {
+	case cfg.MetricsAddr != "":
+		// disajikan listener terpisah dari cmd/api
+	case cfg.MetricsToken != "":
+		r.GET("/metrics", gin.WrapH(metrics.Handler(cfg.MetricsToken)))
+	default:
+		slog.Info("metrics: /metrics nonaktif (set METRICS_ADDR atau METRICS_TOKEN)")
+	}
+}
+
+// trustedProxies membaca TRUSTED_PROXIES (IP/CIDR dipisah koma). Kosong
+// berarti tidak ada proxy yang dipercaya, sehingga X-Forwarded-For dari
+// client tidak bisa memalsukan IP untuk rate limit.
+func trustedProxies(spec string) []string {
+	var proxies []string
+	for _, p := range strings.Split(spec, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
 	}
+	return proxies
+}
 
-	entryRepo := repository.NewEntryRepository(db, auditRepo)
-	publicHandler := handler.NewPublicHandler(entryRepo)
-	r.GET("/api/public/:slug", publicHandler.ListPublished)
-	r.GET("/api/public/:slug/:id", publicHandler.GetPublished)
+// newCacheStore membuat cache response publik dan menjalankan invalidasi
+// dari event hub. Redis yang tidak bisa dihubungi saat start diganti
+// memory agar API tetap jalan; nil berarti CACHE_DRIVER=off.
+func newCacheStore(ctx context.Context, cfg config.Config, hub *stream.Hub) cache.Store {
+	store, err := cache.New(cache.Config{
+		Driver:        cfg.CacheDriver,
+		RedisAddr:     cfg.RedisAddr,
+		RedisPassword: cfg.RedisPassword,
+		RedisDB:       cfg.RedisDB,
+	})
+	if err != nil {
+		slog.Warn("cache tidak valid, memakai memory", "error", err)
+		store = cache.NewMemory(0)
+	}
+	if store == nil {
+		return nil
+	}
+	pingCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := store.Ping(pingCtx); err != nil {
+		slog.Warn("cache belum siap, memakai memory", "driver", cfg.CacheDriver, "error", err)
+		store = cache.NewMemory(0)
+	}
+	cancel()
+	go cache.Watch(ctx, store, hub)
+	return store
+}
 
-	return r
+func publicCacheTTL(cfg config.Config) time.Duration {
+	ttl, err := time.ParseDuration(cfg.PublicCacheTTL)
+	if err != nil || ttl < 0 {
+		slog.Warn("PUBLIC_CACHE_TTL tidak valid, memakai 5m", "value", cfg.PublicCacheTTL)
+		return 5 * time.Minute
+	}
+	return ttl
+}
+
+// newRateLimitStore membuat penyimpanan bucket. Store Redis sendiri jatuh
+// ke bucket di memori selama Redis tidak bisa dihubungi.
+func newRateLimitStore(cfg config.Config) ratelimit.Store {
+	store, err := ratelimit.New(ratelimit.Config{
+		Driver:        cfg.RateLimitDriver,
+		RedisAddr:     cfg.RedisAddr,
+		RedisPassword: cfg.RedisPassword,
+		RedisDB:       cfg.RedisDB,
+	})
+	if err != nil {
+		log.Fatalf("RATE_LIMIT_DRIVER: %v", err)
+	}
+	pingCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := store.Ping(pingCtx); err != nil {
+		slog.Warn("rate limit belum siap", "driver", cfg.RateLimitDriver, "error", err)
+	}
+	cancel()
+	return store
+}
+
+func rateLimit(store ratelimit.Store, name, spec string, key func(*gin.Context) string, keys ratelimit.Keys) gin.HandlerFunc {
+	policy, err := ratelimit.ParsePolicy(name, spec)
+	if err != nil {
+		log.Fatalf("RATE_LIMIT_%s: %v", strings.ToUpper(name), err)
+	}
+	return middleware.RateLimit(middleware.RateLimitConfig{
+		Store:   store,
+		Policy:  policy,
+		Key:     key,
+		APIKeys: keys,
+	})
 }
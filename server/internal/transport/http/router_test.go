@@ -0,0 +1,73 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cms/server/internal/ratelimit"
+	"cms/server/internal/transport/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTrustedProxies(t *testing.T) {
+	if got := trustedProxies(""); got != nil {
+		t.Errorf("trustedProxies(\"\") = %v, want nil", got)
+	}
+	got := trustedProxies(" 10.0.0.1, 10.1.0.0/16 ,")
+	if len(got) != 2 || got[0] != "10.0.0.1" || got[1] != "10.1.0.0/16" {
+		t.Errorf("trustedProxies = %q, want [10.0.0.1 10.1.0.0/16]", got)
+	}
+}
+
+// TestRateLimitClientIP memastikan X-Forwarded-For hanya dipakai bila
+// request datang dari proxy di TRUSTED_PROXIES.
+func TestRateLimitClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name    string
+		trusted string
+		// status request kedua dari proxy yang sama dengan X-Forwarded-For lain
+		want int
+	}{
+		{"no trusted proxies", "", http.StatusTooManyRequests},
+		{"proxy not trusted", "10.9.9.9", http.StatusTooManyRequests},
+		{"trusted proxy", "10.0.0.1, 10.0.0.2", http.StatusOK},
+		{"trusted proxy cidr", "10.0.0.0/8", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			if err := r.SetTrustedProxies(trustedProxies(tt.trusted)); err != nil {
+				t.Fatalf("SetTrustedProxies: %v", err)
+			}
+			r.Use(middleware.RateLimit(middleware.RateLimitConfig{
+				Store:  ratelimit.NewMemory(),
+				Policy: ratelimit.Policy{Name: "api", Limit: 1, Window: time.Minute},
+				Key:    middleware.ByIP,
+			}))
+			r.GET("/", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+			do := func(forwarded string) *httptest.ResponseRecorder {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.RemoteAddr = "10.0.0.1:4321"
+				req.Header.Set("X-Forwarded-For", forwarded)
+				rec := httptest.NewRecorder()
+				r.ServeHTTP(rec, req)
+				return rec
+			}
+			if rec := do("203.0.113.1"); rec.Code != http.StatusOK {
+				t.Fatalf("first request: status %d", rec.Code)
+			}
+			rec := do("203.0.113.2")
+			if rec.Code != tt.want {
+				t.Fatalf("second request: status %d, want %d", rec.Code, tt.want)
+			}
+			if rec.Code == http.StatusTooManyRequests && rec.Header().Get("Retry-After") != "60" {
+				t.Errorf("Retry-After = %q, want 60", rec.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package http
+
+import (
+	"net/http"
+
+	"cms/server/internal/config"
+)
+
+// NewServer membuat server API di APP_PORT dengan timeout dari cfg.
+func NewServer(cfg config.Config, h http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              ":" + cfg.AppPort,
+		Handler:           h,
+		ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTPReadTimeout,
+		WriteTimeout:      cfg.HTTPWriteTimeout,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
+	}
+}
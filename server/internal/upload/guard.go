@@ -0,0 +1,109 @@
+package upload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInvalidContent dikembalikan bila SVG/gambar rusak sehingga tidak bisa
+// dibersihkan.
+var ErrInvalidContent = errors.New("file content is invalid")
+
+// Guard menjalankan semua pemeriksaan sebelum file disimpan sebagai
+// MediaAsset: sniffing mime, allowlist, kecocokan ekstensi, batas ukuran,
+// scan malware, lalu sanitasi SVG dan penghapusan metadata gambar.
+type Guard struct {
+	Allowed Allowlist
+	Limits  Limits
+	Scanner Scanner
+}
+
+type Result struct {
+	// Mime hasil sniffing, bukan Content-Type dari client.
+	Mime string
+	// Ext adalah ekstensi utama untuk Mime (mis. ".jpg").
+	Ext string
+	// Data berisi isi file yang sudah diubah (SVG disanitasi, metadata
+	// dihapus). nil berarti isi asli dipakai apa adanya.
+	Data []byte
+	// Checksum adalah SHA-256 (hex) dari isi yang akan disimpan, yaitu Data
+	// bila tidak nil.
+	Checksum string
+}
+
+// Inspect memeriksa f berukuran size. Posisi f dikembalikan ke awal.
+func (g *Guard) Inspect(ctx context.Context, filename string, f io.ReadSeeker, size int64) (*Result, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	mimeType, ext := Sniff(head[:n])
+	res := &Result{Mime: mimeType, Ext: ext}
+
+	if !g.Allowed.Allows(mimeType) {
+		return nil, fmt.Errorf("%w: %s", ErrTypeNotAllowed, mimeType)
+	}
+	if err := CheckExtension(filename, mimeType); err != nil {
+		return nil, err
+	}
+	if limit := g.Limits.For(mimeType); size > limit {
+		return nil, fmt.Errorf("%w (max %s for %s)", ErrTooLarge, FormatSize(limit), mimeType)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	// checksum dihitung sambil scan agar file cukup dibaca sekali
+	h := sha256.New()
+	body := io.TeeReader(io.LimitReader(f, size), h)
+	if g.Scanner != nil {
+		if err := g.Scanner.Scan(ctx, body); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return nil, err
+	}
+	res.Checksum = hex.EncodeToString(h.Sum(nil))
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	switch mimeType {
+	case "image/svg+xml", "image/jpeg", "image/png", "image/webp":
+		data, err := io.ReadAll(io.LimitReader(f, size))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if mimeType == "image/svg+xml" {
+			res.Data, err = SanitizeSVG(data)
+		} else {
+			res.Data, err = StripMetadata(mimeType, data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidContent, err)
+		}
+		res.Checksum = Checksum(res.Data)
+	}
+	return res, nil
+}
+
+// Checksum mengembalikan SHA-256 (hex) dari data.
+func Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ObjectName adalah nama object content-addressed untuk isi dengan checksum
+// tersebut, sehingga file yang sama selalu disimpan di tempat yang sama.
+func ObjectName(checksum, ext string) string {
+	return checksum + ext
+}
@@ -0,0 +1,174 @@
+// Package upload berisi aturan ukuran upload media dan perhitungan part
+// untuk multipart upload.
+package upload
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DefaultLimits dipakai bila UPLOAD_MAX_SIZES tidak diisi.
+const DefaultLimits = "image/*=20MB,video/*=2GB,audio/*=200MB,*=50MB"
+
+// Limits memetakan pola mime ("image/png", "image/*" atau "*") ke ukuran
+// maksimal dalam byte.
+type Limits map[string]int64
+
+// ParseLimits membaca format "image/*=20MB,video/*=2GB,*=50MB". Satuan yang
+// dikenal: B, KB, MB, GB (kelipatan 1024).
+func ParseLimits(spec string) (Limits, error) {
+	l := Limits{}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		pattern, size, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("upload limit %q: expected <mime>=<size>", item)
+		}
+		n, err := ParseSize(size)
+		if err != nil {
+			return nil, fmt.Errorf("upload limit %q: %w", item, err)
+		}
+		l[strings.ToLower(strings.TrimSpace(pattern))] = n
+	}
+	if _, ok := l["*"]; !ok {
+		return nil, fmt.Errorf("upload limits must contain a default (*=<size>)")
+	}
+	return l, nil
+}
+
+func ParseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	mult := int64(1)
+	for _, u := range []struct {
+		suffix string
+		mult   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(s, u.suffix) {
+			s, mult = strings.TrimSpace(strings.TrimSuffix(s, u.suffix)), u.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * mult, nil
+}
+
+// For mengembalikan batas ukuran untuk mime: cocok persis, lalu "type/*",
+// lalu default "*".
+func (l Limits) For(mime string) int64 {
+	mime = baseMime(mime)
+	if n, ok := l[mime]; ok {
+		return n
+	}
+	if typ, _, ok := strings.Cut(mime, "/"); ok {
+		if n, ok := l[typ+"/*"]; ok {
+			return n
+		}
+	}
+	return l["*"]
+}
+
+// Max adalah batas terbesar dari semua pola, dipakai untuk membatasi body
+// request sebelum mime file diketahui.
+func (l Limits) Max() int64 {
+	var m int64
+	for _, n := range l {
+		m = max(m, n)
+	}
+	return m
+}
+
+// LimitedReader meneruskan isi reader selama ukurannya tidak melebihi batas
+// untuk mime hasil sniffing byte-byte pertamanya.
+type LimitedReader struct {
+	r     io.Reader
+	mime  string
+	limit int64
+	n     int64
+	err   error
+}
+
+// Reader membungkus r dengan batas ukuran dari l. Mime ditentukan dari
+// awal isi r sebelum byte pertama diteruskan, sehingga file yang terlalu
+// besar berhenti dibaca begitu batasnya terlewati.
+func (l Limits) Reader(r io.Reader) *LimitedReader {
+	br := bufio.NewReaderSize(r, sniffLen)
+	// error Peek (mis. body terlalu besar) muncul lagi saat Read
+	head, _ := br.Peek(sniffLen)
+	mimeType, _ := Sniff(head)
+	return &LimitedReader{r: br, mime: mimeType, limit: l.For(mimeType)}
+}
+
+// Mime adalah mime hasil sniffing yang menentukan batas ukuran.
+func (lr *LimitedReader) Mime() string { return lr.mime }
+
+func (lr *LimitedReader) Read(p []byte) (int, error) {
+	if lr.err != nil {
+		return 0, lr.err
+	}
+	n, err := lr.r.Read(p)
+	lr.n += int64(n)
+	if lr.n > lr.limit {
+		lr.err = fmt.Errorf("%w (max %s for %s)", ErrTooLarge, FormatSize(lr.limit), lr.mime)
+		return 0, lr.err
+	}
+	return n, err
+}
+
+// Err mengembalikan ErrTooLarge bila batas terlewati. Backend storage
+// belum tentu meneruskan error dari reader apa adanya, jadi periksa Err
+// setelah Put gagal.
+func (lr *LimitedReader) Err() error { return lr.err }
+
+// FormatSize menampilkan ukuran dalam satuan yang mudah dibaca.
+func FormatSize(n int64) string {
+	switch {
+	case n >= 1<<30 && n%(1<<30) == 0:
+		return strconv.FormatInt(n>>30, 10) + "GB"
+	case n >= 1<<20 && n%(1<<20) == 0:
+		return strconv.FormatInt(n>>20, 10) + "MB"
+	case n >= 1<<10 && n%(1<<10) == 0:
+		return strconv.FormatInt(n>>10, 10) + "KB"
+	}
+	return strconv.FormatInt(n, 10) + "B"
+}
+
+const (
+	// batas dari S3: part minimal 5MB (kecuali part terakhir), maks 10000 part
+	minPartSize = 8 << 20
+	maxParts    = 10000
+)
+
+// PartSize menghitung ukuran part untuk file berukuran size.
+func PartSize(size int64) int64 {
+	ps := int64(minPartSize)
+	if need := (size + maxParts - 1) / maxParts; need > ps {
+		// bulatkan ke atas ke kelipatan 1MB
+		ps = (need + 1<<20 - 1) / (1 << 20) * (1 << 20)
+	}
+	return ps
+}
+
+// PartCount menghitung jumlah part untuk size dengan ukuran part partSize.
+func PartCount(size, partSize int64) int {
+	if size <= 0 {
+		return 1
+	}
+	return int((size + partSize - 1) / partSize)
+}
+
+// ExpectedPartSize adalah ukuran yang harus dimiliki part ke-n (mulai 1).
+func ExpectedPartSize(size, partSize int64, n int) int64 {
+	if n == PartCount(size, partSize) {
+		return size - int64(n-1)*partSize
+	}
+	return partSize
+}
@@ -0,0 +1,46 @@
+package upload
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestLimitsReader(t *testing.T) {
+	limits := Limits{"image/*": 1 << 10, "*": 4 << 10}
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 16)...)
+
+	tests := []struct {
+		name     string
+		data     []byte
+		wantMime string
+		tooLarge bool
+	}{
+		{"text under default", bytes.Repeat([]byte("a"), 2<<10), "text/plain", false},
+		{"text over default", bytes.Repeat([]byte("a"), 5<<10), "text/plain", true},
+		{"png uses image limit", append(png, make([]byte, 2<<10)...), "image/png", true},
+		{"png under image limit", png, "image/png", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lr := limits.Reader(bytes.NewReader(tt.data))
+			if lr.Mime() != tt.wantMime {
+				t.Errorf("Mime() = %q, want %q", lr.Mime(), tt.wantMime)
+			}
+			n, err := io.Copy(io.Discard, lr)
+			if got := errors.Is(err, ErrTooLarge); got != tt.tooLarge {
+				t.Fatalf("Copy error = %v, want ErrTooLarge %v", err, tt.tooLarge)
+			}
+			if tt.tooLarge {
+				if !errors.Is(lr.Err(), ErrTooLarge) {
+					t.Errorf("Err() = %v, want ErrTooLarge", lr.Err())
+				}
+				return
+			}
+			if n != int64(len(tt.data)) {
+				t.Errorf("copied %d bytes, want %d", n, len(tt.data))
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image/jpeg"
+
+	"cms/server/internal/imageproc"
+)
+
+var errMalformed = errors.New("malformed image")
+
+// StripMetadata menghapus metadata EXIF/XMP/IPTC (termasuk lokasi GPS) dari
+// JPEG, PNG dan WebP tanpa meng-encode ulang gambar. Pengecualian: JPEG
+// dengan EXIF Orientation selain normal di-encode ulang dalam posisi yang
+// sudah diputar, agar gambar tidak tampil miring setelah EXIF dibuang.
+// Mime lain dikembalikan apa adanya.
+func StripMetadata(mimeType string, data []byte) ([]byte, error) {
+	switch mimeType {
+	case "image/jpeg":
+		return stripJPEG(data)
+	case "image/png":
+		return stripPNG(data)
+	case "image/webp":
+		return stripWebP(data)
+	}
+	return data, nil
+}
+
+func stripJPEG(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, errMalformed
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:2])
+	orientation := 1
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xFF {
+			return nil, errMalformed
+		}
+		marker := data[i+1]
+		if marker == 0xFF { // padding
+			i++
+			continue
+		}
+		if marker == 0xDA { // start of scan: sisanya data gambar
+			out.Write(data[i:])
+			break
+		}
+		n := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + n
+		if n < 2 || end > len(data) {
+			return nil, errMalformed
+		}
+		seg := data[i:end]
+		switch marker {
+		case 0xE1: // APP1: EXIF / XMP
+			if o := exifOrientation(seg[4:]); o != 0 {
+				orientation = o
+			}
+		case 0xED: // APP13: IPTC
+		case 0xFE: // komentar
+		default:
+			out.Write(seg)
+		}
+		i = end
+	}
+
+	if orientation == 1 {
+		return out.Bytes(), nil
+	}
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, imageproc.Orient(img, orientation), &jpeg.Options{Quality: 92}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// exifOrientation membaca tag Orientation (0x0112) dari IFD0 payload APP1.
+// Mengembalikan 0 bila tidak ada.
+func exifOrientation(p []byte) int {
+	if len(p) < 14 || string(p[:6]) != "Exif\x00\x00" {
+		return 0
+	}
+	tiff := p[6:]
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 0
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for k := 0; k < count; k++ {
+		e := ifd + 2 + k*12
+		if e+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[e:]) == 0x0112 {
+			return int(order.Uint16(tiff[e+8:]))
+		}
+	}
+	return 0
+}
+
+// chunk PNG yang bisa berisi metadata
+var pngMetadataChunks = map[string]bool{"eXIf": true, "tEXt": true, "zTXt": true, "iTXt": true, "tIME": true}
+
+func stripPNG(data []byte) ([]byte, error) {
+	if len(data) < 8 || string(data[:8]) != "\x89PNG\r\n\x1a\n" {
+		return nil, errMalformed
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:8])
+	for i := 8; i < len(data); {
+		if i+12 > len(data) {
+			return nil, errMalformed
+		}
+		n := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + n
+		if n < 0 || end > len(data) {
+			return nil, errMalformed
+		}
+		if !pngMetadataChunks[string(data[i+4:i+8])] {
+			out.Write(data[i:end])
+		}
+		i = end
+	}
+	return out.Bytes(), nil
+}
+
+func stripWebP(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, errMalformed
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:12])
+	vp8x := -1
+	for i := 12; i < len(data); {
+		if i+8 > len(data) {
+			return nil, errMalformed
+		}
+		n := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + n + n%2
+		if end > len(data) {
+			// beberapa encoder tidak menulis padding di chunk terakhir
+			end = len(data)
+		}
+		switch fourcc := string(data[i : i+4]); fourcc {
+		case "EXIF", "XMP ":
+		default:
+			if fourcc == "VP8X" {
+				vp8x = out.Len()
+			}
+			out.Write(data[i:end])
+		}
+		i = end
+	}
+	b := out.Bytes()
+	if vp8x >= 0 && vp8x+8 < len(b) {
+		b[vp8x+8] &^= 0x08 | 0x04 // flag EXIF & XMP
+	}
+	binary.LittleEndian.PutUint32(b[4:], uint32(len(b)-8))
+	return b, nil
+}
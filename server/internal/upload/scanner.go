@@ -0,0 +1,109 @@
+package upload
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInfected dikembalikan bila scanner menemukan malware.
+	ErrInfected = errors.New("file is infected")
+	// ErrScanFailed dikembalikan bila scanner tidak bisa dihubungi atau
+	// memberi jawaban yang tidak dikenal. Upload ditolak (fail closed).
+	ErrScanFailed = errors.New("virus scan failed")
+)
+
+// Scanner memeriksa isi file sebelum disimpan sebagai MediaAsset.
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) error
+}
+
+// NopScanner menerima semua file. Dipakai bila CLAMAV_ADDR tidak diisi.
+type NopScanner struct{}
+
+func (NopScanner) Scan(context.Context, io.Reader) error { return nil }
+
+// NewScanner memilih scanner dari alamat: kosong → NopScanner, selain itu
+// ClamAV ("tcp://host:3310" atau "unix:///run/clamav/clamd.sock").
+func NewScanner(addr string) (Scanner, error) {
+	if addr == "" {
+		return NopScanner{}, nil
+	}
+	return NewClamAV(addr)
+}
+
+// ClamAV memindai file lewat perintah INSTREAM milik clamd.
+type ClamAV struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+func NewClamAV(addr string) (*ClamAV, error) {
+	network, address, ok := strings.Cut(addr, "://")
+	if !ok || (network != "tcp" && network != "unix") || address == "" {
+		return nil, fmt.Errorf("clamav address %q: expected tcp://host:port or unix:///path", addr)
+	}
+	return &ClamAV{network: network, address: address, timeout: 2 * time.Minute}, nil
+}
+
+const clamChunkSize = 64 << 10
+
+func (s *ClamAV) Scan(ctx context.Context, r io.Reader) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrScanFailed, err)
+	}
+	defer conn.Close()
+	deadline := time.Now().Add(s.timeout)
+	if dl, ok := ctx.Deadline(); ok && dl.Before(deadline) {
+		deadline = dl
+	}
+	_ = conn.SetDeadline(deadline)
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return fmt.Errorf("%w: %v", ErrScanFailed, err)
+	}
+	buf := make([]byte, 4+clamChunkSize)
+	for {
+		n, rerr := r.Read(buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf, uint32(n))
+			if _, err := conn.Write(buf[:4+n]); err != nil {
+				// clamd memutus koneksi bila StreamMaxLength terlampaui;
+				// jawabannya tetap dibaca di bawah
+				break
+			}
+		}
+		if errors.Is(rerr, io.EOF) {
+			break
+		}
+		if rerr != nil {
+			return rerr
+		}
+	}
+	_, _ = conn.Write([]byte{0, 0, 0, 0})
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		return fmt.Errorf("%w: %v", ErrScanFailed, err)
+	}
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+	switch {
+	case strings.HasSuffix(reply, "OK"):
+		return nil
+	case strings.HasSuffix(reply, "FOUND"):
+		// "stream: Eicar-Signature FOUND"
+		sig := strings.TrimSuffix(strings.TrimPrefix(reply, "stream: "), " FOUND")
+		return fmt.Errorf("%w: %s", ErrInfected, sig)
+	}
+	return fmt.Errorf("%w: clamd replied %q", ErrScanFailed, reply)
+}
@@ -0,0 +1,109 @@
+package upload
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// DefaultAllowedTypes dipakai bila UPLOAD_ALLOWED_TYPES tidak diisi.
+const DefaultAllowedTypes = "image/jpeg,image/png,image/gif,image/webp,image/svg+xml,image/avif,video/*,audio/*,application/pdf,text/plain,text/csv"
+
+var (
+	ErrTypeNotAllowed    = errors.New("file type is not allowed")
+	ErrExtensionMismatch = errors.New("file extension does not match its content")
+	ErrTooLarge          = errors.New("file is too large")
+)
+
+// sniffLen adalah jumlah byte awal yang dibaca untuk mendeteksi mime.
+const sniffLen = 3072
+
+// ekstensi tambahan yang lazim dipakai selain ekstensi utama dari mimetype
+var extAliases = map[string][]string{
+	"image/jpeg":      {".jpg", ".jpeg", ".jpe", ".jfif"},
+	"image/tiff":      {".tif", ".tiff"},
+	"image/svg+xml":   {".svg"},
+	"text/plain":      {".txt", ".text", ".md", ".log"},
+	"audio/mpeg":      {".mp3", ".mpga"},
+	"video/mp4":       {".mp4", ".m4v"},
+	"video/quicktime": {".mov", ".qt"},
+}
+
+// Sniff mendeteksi mime dari isi file (tanpa parameter seperti charset)
+// beserta ekstensi utamanya.
+func Sniff(head []byte) (string, string) {
+	m := mimetype.Detect(head)
+	return baseMime(m.String()), m.Extension()
+}
+
+// Allowlist berisi pola mime ("image/png", "image/*" atau "*") yang boleh
+// diupload.
+type Allowlist []string
+
+func ParseAllowlist(spec string) Allowlist {
+	var a Allowlist
+	for _, p := range strings.Split(spec, ",") {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			a = append(a, p)
+		}
+	}
+	return a
+}
+
+func (a Allowlist) Allows(mimeType string) bool {
+	mimeType = baseMime(mimeType)
+	typ, _, _ := strings.Cut(mimeType, "/")
+	for _, p := range a {
+		if p == "*" || p == mimeType || p == typ+"/*" {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckExtension memastikan ekstensi nama file cocok dengan mime hasil
+// sniffing. File tanpa ekstensi diterima.
+func CheckExtension(filename, mimeType string) error {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == "" {
+		return nil
+	}
+	for _, e := range extensionsFor(mimeType) {
+		if e == ext {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is not a valid extension for %s", ErrExtensionMismatch, ext, mimeType)
+}
+
+// extensionsFor mengumpulkan ekstensi yang sah untuk mime, termasuk milik
+// parent-nya (mis. JSON juga sah sebagai .txt). Root application/octet-stream
+// tidak ikut, karena ekstensinya (.exe, .bin, ...) akan cocok untuk file apa
+// pun.
+func extensionsFor(mimeType string) []string {
+	var exts []string
+	start := mimetype.Lookup(mimeType)
+	for m := start; m != nil; m = m.Parent() {
+		if m != start && m.Parent() == nil {
+			break
+		}
+		name := baseMime(m.String())
+		if e := m.Extension(); e != "" {
+			exts = append(exts, e)
+		}
+		exts = append(exts, extAliases[name]...)
+		if byType, err := mime.ExtensionsByType(name); err == nil {
+			exts = append(exts, byType...)
+		}
+	}
+	exts = append(exts, extAliases[mimeType]...)
+	return exts
+}
+
+func baseMime(m string) string {
+	return strings.ToLower(strings.TrimSpace(strings.Split(m, ";")[0]))
+}
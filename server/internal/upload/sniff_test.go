@@ -0,0 +1,100 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func pngBytes(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestCheckExtension(t *testing.T) {
+	tests := []struct {
+		filename string
+		mime     string
+		wantErr  bool
+	}{
+		{"photo.jpg", "image/jpeg", false},
+		{"photo.JPEG", "image/jpeg", false},
+		{"icon.svg", "image/svg+xml", false},
+		{"notes", "text/plain", false},
+		{"data.json", "application/json", false},
+		{"photo.png", "image/jpeg", true},
+		{"page.svg", "text/html", true},
+		{"invoice.pdf.exe", "application/pdf", true},
+		{"archive.bin", "application/zip", true},
+		{"blob.bin", "application/octet-stream", false},
+	}
+	for _, tt := range tests {
+		err := CheckExtension(tt.filename, tt.mime)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckExtension(%q, %s) = %v, wantErr %v", tt.filename, tt.mime, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrExtensionMismatch) {
+			t.Errorf("CheckExtension(%q, %s) = %v, want ErrExtensionMismatch", tt.filename, tt.mime, err)
+		}
+	}
+}
+
+func TestGuardInspect(t *testing.T) {
+	g := &Guard{
+		Allowed: ParseAllowlist("image/*,text/plain"),
+		Limits:  Limits{"image/*": 1 << 20, "*": 64},
+	}
+	pngData := pngBytes(t)
+	svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`)
+
+	tests := []struct {
+		name     string
+		filename string
+		data     []byte
+		wantMime string
+		wantErr  error
+	}{
+		{"png", "a.png", pngData, "image/png", nil},
+		{"png named jpg", "a.jpg", pngData, "", ErrExtensionMismatch},
+		{"svg named png", "logo.png", svg, "", ErrExtensionMismatch},
+		{"text named png", "a.png", []byte("just text"), "", ErrExtensionMismatch},
+		{"html not allowed", "a.html", []byte("<!DOCTYPE html><html><body>x</body></html>"), "", ErrTypeNotAllowed},
+		{"text over limit", "a.txt", bytes.Repeat([]byte("a"), 65), "", ErrTooLarge},
+		{"svg sanitized", "logo.svg", svg, "image/svg+xml", nil},
+		{"broken svg", "logo.svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg"><rect width="1 <</svg>`), "", ErrInvalidContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := g.Inspect(context.Background(), tt.filename, bytes.NewReader(tt.data), int64(len(tt.data)))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Inspect error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Inspect: %v", err)
+			}
+			if res.Mime != tt.wantMime {
+				t.Errorf("Mime = %q, want %q", res.Mime, tt.wantMime)
+			}
+			stored := tt.data
+			if res.Data != nil {
+				stored = res.Data
+			}
+			if res.Checksum != Checksum(stored) {
+				t.Errorf("Checksum does not match the stored content")
+			}
+			if bytes.Contains(stored, []byte("script")) {
+				t.Errorf("stored content still contains a script: %s", stored)
+			}
+		})
+	}
+}
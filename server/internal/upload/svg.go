@@ -0,0 +1,165 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// elemen yang dibuang beserta seluruh isinya
+var svgDropElements = map[string]bool{
+	"script":        true,
+	"foreignobject": true,
+	"iframe":        true,
+	"embed":         true,
+	"object":        true,
+	"handler":       true,
+	"listener":      true,
+	"base":          true,
+	"meta":          true,
+}
+
+// elemen animasi bisa mengubah atribut lain (mis. href menjadi javascript:)
+var svgAnimateElements = map[string]bool{
+	"set":              true,
+	"animate":          true,
+	"animatemotion":    true,
+	"animatetransform": true,
+}
+
+var unsafeCSS = regexp.MustCompile(`(?i)(javascript:|expression\s*\(|@import|behavior\s*:|-moz-binding)`)
+
+// SanitizeSVG membuang script, event handler (on*), elemen yang bisa
+// memuat HTML/konten luar, DOCTYPE/entity dan URL berbahaya dari SVG.
+func SanitizeSVG(src []byte) ([]byte, error) {
+	dec := xml.NewDecoder(bytes.NewReader(src))
+	dec.Strict = false
+	// entity tidak pernah di-expand; DOCTYPE dibuang di bawah
+	dec.Entity = map[string]string{}
+
+	var out bytes.Buffer
+	skip := 0 // kedalaman elemen yang sedang dibuang
+	sawRoot := false
+	for {
+		tok, err := dec.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid svg: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			if !sawRoot {
+				if name != "svg" {
+					return nil, errors.New("invalid svg: root element is not <svg>")
+				}
+				sawRoot = true
+			}
+			if skip > 0 || svgDropElements[name] || (svgAnimateElements[name] && animatesUnsafe(t)) {
+				skip++
+				continue
+			}
+			out.WriteByte('<')
+			out.WriteString(qname(t.Name))
+			for _, a := range t.Attr {
+				if !safeSVGAttr(name, a) {
+					continue
+				}
+				out.WriteByte(' ')
+				out.WriteString(qname(a.Name))
+				out.WriteString(`="`)
+				_ = xml.EscapeText(&out, []byte(a.Value))
+				out.WriteByte('"')
+			}
+			out.WriteByte('>')
+		case xml.EndElement:
+			if skip > 0 {
+				skip--
+				continue
+			}
+			out.WriteString("</" + qname(t.Name) + ">")
+		case xml.CharData:
+			if skip > 0 {
+				continue
+			}
+			if unsafeCSS.Match(t) {
+				// isi <style> yang mencurigakan dibuang seluruhnya
+				continue
+			}
+			_ = xml.EscapeText(&out, t)
+		case xml.ProcInst:
+			if t.Target == "xml" && skip == 0 && out.Len() == 0 {
+				out.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
+			}
+		case xml.Directive, xml.Comment:
+			// DOCTYPE/ENTITY & komentar dibuang
+		}
+	}
+	if !sawRoot {
+		return nil, errors.New("invalid svg: no <svg> element")
+	}
+	return out.Bytes(), nil
+}
+
+func qname(n xml.Name) string {
+	if n.Space != "" {
+		return n.Space + ":" + n.Local
+	}
+	return n.Local
+}
+
+func safeSVGAttr(elem string, a xml.Attr) bool {
+	local := strings.ToLower(a.Name.Local)
+	if strings.HasPrefix(local, "on") {
+		return false
+	}
+	value := strings.TrimSpace(a.Value)
+	switch local {
+	case "href", "src", "action", "formaction":
+		if elem == "use" {
+			// <use> menyisipkan isi dokumen lain (termasuk script di
+			// dalamnya), jadi hanya boleh menunjuk elemen di file ini
+			return strings.HasPrefix(value, "#")
+		}
+		return safeURL(value)
+	}
+	// style dan url(...) di atribut presentasi (fill, filter, mask, ...)
+	return !unsafeCSS.MatchString(value)
+}
+
+// safeURL menerima referensi internal (#id), http(s) dan data URI gambar
+// raster. SVG di dalam data URI ditolak karena bisa berisi script lagi.
+func safeURL(v string) bool {
+	lower := strings.ToLower(strings.Join(strings.Fields(v), ""))
+	switch {
+	case lower == "", strings.HasPrefix(lower, "#"):
+		return true
+	case strings.HasPrefix(lower, "http://"), strings.HasPrefix(lower, "https://"):
+		return true
+	}
+	for _, p := range []string{"data:image/png", "data:image/jpeg", "data:image/gif", "data:image/webp"} {
+		if strings.HasPrefix(lower, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func animatesUnsafe(t xml.StartElement) bool {
+	for _, a := range t.Attr {
+		if strings.ToLower(a.Name.Local) != "attributename" {
+			continue
+		}
+		v := strings.ToLower(strings.TrimSpace(a.Value))
+		if strings.HasSuffix(v, "href") || strings.HasPrefix(v, "on") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package upload
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeSVG(t *testing.T) {
+	const open = `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink">`
+	tests := []struct {
+		name string
+		src  string
+		// drop tidak boleh ada di hasil, keep harus tetap ada
+		drop []string
+		keep []string
+	}{
+		{
+			name: "script tag",
+			src:  open + `<script>alert(1)</script><circle r="1"/></svg>`,
+			drop: []string{"script", "alert"},
+			keep: []string{`<circle r="1">`},
+		},
+		{
+			name: "script in CDATA",
+			src:  open + `<script><![CDATA[alert(1)]]></script></svg>`,
+			drop: []string{"script", "alert"},
+		},
+		{
+			name: "event handlers",
+			src:  open + `<rect onclick="alert(1)" ONLOAD="x()" width="2"/></svg>`,
+			drop: []string{"onclick", "ONLOAD", "alert"},
+			keep: []string{`width="2"`},
+		},
+		{
+			name: "javascript href",
+			src:  open + `<a href="javascript:alert(1)"><text>x</text></a><a xlink:href=" JaVa script:alert(2)">y</a></svg>`,
+			drop: []string{"javascript", "JaVa", "alert"},
+			keep: []string{"<text>x</text>"},
+		},
+		{
+			name: "svg data href",
+			src:  open + `<image href="data:image/svg+xml;base64,PHN2Zz4="/><image href="data:text/html,%3Cscript%3E"/></svg>`,
+			drop: []string{"data:"},
+		},
+		{
+			name: "raster data href kept",
+			src:  open + `<image href="data:image/png;base64,iVBORw0KGgo="/></svg>`,
+			keep: []string{`href="data:image/png;base64,iVBORw0KGgo="`},
+		},
+		{
+			name: "foreignObject",
+			src:  open + `<foreignObject><div xmlns="http://www.w3.org/1999/xhtml"><iframe src="x"></iframe></div></foreignObject><g/></svg>`,
+			drop: []string{"foreignObject", "div", "iframe"},
+			keep: []string{"<g>"},
+		},
+		{
+			name: "external use reference",
+			src:  open + `<use xlink:href="https://evil.example/sprite.svg#icon"/><use href="#local"/></svg>`,
+			drop: []string{"evil.example"},
+			keep: []string{`href="#local"`},
+		},
+		{
+			name: "external link kept",
+			src:  open + `<a xlink:href="https://example.com/">x</a></svg>`,
+			keep: []string{`xlink:href="https://example.com/"`},
+		},
+		{
+			name: "animation rewriting href",
+			src:  open + `<a href="#a"><set attributeName="href" to="javascript:alert(1)"/></a></svg>`,
+			drop: []string{"set", "javascript"},
+		},
+		{
+			name: "doctype and entities",
+			src: `<?xml version="1.0"?><!DOCTYPE svg [<!ENTITY xxe SYSTEM "file:///etc/passwd"><!ENTITY lol "lol">]>` +
+				open + `<text>&xxe;&lol;</text></svg>`,
+			drop: []string{"DOCTYPE", "ENTITY", "passwd", "lollol"},
+			keep: []string{`<?xml version="1.0" encoding="UTF-8"?>`, "<text>"},
+		},
+		{
+			name: "unsafe style",
+			src:  open + `<style>@import url(https://evil.example/x.css);</style><rect style="fill: url(javascript:alert(1))"/></svg>`,
+			drop: []string{"@import", "javascript"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := SanitizeSVG([]byte(tt.src))
+			if err != nil {
+				t.Fatalf("SanitizeSVG: %v", err)
+			}
+			got := string(out)
+			for _, s := range tt.drop {
+				if strings.Contains(got, s) {
+					t.Errorf("output still contains %q:\n%s", s, got)
+				}
+			}
+			for _, s := range tt.keep {
+				if !strings.Contains(got, s) {
+					t.Errorf("output is missing %q:\n%s", s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSanitizeSVGRejectsNonSVG(t *testing.T) {
+	for _, src := range []string{
+		`<html><script>alert(1)</script></html>`,
+		`not xml at all`,
+		`<?xml version="1.0"?>`,
+	} {
+		if _, err := SanitizeSVG([]byte(src)); err == nil {
+			t.Errorf("SanitizeSVG(%q) succeeded, want error", src)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Package webhook mengirim event dari outbox webhook_deliveries ke URL
+// webhook yang terdaftar.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"cms/server/internal/model"
+	"cms/server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	HeaderEvent     = "X-CMS-Event"
+	HeaderDelivery  = "X-CMS-Delivery"
+	HeaderTimestamp = "X-CMS-Timestamp"
+	HeaderSignature = "X-CMS-Signature"
+
+	MaxAttempts = 8
+
+	batchSize       = 20
+	lease           = time.Minute
+	maxResponseBody = 2 << 10
+)
+
+// Sign menghasilkan signature HMAC-SHA256 dari "<timestamp>.<body>".
+// Penerima memverifikasi dengan menghitung ulang dan membandingkan header
+// X-CMS-Signature ("sha256=<hex>").
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff mengembalikan jeda sebelum percobaan berikutnya: 30 detik lalu
+// berlipat dua setiap kali gagal, maksimal 6 jam.
+func Backoff(attempts int) time.Duration {
+	d := 30 * time.Second
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= 6*time.Hour {
+			return 6 * time.Hour
+		}
+	}
+	return d
+}
+
+type Worker struct {
+	repo     repository.WebhookRepository
+	client   *http.Client
+	interval time.Duration
+}
+
+func NewWorker(repo repository.WebhookRepository) *Worker {
+	return &Worker{
+		repo:     repo,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		interval: 2 * time.Second,
+	}
+}
+
+// Run memproses outbox sampai ctx dibatalkan.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		for {
+			// batch yang sudah diambil diselesaikan walau ctx dibatalkan
+			n, err := w.processBatch(context.WithoutCancel(ctx))
+			if err != nil {
+				slog.ErrorContext(ctx, "webhook worker", "error", err)
+			}
+			if err != nil || n < batchSize || ctx.Err() != nil {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) processBatch(ctx context.Context) (int, error) {
+	items, err := w.repo.ClaimDue(ctx, batchSize, lease)
+	if err != nil {
+		return 0, err
+	}
+	hooks := map[uuid.UUID]*model.Webhook{}
+	for i := range items {
+		d := &items[i]
+		wh, ok := hooks[d.WebhookID]
+		if !ok {
+			if wh, err = w.repo.Get(ctx, d.WebhookID); err != nil {
+				slog.ErrorContext(ctx, "webhook worker: load webhook", "webhook_id", d.WebhookID, "error", err)
+				continue
+			}
+			hooks[d.WebhookID] = wh
+		}
+		w.deliver(ctx, wh, d)
+		if err := w.repo.SaveAttempt(ctx, d); err != nil {
+			slog.ErrorContext(ctx, "webhook worker: save delivery", "delivery_id", d.ID, "error", err)
+		}
+	}
+	return len(items), nil
+}
+
+func (w *Worker) deliver(ctx context.Context, wh *model.Webhook, d *model.WebhookDelivery) {
+	now := time.Now()
+	if !wh.Active {
+		d.Status = model.DeliveryFailed
+		d.LastError = "webhook is disabled"
+		return
+	}
+	d.Attempts++
+	d.LastAttemptAt = &now
+
+	code, body, err := w.send(ctx, wh, d)
+	d.DurationMs = time.Since(now).Milliseconds()
+	d.LastStatusCode = code
+	d.LastResponse = body
+	d.LastError = ""
+
+	if err == nil && code >= 200 && code < 300 {
+		d.Status = model.DeliverySucceeded
+		d.DeliveredAt = &now
+		return
+	}
+	if err != nil {
+		d.LastError = err.Error()
+	} else {
+		d.LastError = "unexpected status " + strconv.Itoa(code)
+	}
+	if d.Attempts >= MaxAttempts {
+		d.Status = model.DeliveryFailed
+		return
+	}
+	d.NextAttemptAt = now.Add(Backoff(d.Attempts))
+}
+
+func (w *Worker) send(ctx context.Context, wh *model.Webhook, d *model.WebhookDelivery) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "cms-webhook/1")
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderDelivery, d.ID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, Sign(wh.Secret, ts, d.Payload))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	return resp.StatusCode, string(body), nil
+}
@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"cms/server/internal/model"
+
+	"github.com/google/uuid"
+)
+
+func TestSign(t *testing.T) {
+	// dihitung terpisah: HMAC-SHA256("whsec_test", "1700000000." + body)
+	const want = "sha256=f6faa9df003917740e5ff5de9523f8416faa96446137e1d51452d516c660c7e4"
+	got := Sign("whsec_test", 1700000000, []byte(`{"event":"entry.published"}`))
+	if got != want {
+		t.Errorf("Sign = %s, want %s", got, want)
+	}
+	if Sign("whsec_test", 1700000001, []byte(`{"event":"entry.published"}`)) == want {
+		t.Error("signature does not depend on the timestamp")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{MaxAttempts, 64 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, 6 * time.Hour},
+		{100, 6 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if r.Header.Get(HeaderSignature) != Sign("secret", ts, body) {
+			t.Errorf("signature header does not match the body")
+		}
+		if r.Header.Get(HeaderEvent) != "entry.published" {
+			t.Errorf("%s = %q", HeaderEvent, r.Header.Get(HeaderEvent))
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	w := NewWorker(nil)
+	wh := &model.Webhook{ID: uuid.New(), URL: srv.URL, Secret: "secret", Active: true}
+	newDelivery := func(attempts int) *model.WebhookDelivery {
+		return &model.WebhookDelivery{
+			ID: uuid.New(), WebhookID: wh.ID, Event: "entry.published", Status: model.DeliveryPending,
+			Payload: []byte(`{"id":"1"}`), Attempts: attempts,
+		}
+	}
+
+	d := newDelivery(0)
+	w.deliver(context.Background(), wh, d)
+	if d.Status != model.DeliverySucceeded || d.Attempts != 1 || d.DeliveredAt == nil {
+		t.Errorf("success: status %s, attempts %d", d.Status, d.Attempts)
+	}
+
+	status = http.StatusInternalServerError
+	d = newDelivery(0)
+	before := time.Now()
+	w.deliver(context.Background(), wh, d)
+	if d.Status != model.DeliveryPending || d.LastStatusCode != http.StatusInternalServerError {
+		t.Errorf("first failure: status %s, code %d; want pending retry", d.Status, d.LastStatusCode)
+	}
+	if wait := d.NextAttemptAt.Sub(before); wait < 30*time.Second || wait > 31*time.Second {
+		t.Errorf("first failure: next attempt in %v, want 30s", wait)
+	}
+
+	d = newDelivery(MaxAttempts - 1)
+	w.deliver(context.Background(), wh, d)
+	if d.Status != model.DeliveryFailed || d.Attempts != MaxAttempts {
+		t.Errorf("last attempt: status %s, attempts %d; want failed after %d", d.Status, d.Attempts, MaxAttempts)
+	}
+
+	d = newDelivery(0)
+	w.deliver(context.Background(), &model.Webhook{ID: wh.ID, URL: srv.URL, Active: false}, d)
+	if d.Status != model.DeliveryFailed || d.Attempts != 0 {
+		t.Errorf("disabled webhook: status %s, attempts %d; want failed without sending", d.Status, d.Attempts)
+	}
+}
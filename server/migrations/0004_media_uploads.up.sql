@@ -0,0 +1,18 @@
+-- Sesi upload media besar (multipart / presigned)
+CREATE TABLE IF NOT EXISTS media_uploads (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  method TEXT NOT NULL, -- multipart|presigned
+  object_name TEXT NOT NULL,
+  upload_id TEXT NOT NULL DEFAULT '',
+  filename TEXT NOT NULL,
+  mime TEXT NOT NULL,
+  size_bytes BIGINT NOT NULL,
+  part_size BIGINT NOT NULL DEFAULT 0,
+  status TEXT NOT NULL DEFAULT 'pending', -- pending|completed|aborted
+  asset_id UUID REFERENCES media_assets(id) ON DELETE SET NULL,
+  created_by UUID REFERENCES users(id),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_uploads_status ON media_uploads(status, expires_at);
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_media_assets_created;
+DROP INDEX IF EXISTS idx_media_assets_tags;
+DROP INDEX IF EXISTS idx_media_assets_folder;
+
+ALTER TABLE media_assets
+  DROP COLUMN IF EXISTS credit,
+  DROP COLUMN IF EXISTS caption,
+  DROP COLUMN IF EXISTS alt_text,
+  DROP COLUMN IF EXISTS tags,
+  DROP COLUMN IF EXISTS folder_id;
+
+DROP TABLE IF EXISTS media_folders;
@@ -0,0 +1,24 @@
+-- Folder & metadata editorial untuk media library
+CREATE TABLE IF NOT EXISTS media_folders (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  name TEXT NOT NULL,
+  parent_id UUID REFERENCES media_folders(id),
+  created_by UUID REFERENCES users(id),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- nama folder unik di dalam parent yang sama (root = parent NULL)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_media_folders_name
+  ON media_folders (COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'::uuid), lower(name));
+
+ALTER TABLE media_assets
+  ADD COLUMN IF NOT EXISTS folder_id UUID REFERENCES media_folders(id) ON DELETE SET NULL,
+  ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}',
+  ADD COLUMN IF NOT EXISTS alt_text TEXT NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS caption TEXT NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS credit TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_media_assets_folder ON media_assets(folder_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_media_assets_tags ON media_assets USING GIN (tags);
+CREATE INDEX IF NOT EXISTS idx_media_assets_created ON media_assets(created_at DESC);
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS trg_entry_versions_media_usage ON entry_versions;
+DROP TRIGGER IF EXISTS trg_entries_media_usage ON entries;
+DROP FUNCTION IF EXISTS rebuild_media_usages();
+DROP FUNCTION IF EXISTS entry_versions_media_usage();
+DROP FUNCTION IF EXISTS entries_media_usage();
+DROP FUNCTION IF EXISTS media_refs(UUID, JSONB);
+DROP TABLE IF EXISTS media_usages;
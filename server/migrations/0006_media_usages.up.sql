@@ -0,0 +1,87 @@
+-- Rujukan media dari entry (version = 0 berarti data entry saat ini) dan
+-- dari setiap versi entry. Diisi otomatis oleh trigger di bawah sehingga
+-- semua jalur tulis (API, CSV import, import arsip) ikut tercatat.
+CREATE TABLE IF NOT EXISTS media_usages (
+  media_id UUID NOT NULL REFERENCES media_assets(id) ON DELETE CASCADE,
+  entry_id UUID NOT NULL REFERENCES entries(id) ON DELETE CASCADE,
+  version INT NOT NULL DEFAULT 0,
+  field TEXT NOT NULL,
+  PRIMARY KEY (media_id, entry_id, version, field)
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_usages_entry ON media_usages(entry_id, version);
+
+-- media_refs mengembalikan media yang dirujuk field berjenis image pada doc.
+-- Nilai field boleh berupa id, objek {"id": ...} atau array keduanya.
+CREATE OR REPLACE FUNCTION media_refs(ct UUID, doc JSONB)
+RETURNS TABLE (media_id UUID, field TEXT) AS $$
+  SELECT DISTINCT m.id, f.name
+  FROM content_fields f
+  CROSS JOIN LATERAL jsonb_array_elements(
+    CASE jsonb_typeof(doc -> f.name)
+      WHEN 'array' THEN doc -> f.name
+      WHEN 'string' THEN jsonb_build_array(doc -> f.name)
+      WHEN 'object' THEN jsonb_build_array(doc -> f.name)
+      ELSE '[]'::jsonb
+    END
+  ) AS elem
+  JOIN media_assets m ON m.id::text = lower(
+    CASE jsonb_typeof(elem)
+      WHEN 'string' THEN elem #>> '{}'
+      WHEN 'object' THEN elem ->> 'id'
+    END
+  )
+  WHERE f.content_type_id = ct AND f.kind = 'image'
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION entries_media_usage() RETURNS trigger AS $$
+BEGIN
+  DELETE FROM media_usages WHERE entry_id = NEW.id AND version = 0;
+  INSERT INTO media_usages (media_id, entry_id, version, field)
+  SELECT r.media_id, NEW.id, 0, r.field FROM media_refs(NEW.content_type_id, NEW.data) r;
+  RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION entry_versions_media_usage() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP IN ('UPDATE', 'DELETE') THEN
+    DELETE FROM media_usages WHERE entry_id = OLD.entry_id AND version = OLD.version;
+  END IF;
+  IF TG_OP = 'DELETE' THEN
+    RETURN OLD;
+  END IF;
+  INSERT INTO media_usages (media_id, entry_id, version, field)
+  SELECT r.media_id, NEW.entry_id, NEW.version, r.field
+  FROM entries e, media_refs(e.content_type_id, NEW.data) r
+  WHERE e.id = NEW.entry_id
+  ON CONFLICT DO NOTHING;
+  RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_entries_media_usage ON entries;
+CREATE TRIGGER trg_entries_media_usage
+  AFTER INSERT OR UPDATE OF data, content_type_id ON entries
+  FOR EACH ROW EXECUTE FUNCTION entries_media_usage();
+
+DROP TRIGGER IF EXISTS trg_entry_versions_media_usage ON entry_versions;
+CREATE TRIGGER trg_entry_versions_media_usage
+  AFTER INSERT OR UPDATE OF data OR DELETE ON entry_versions
+  FOR EACH ROW EXECUTE FUNCTION entry_versions_media_usage();
+
+-- rebuild_media_usages menghitung ulang semua rujukan, mis. setelah field
+-- image ditambahkan ke content type yang sudah punya entry.
+CREATE OR REPLACE FUNCTION rebuild_media_usages() RETURNS void AS $$
+  DELETE FROM media_usages;
+  INSERT INTO media_usages (media_id, entry_id, version, field)
+  SELECT r.media_id, e.id, 0, r.field
+  FROM entries e, media_refs(e.content_type_id, e.data) r
+  UNION
+  SELECT r.media_id, v.entry_id, v.version, r.field
+  FROM entry_versions v
+  JOIN entries e ON e.id = v.entry_id,
+  media_refs(e.content_type_id, v.data) r;
+$$ LANGUAGE sql;
+
+SELECT rebuild_media_usages();
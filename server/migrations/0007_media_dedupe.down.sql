@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS trg_media_objects_ref ON media_assets;
+DROP FUNCTION IF EXISTS media_objects_ref();
+DROP TABLE IF EXISTS media_objects;
+DROP INDEX IF EXISTS idx_media_assets_checksum;
+ALTER TABLE media_assets DROP COLUMN IF EXISTS checksum;
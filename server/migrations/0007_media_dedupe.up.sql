@@ -0,0 +1,46 @@
+-- SHA-256 (hex) dari isi file. Kosong untuk media lama sampai diisi oleh
+-- `cms media-verify -backfill`.
+ALTER TABLE media_assets ADD COLUMN IF NOT EXISTS checksum TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_media_assets_checksum ON media_assets(checksum) WHERE checksum <> '';
+
+-- Satu baris per object di bucket; ref_count = jumlah media_assets yang
+-- memakai object tersebut. Dijaga oleh trigger sehingga semua jalur tulis
+-- (upload, import arsip) ikut terhitung.
+CREATE TABLE IF NOT EXISTS media_objects (
+  object_name TEXT PRIMARY KEY,
+  checksum TEXT NOT NULL DEFAULT '',
+  size_bytes BIGINT NOT NULL DEFAULT 0,
+  ref_count INT NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_objects_checksum ON media_objects(checksum) WHERE checksum <> '';
+
+CREATE OR REPLACE FUNCTION media_objects_ref() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP IN ('UPDATE', 'DELETE') THEN
+    UPDATE media_objects SET ref_count = ref_count - 1 WHERE object_name = OLD.url;
+    DELETE FROM media_objects WHERE object_name = OLD.url AND ref_count <= 0;
+  END IF;
+  IF TG_OP IN ('INSERT', 'UPDATE') THEN
+    INSERT INTO media_objects (object_name, checksum, size_bytes, ref_count)
+    VALUES (NEW.url, NEW.checksum, NEW.size_bytes, 1)
+    ON CONFLICT (object_name) DO UPDATE SET
+      ref_count = media_objects.ref_count + 1,
+      checksum = CASE WHEN media_objects.checksum = '' THEN EXCLUDED.checksum ELSE media_objects.checksum END;
+    RETURN NEW;
+  END IF;
+  RETURN OLD;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_media_objects_ref ON media_assets;
+CREATE TRIGGER trg_media_objects_ref
+  AFTER INSERT OR DELETE OR UPDATE OF url, checksum ON media_assets
+  FOR EACH ROW EXECUTE FUNCTION media_objects_ref();
+
+INSERT INTO media_objects (object_name, checksum, size_bytes, ref_count, created_at)
+SELECT url, max(checksum), max(size_bytes), count(*), min(created_at)
+FROM media_assets
+GROUP BY url
+ON CONFLICT (object_name) DO NOTHING;
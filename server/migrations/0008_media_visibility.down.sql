@@ -0,0 +1 @@
+ALTER TABLE media_assets DROP COLUMN IF EXISTS visibility;
@@ -0,0 +1,4 @@
+-- auto: publik selama dirujuk entry yang sudah publish; public: selalu;
+-- private: tidak pernah (hanya lewat API yang butuh login).
+ALTER TABLE media_assets ADD COLUMN IF NOT EXISTS visibility TEXT NOT NULL DEFAULT 'auto'
+  CHECK (visibility IN ('auto', 'public', 'private'));
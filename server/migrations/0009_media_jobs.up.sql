@@ -0,0 +1,21 @@
+-- Antrian pemrosesan media non-gambar (video, audio, PDF): metadata &
+-- poster diekstrak worker di background setelah upload.
+CREATE TABLE IF NOT EXISTS media_jobs (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  media_id UUID NOT NULL REFERENCES media_assets(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL DEFAULT 'metadata',
+  status TEXT NOT NULL DEFAULT 'pending', -- pending|running|completed|failed
+  attempts INT NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT '',
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  finished_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_media_jobs_media ON media_jobs(media_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_media_jobs_due ON media_jobs(next_attempt_at) WHERE status IN ('pending', 'running');
+
+-- media yang sudah ada ikut diproses
+INSERT INTO media_jobs (media_id)
+SELECT id FROM media_assets
+WHERE mime LIKE 'video/%' OR mime LIKE 'audio/%' OR mime = 'application/pdf';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_audit_logs_actor;
+DROP INDEX IF EXISTS idx_media_assets_created_id;
+CREATE INDEX IF NOT EXISTS idx_media_assets_created ON media_assets(created_at DESC);
+DROP INDEX IF EXISTS idx_entries_ct_published;
+DROP INDEX IF EXISTS idx_entries_ct_created;
@@ -0,0 +1,7 @@
+-- Index untuk pagination cursor (urutan kolom waktu lalu id).
+CREATE INDEX IF NOT EXISTS idx_entries_ct_created ON entries(content_type_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_entries_ct_published ON entries(content_type_id, published_at DESC, id DESC)
+  WHERE status = 'published';
+DROP INDEX IF EXISTS idx_media_assets_created;
+CREATE INDEX IF NOT EXISTS idx_media_assets_created_id ON media_assets(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor ON audit_logs(actor_id, id DESC);
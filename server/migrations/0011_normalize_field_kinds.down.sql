@@ -0,0 +1,2 @@
+-- Nama kind lama tidak bisa dibedakan lagi; tidak ada yang dikembalikan.
+SELECT 1;
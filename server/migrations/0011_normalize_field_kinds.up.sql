@@ -0,0 +1,3 @@
+-- Kind dari admin UI disimpan dengan nama kanonik server.
+UPDATE content_fields SET kind = 'bool' WHERE kind = 'boolean';
+UPDATE content_fields SET kind = 'image' WHERE kind = 'media';
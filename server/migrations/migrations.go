@@ -0,0 +1,8 @@
+// Package migrations menyimpan migrasi SQL berversi yang di-embed ke binary.
+// Nama file mengikuti pola NNNN_nama.up.sql / NNNN_nama.down.sql.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
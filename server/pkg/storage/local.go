@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// file sementara saat Put; diabaikan oleh List
+const localTempPrefix = ".tmp-"
+
+// Local menyimpan object sebagai file biasa di bawah satu direktori root.
+type Local struct {
+	emulatedMultipart
+	root   string
+	signer *URLSigner
+}
+
+func NewLocal(root string, signer *URLSigner) (*Local, error) {
+	if root == "" {
+		return nil, errors.New("local storage: directory is required")
+	}
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("local storage: %w", err)
+	}
+	if err := os.MkdirAll(abs, 0o755); err != nil {
+		return nil, fmt.Errorf("local storage: %w", err)
+	}
+	l := &Local{root: abs, signer: signer}
+	l.emulatedMultipart = emulatedMultipart{base: l}
+	return l, nil
+}
+
+// Signer mengembalikan penanda tangan URL yang dipakai PresignGet.
+func (l *Local) Signer() *URLSigner { return l.signer }
+
+func (l *Local) path(name string) (string, error) {
+	if _, err := cleanName(name); err != nil {
+		return "", err
+	}
+	return filepath.Join(l.root, filepath.FromSlash(name)), nil
+}
+
+func notFound(name string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return err
+}
+
+func (l *Local) Ping(context.Context) error {
+	st, err := os.Stat(l.root)
+	if err != nil {
+		return fmt.Errorf("local storage: %w", err)
+	}
+	if !st.IsDir() {
+		return fmt.Errorf("local storage: %s is not a directory", l.root)
+	}
+	return nil
+}
+
+// Put menulis ke file sementara lalu rename sehingga pembaca tidak pernah
+// melihat file yang setengah jadi.
+func (l *Local) Put(ctx context.Context, name, contentType string, r io.Reader, size int64) error {
+	p, err := l.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), localTempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	n, err := io.Copy(tmp, r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if size >= 0 && n != size {
+		return fmt.Errorf("put %s: wrote %d bytes, expected %d", name, n, size)
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (l *Local) Open(ctx context.Context, name string) (io.ReadSeekCloser, int64, error) {
+	p, err := l.path(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, 0, notFound(name, err)
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	if st.IsDir() {
+		f.Close()
+		return nil, 0, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return f, st.Size(), nil
+}
+
+func (l *Local) info(name string, st fs.FileInfo) *ObjectInfo {
+	ct := mime.TypeByExtension(path.Ext(name))
+	if ct == "" {
+		ct = "application/octet-stream"
+	}
+	return &ObjectInfo{
+		Name:         name,
+		Size:         st.Size(),
+		ContentType:  ct,
+		ETag:         fmt.Sprintf("%x-%x", st.ModTime().UnixNano(), st.Size()),
+		LastModified: st.ModTime(),
+	}
+}
+
+func (l *Local) Stat(ctx context.Context, name string) (*ObjectInfo, error) {
+	p, err := l.path(name)
+	if err != nil {
+		return nil, err
+	}
+	st, err := os.Stat(p)
+	if err != nil {
+		return nil, notFound(name, err)
+	}
+	if st.IsDir() {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return l.info(name, st), nil
+}
+
+func (l *Local) Delete(ctx context.Context, name string) error {
+	p, err := l.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil {
+		return notFound(name, err)
+	}
+	// direktori yang kosong ikut dibersihkan; gagal berarti masih berisi
+	for dir := filepath.Dir(p); dir != l.root && strings.HasPrefix(dir, l.root); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+func (l *Local) Copy(ctx context.Context, src, dst string) error {
+	f, size, err := l.Open(ctx, src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	st, err := l.Stat(ctx, src)
+	if err != nil {
+		return err
+	}
+	return l.Put(ctx, dst, st.ContentType, f, size)
+}
+
+func (l *Local) List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	// mulai dari direktori terdalam yang pasti memuat prefix
+	start := l.root
+	if i := strings.LastIndex(prefix, "/"); i > 0 {
+		start = filepath.Join(l.root, filepath.FromSlash(prefix[:i]))
+	}
+	err := filepath.WalkDir(start, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), localTempPrefix) {
+			return nil
+		}
+		rel, err := filepath.Rel(l.root, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if !strings.HasPrefix(name, prefix) || hiddenFromList(name, prefix) {
+			return nil
+		}
+		st, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(*l.info(name, st))
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (l *Local) PresignGet(ctx context.Context, name string, expiry time.Duration) (string, error) {
+	if _, err := l.Stat(ctx, name); err != nil {
+		return "", err
+	}
+	return l.signer.Sign(name, expiry), nil
+}
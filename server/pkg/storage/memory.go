@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Memory menyimpan object di memori proses. Cocok untuk development dan
+// test; isinya hilang saat proses berhenti.
+type Memory struct {
+	emulatedMultipart
+	signer *URLSigner
+
+	mu      sync.RWMutex
+	objects map[string]memObject
+}
+
+type memObject struct {
+	data        []byte
+	contentType string
+	etag        string
+	modified    time.Time
+}
+
+func NewMemory(signer *URLSigner) *Memory {
+	m := &Memory{signer: signer, objects: map[string]memObject{}}
+	m.emulatedMultipart = emulatedMultipart{base: m}
+	return m
+}
+
+func (m *Memory) Ping(context.Context) error { return nil }
+
+// Signer mengembalikan penanda tangan URL yang dipakai PresignGet.
+func (m *Memory) Signer() *URLSigner { return m.signer }
+
+func (m *Memory) Put(ctx context.Context, name, contentType string, r io.Reader, size int64) error {
+	if _, err := cleanName(name); err != nil {
+		return err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if size >= 0 && int64(len(data)) != size {
+		return fmt.Errorf("put %s: read %d bytes, expected %d", name, len(data), size)
+	}
+	sum := md5.Sum(data)
+	m.mu.Lock()
+	m.objects[name] = memObject{data: data, contentType: contentType, etag: hex.EncodeToString(sum[:]), modified: time.Now()}
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *Memory) get(name string) (memObject, error) {
+	m.mu.RLock()
+	obj, ok := m.objects[name]
+	m.mu.RUnlock()
+	if !ok {
+		return memObject{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return obj, nil
+}
+
+type nopSeekCloser struct{ *bytes.Reader }
+
+func (nopSeekCloser) Close() error { return nil }
+
+func (m *Memory) Open(ctx context.Context, name string) (io.ReadSeekCloser, int64, error) {
+	obj, err := m.get(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	return nopSeekCloser{bytes.NewReader(obj.data)}, int64(len(obj.data)), nil
+}
+
+func (m *Memory) Stat(ctx context.Context, name string) (*ObjectInfo, error) {
+	obj, err := m.get(name)
+	if err != nil {
+		return nil, err
+	}
+	return &ObjectInfo{Name: name, Size: int64(len(obj.data)), ContentType: obj.contentType, ETag: obj.etag, LastModified: obj.modified}, nil
+}
+
+func (m *Memory) Delete(ctx context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.objects[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	delete(m.objects, name)
+	return nil
+}
+
+func (m *Memory) Copy(ctx context.Context, src, dst string) error {
+	if _, err := cleanName(dst); err != nil {
+		return err
+	}
+	obj, err := m.get(src)
+	if err != nil {
+		return err
+	}
+	obj.modified = time.Now()
+	m.mu.Lock()
+	m.objects[dst] = obj
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *Memory) List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	m.mu.RLock()
+	var infos []ObjectInfo
+	for name, obj := range m.objects {
+		if strings.HasPrefix(name, prefix) && !hiddenFromList(name, prefix) {
+			infos = append(infos, ObjectInfo{Name: name, Size: int64(len(obj.data)), ContentType: obj.contentType, ETag: obj.etag, LastModified: obj.modified})
+		}
+	}
+	m.mu.RUnlock()
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	for _, info := range infos {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Memory) PresignGet(ctx context.Context, name string, expiry time.Duration) (string, error) {
+	if _, err := m.get(name); err != nil {
+		return "", err
+	}
+	return m.signer.Sign(name, expiry), nil
+}
@@ -0,0 +1,255 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// MinIO menyimpan object di bucket MinIO atau S3.
+type MinIO struct {
+	client *minio.Client
+	bucket string
+	region string
+	// ready menandai bucket sudah dipastikan ada
+	ready atomic.Bool
+}
+
+// NewMinIO membuat client tanpa menghubungi server; bucket dibuat saat
+// pertama kali dibutuhkan sehingga API tetap bisa start walau MinIO mati.
+// Setiap request ke server menjadi span tracing "minio <METHOD>" dan
+// dilaporkan ke cfg.Observe.
+func NewMinIO(cfg Config) (*MinIO, error) {
+	base, err := minio.DefaultTransport(cfg.UseSSL)
+	if err != nil {
+		return nil, fmt.Errorf("minio: %w", err)
+	}
+	var transport http.RoundTripper = base
+	if cfg.Observe != nil {
+		transport = observedTransport{next: base, observe: cfg.Observe}
+	}
+	m, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+		Transport: otelhttp.NewTransport(transport, otelhttp.WithSpanNameFormatter(
+			func(_ string, r *http.Request) string { return "minio " + r.Method },
+		)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("minio: %w", err)
+	}
+	return &MinIO{client: m, bucket: cfg.Bucket, region: cfg.Region}, nil
+}
+
+// Ping memastikan MinIO bisa dihubungi dan membuat bucket bila belum ada.
+func (s *MinIO) Ping(ctx context.Context) error {
+	if !s.ready.Load() {
+		return s.ensureBucket(ctx)
+	}
+	// bucket sudah dipastikan ada; cukup cek server masih menjawab
+	if _, err := s.client.BucketExists(ctx, s.bucket); err != nil {
+		return fmt.Errorf("minio: check bucket %s: %w", s.bucket, err)
+	}
+	return nil
+}
+
+func (s *MinIO) ensureBucket(ctx context.Context) error {
+	if s.ready.Load() {
+		return nil
+	}
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		return fmt.Errorf("minio: check bucket %s: %w", s.bucket, err)
+	}
+	if !exists {
+		if err := s.client.MakeBucket(ctx, s.bucket, minio.MakeBucketOptions{Region: s.region}); err != nil {
+			return fmt.Errorf("minio: create bucket %s: %w", s.bucket, err)
+		}
+		slog.InfoContext(ctx, "bucket dibuat", "bucket", s.bucket)
+	}
+	s.ready.Store(true)
+	return nil
+}
+
+// observedTransport melaporkan durasi setiap request ke server. Response
+// 4xx (mis. object tidak ada) bukan error storage.
+type observedTransport struct {
+	next    http.RoundTripper
+	observe func(method string, d time.Duration, err error)
+}
+
+func (t observedTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := t.next.RoundTrip(r)
+	observed := err
+	if err == nil && res.StatusCode >= http.StatusInternalServerError {
+		observed = fmt.Errorf("status %d", res.StatusCode)
+	}
+	t.observe(r.Method, time.Since(start), observed)
+	return res, err
+}
+
+// minioErr menerjemahkan error "tidak ada" dari S3 menjadi ErrNotFound.
+func minioErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch minio.ToErrorResponse(err).Code {
+	case "NoSuchKey", "NotFound", "NoSuchUpload":
+		return fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	return err
+}
+
+func (s *MinIO) Put(ctx context.Context, name, contentType string, r io.Reader, size int64) error {
+	if err := s.ensureBucket(ctx); err != nil {
+		return err
+	}
+	_, err := s.client.PutObject(ctx, s.bucket, name, r, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	return err
+}
+
+func (s *MinIO) Open(ctx context.Context, name string) (io.ReadSeekCloser, int64, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, 0, minioErr(err)
+	}
+	info, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		return nil, 0, minioErr(err)
+	}
+	return obj, info.Size, nil
+}
+
+func (s *MinIO) Stat(ctx context.Context, name string) (*ObjectInfo, error) {
+	info, err := s.client.StatObject(ctx, s.bucket, name, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, minioErr(err)
+	}
+	return &ObjectInfo{Name: info.Key, Size: info.Size, ContentType: info.ContentType, ETag: info.ETag, LastModified: info.LastModified}, nil
+}
+
+func (s *MinIO) Delete(ctx context.Context, name string) error {
+	return minioErr(s.client.RemoveObject(ctx, s.bucket, name, minio.RemoveObjectOptions{}))
+}
+
+// Copy menyalin object di sisi server tanpa melewati aplikasi.
+func (s *MinIO) Copy(ctx context.Context, src, dst string) error {
+	_, err := s.client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: s.bucket, Object: dst},
+		minio.CopySrcOptions{Bucket: s.bucket, Object: src})
+	return minioErr(err)
+}
+
+func (s *MinIO) List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return obj.Err
+		}
+		if err := fn(ObjectInfo{Name: obj.Key, Size: obj.Size, ETag: obj.ETag, LastModified: obj.LastModified}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *MinIO) PresignGet(ctx context.Context, name string, expiry time.Duration) (string, error) {
+	reqParams := make(url.Values) // bisa diisi content-disposition atau lainnya
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, name, expiry, reqParams)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// NewMultipartUpload memulai multipart upload S3 dan mengembalikan upload id.
+func (s *MinIO) NewMultipartUpload(ctx context.Context, name, contentType string) (string, error) {
+	if err := s.ensureBucket(ctx); err != nil {
+		return "", err
+	}
+	core := minio.Core{Client: s.client}
+	return core.NewMultipartUpload(ctx, s.bucket, name, minio.PutObjectOptions{ContentType: contentType})
+}
+
+func (s *MinIO) PutPart(ctx context.Context, name, uploadID string, number int, r io.Reader, size int64) (*Part, error) {
+	core := minio.Core{Client: s.client}
+	p, err := core.PutObjectPart(ctx, s.bucket, name, uploadID, number, r, size, minio.PutObjectPartOptions{})
+	if err != nil {
+		return nil, minioErr(err)
+	}
+	return &Part{Number: p.PartNumber, Size: p.Size, ETag: p.ETag}, nil
+}
+
+func (s *MinIO) ListParts(ctx context.Context, name, uploadID string) ([]Part, error) {
+	core := minio.Core{Client: s.client}
+	var parts []Part
+	marker := 0
+	for {
+		res, err := core.ListObjectParts(ctx, s.bucket, name, uploadID, marker, 1000)
+		if err != nil {
+			return nil, minioErr(err)
+		}
+		for _, p := range res.ObjectParts {
+			parts = append(parts, Part{Number: p.PartNumber, Size: p.Size, ETag: p.ETag})
+		}
+		if !res.IsTruncated {
+			return parts, nil
+		}
+		marker = res.NextPartNumberMarker
+	}
+}
+
+func (s *MinIO) CompleteMultipartUpload(ctx context.Context, name, uploadID string, parts []Part) error {
+	core := minio.Core{Client: s.client}
+	complete := make([]minio.CompletePart, len(parts))
+	for i, p := range parts {
+		complete[i] = minio.CompletePart{PartNumber: p.Number, ETag: p.ETag}
+	}
+	_, err := core.CompleteMultipartUpload(ctx, s.bucket, name, uploadID, complete, minio.PutObjectOptions{})
+	return minioErr(err)
+}
+
+func (s *MinIO) AbortMultipartUpload(ctx context.Context, name, uploadID string) error {
+	core := minio.Core{Client: s.client}
+	return minioErr(core.AbortMultipartUpload(ctx, s.bucket, name, uploadID))
+}
+
+func (s *MinIO) PresignedPost(ctx context.Context, name, contentType string, maxSize int64, expiry time.Duration) (string, map[string]string, error) {
+	if err := s.ensureBucket(ctx); err != nil {
+		return "", nil, err
+	}
+	policy := minio.NewPostPolicy()
+	if err := policy.SetBucket(s.bucket); err != nil {
+		return "", nil, err
+	}
+	if err := policy.SetKey(name); err != nil {
+		return "", nil, err
+	}
+	if err := policy.SetContentType(contentType); err != nil {
+		return "", nil, err
+	}
+	if err := policy.SetContentLengthRange(1, maxSize); err != nil {
+		return "", nil, err
+	}
+	if err := policy.SetExpires(time.Now().UTC().Add(expiry)); err != nil {
+		return "", nil, err
+	}
+	u, fields, err := s.client.PresignedPostPolicy(ctx, policy)
+	if err != nil {
+		return "", nil, err
+	}
+	return u.String(), fields, nil
+}
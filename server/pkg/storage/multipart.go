@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// multipartPrefix menampung part dari multipart upload yang diemulasikan.
+// Object di bawah prefix ini tidak ikut List kecuali diminta langsung.
+const multipartPrefix = ".multipart/"
+
+// basicStore adalah operasi dasar yang dipakai emulatedMultipart.
+type basicStore interface {
+	Put(ctx context.Context, name, contentType string, r io.Reader, size int64) error
+	Open(ctx context.Context, name string) (io.ReadSeekCloser, int64, error)
+	Stat(ctx context.Context, name string) (*ObjectInfo, error)
+	List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error
+	Delete(ctx context.Context, name string) error
+}
+
+// emulatedMultipart memberi multipart upload ala S3 untuk backend yang tidak
+// memilikinya: setiap part disimpan sebagai object tersendiri lalu
+// digabung saat complete.
+type emulatedMultipart struct {
+	base basicStore
+}
+
+func (m emulatedMultipart) NewMultipartUpload(ctx context.Context, name, contentType string) (string, error) {
+	if _, err := cleanName(name); err != nil {
+		return "", err
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(b)
+	info := name + "\n" + contentType
+	return id, m.base.Put(ctx, m.infoName(id), "text/plain", strings.NewReader(info), int64(len(info)))
+}
+
+func (m emulatedMultipart) PutPart(ctx context.Context, name, uploadID string, number int, r io.Reader, size int64) (*Part, error) {
+	if _, err := m.info(ctx, name, uploadID); err != nil {
+		return nil, err
+	}
+	partName := m.partName(uploadID, number)
+	if err := m.base.Put(ctx, partName, "application/octet-stream", r, size); err != nil {
+		return nil, err
+	}
+	st, err := m.base.Stat(ctx, partName)
+	if err != nil {
+		return nil, err
+	}
+	return &Part{Number: number, Size: st.Size, ETag: st.ETag}, nil
+}
+
+func (m emulatedMultipart) ListParts(ctx context.Context, name, uploadID string) ([]Part, error) {
+	if _, err := m.info(ctx, name, uploadID); err != nil {
+		return nil, err
+	}
+	var parts []Part
+	err := m.base.List(ctx, m.prefix(uploadID), func(o ObjectInfo) error {
+		n, err := strconv.Atoi(strings.TrimPrefix(o.Name, m.prefix(uploadID)))
+		if err != nil {
+			return nil // info
+		}
+		parts = append(parts, Part{Number: n, Size: o.Size, ETag: o.ETag})
+		return nil
+	})
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Number < parts[j].Number })
+	return parts, err
+}
+
+func (m emulatedMultipart) CompleteMultipartUpload(ctx context.Context, name, uploadID string, parts []Part) error {
+	contentType, err := m.info(ctx, name, uploadID)
+	if err != nil {
+		return err
+	}
+	var total int64
+	for _, p := range parts {
+		st, err := m.base.Stat(ctx, m.partName(uploadID, p.Number))
+		if err != nil {
+			return fmt.Errorf("part %d: %w", p.Number, err)
+		}
+		if p.ETag != "" && p.ETag != st.ETag {
+			return fmt.Errorf("part %d: etag mismatch", p.Number)
+		}
+		total += st.Size
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		for _, p := range parts {
+			f, _, err := m.base.Open(ctx, m.partName(uploadID, p.Number))
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			_, err = io.Copy(pw, f)
+			f.Close()
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.Close()
+	}()
+	if err := m.base.Put(ctx, name, contentType, pr, total); err != nil {
+		pr.CloseWithError(err)
+		return err
+	}
+	return deletePrefix(ctx, m.base, m.prefix(uploadID))
+}
+
+func (m emulatedMultipart) AbortMultipartUpload(ctx context.Context, name, uploadID string) error {
+	if _, err := m.info(ctx, name, uploadID); err != nil {
+		return err
+	}
+	return deletePrefix(ctx, m.base, m.prefix(uploadID))
+}
+
+// info membaca nama object & content type sesi; sesi yang tidak ada atau
+// milik object lain dianggap tidak ditemukan.
+func (m emulatedMultipart) info(ctx context.Context, name, uploadID string) (string, error) {
+	if uploadID == "" || strings.ContainsAny(uploadID, "/\\.") {
+		return "", fmt.Errorf("%w: upload %q", ErrNotFound, uploadID)
+	}
+	f, _, err := m.base.Open(ctx, m.infoName(uploadID))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+	objName, contentType, _ := strings.Cut(string(b), "\n")
+	if objName != name {
+		return "", fmt.Errorf("%w: upload %q", ErrNotFound, uploadID)
+	}
+	return contentType, nil
+}
+
+func (m emulatedMultipart) prefix(uploadID string) string {
+	return multipartPrefix + uploadID + "/"
+}
+
+func (m emulatedMultipart) infoName(uploadID string) string {
+	return m.prefix(uploadID) + "info"
+}
+
+func (m emulatedMultipart) partName(uploadID string, number int) string {
+	return fmt.Sprintf("%s%05d", m.prefix(uploadID), number)
+}
+
+// hiddenFromList melaporkan apakah object tidak boleh muncul di List
+// dengan prefix tersebut.
+func hiddenFromList(name, prefix string) bool {
+	return strings.HasPrefix(name, multipartPrefix) && !strings.HasPrefix(prefix, multipartPrefix)
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FilesPath adalah route API yang melayani URL hasil URLSigner.
+const FilesPath = "/api/files/"
+
+// URLSigner membuat URL unduhan sementara untuk backend yang tidak punya
+// presigned URL sendiri (local & memory). URL diarahkan ke route FilesPath
+// di API dan ditandatangani HMAC-SHA256.
+type URLSigner struct {
+	baseURL string
+	key     []byte
+}
+
+func NewURLSigner(baseURL, key string) *URLSigner {
+	return &URLSigner{baseURL: strings.TrimRight(baseURL, "/"), key: []byte(key)}
+}
+
+func (s *URLSigner) Sign(name string, expiry time.Duration) string {
+	exp := strconv.FormatInt(time.Now().Add(expiry).Unix(), 10)
+	q := url.Values{"expires": {exp}, "signature": {s.mac(name, exp)}}
+	return s.baseURL + FilesPath + (&url.URL{Path: name}).EscapedPath() + "?" + q.Encode()
+}
+
+// Verify memeriksa tanda tangan dan masa berlaku URL untuk object name.
+func (s *URLSigner) Verify(name, expires, signature string) bool {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(s.mac(name, expires)))
+}
+
+func (s *URLSigner) mac(name, expires string) string {
+	m := hmac.New(sha256.New, s.key)
+	m.Write([]byte(name + "\n" + expires))
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// SignedURLs diimplementasikan backend yang URL PresignGet-nya harus
+// dilayani API sendiri di FilesPath.
+type SignedURLs interface {
+	Signer() *URLSigner
+}
@@ -0,0 +1,183 @@
+// Package storage menyimpan file media di balik satu interface sehingga
+// backend bisa dipilih lewat konfigurasi: MinIO/S3, filesystem lokal, atau
+// memori (untuk development & test tanpa MinIO).
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	DriverMinIO  = "minio"
+	DriverS3     = "s3"
+	DriverLocal  = "local"
+	DriverMemory = "memory"
+)
+
+var (
+	// ErrNotFound dikembalikan bila object (atau sesi multipart) tidak ada.
+	ErrNotFound = errors.New("object not found")
+	// ErrNotSupported dikembalikan untuk fitur yang tidak dimiliki backend.
+	ErrNotSupported = errors.New("not supported by this storage backend")
+	// ErrInvalidName dikembalikan untuk nama object yang tidak aman.
+	ErrInvalidName = errors.New("invalid object name")
+)
+
+type ObjectInfo struct {
+	Name         string
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+// Part adalah satu bagian multipart upload yang sudah diterima storage.
+type Part struct {
+	Number int
+	Size   int64
+	ETag   string
+}
+
+// Storage adalah operasi yang dibutuhkan CMS dari penyimpanan object.
+// Semua implementasi mengembalikan error yang membungkus ErrNotFound bila
+// object tidak ada.
+type Storage interface {
+	// Put meng-stream isi r ke object. size -1 berarti tidak diketahui.
+	Put(ctx context.Context, name, contentType string, r io.Reader, size int64) error
+	// Open membuka object untuk dibaca secara streaming beserta ukurannya.
+	Open(ctx context.Context, name string) (io.ReadSeekCloser, int64, error)
+	Stat(ctx context.Context, name string) (*ObjectInfo, error)
+	Delete(ctx context.Context, name string) error
+	Copy(ctx context.Context, src, dst string) error
+	// List memanggil fn untuk setiap object yang diawali prefix, urut nama.
+	List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error
+	// PresignGet membuat URL sementara untuk mengunduh object tanpa login.
+	PresignGet(ctx context.Context, name string, expiry time.Duration) (string, error)
+	// Ping memastikan storage bisa dipakai (mis. bucket ada).
+	Ping(ctx context.Context) error
+
+	NewMultipartUpload(ctx context.Context, name, contentType string) (string, error)
+	PutPart(ctx context.Context, name, uploadID string, number int, r io.Reader, size int64) (*Part, error)
+	// ListParts mengembalikan semua part yang sudah diterima, berurutan.
+	ListParts(ctx context.Context, name, uploadID string) ([]Part, error)
+	CompleteMultipartUpload(ctx context.Context, name, uploadID string, parts []Part) error
+	AbortMultipartUpload(ctx context.Context, name, uploadID string) error
+}
+
+// PostPresigner diimplementasikan backend yang mendukung upload langsung
+// dari browser lewat presigned POST (S3/MinIO).
+type PostPresigner interface {
+	// PresignedPost membuat URL + form field untuk upload langsung ke
+	// storage. Ukuran file dibatasi storage lewat policy content-length-range.
+	PresignedPost(ctx context.Context, name, contentType string, maxSize int64, expiry time.Duration) (string, map[string]string, error)
+}
+
+// Config memilih dan mengatur backend storage.
+type Config struct {
+	// Driver: minio (default), s3, local atau memory.
+	Driver string
+
+	// MinIO/S3
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string
+	UseSSL    bool
+
+	// Local: direktori root tempat object disimpan.
+	LocalDir string
+	// Local & memory: URL dasar API (mis. "https://cms.example.com") dan
+	// kunci HMAC untuk URL yang dibuat PresignGet.
+	PublicURL  string
+	SigningKey string
+
+	// Observe (opsional) dipanggil setelah setiap request HTTP ke MinIO/S3
+	// dengan method, durasi, dan error jaringan atau status 5xx.
+	Observe func(method string, d time.Duration, err error)
+}
+
+// New membuat storage sesuai cfg.Driver. Koneksi ke MinIO/S3 tidak dicek di
+// sini; gunakan Ping.
+func New(cfg Config) (Storage, error) {
+	switch strings.ToLower(cfg.Driver) {
+	case "", DriverMinIO, DriverS3:
+		return NewMinIO(cfg)
+	case DriverLocal:
+		return NewLocal(cfg.LocalDir, NewURLSigner(cfg.PublicURL, cfg.SigningKey))
+	case DriverMemory:
+		return NewMemory(NewURLSigner(cfg.PublicURL, cfg.SigningKey)), nil
+	}
+	return nil, fmt.Errorf("unknown storage driver %q (expected minio, s3, local or memory)", cfg.Driver)
+}
+
+// IsNotFound melaporkan apakah err berarti object tidak ada.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// Upload menyimpan data ke object.
+func Upload(ctx context.Context, s Storage, name, contentType string, data []byte) error {
+	return s.Put(ctx, name, contentType, bytes.NewReader(data), int64(len(data)))
+}
+
+// Download membaca seluruh isi object ke memori.
+func Download(ctx context.Context, s Storage, name string) ([]byte, error) {
+	f, _, err := s.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// Exists mengecek apakah object ada.
+func Exists(ctx context.Context, s Storage, name string) (bool, error) {
+	_, err := s.Stat(ctx, name)
+	if IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// DeletePrefix menghapus semua object yang namanya diawali prefix.
+func DeletePrefix(ctx context.Context, s Storage, prefix string) error {
+	return deletePrefix(ctx, s, prefix)
+}
+
+func deletePrefix(ctx context.Context, s basicStore, prefix string) error {
+	var names []string
+	err := s.List(ctx, prefix, func(o ObjectInfo) error {
+		names = append(names, o.Name)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := s.Delete(ctx, name); err != nil && !IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanName menolak nama object yang bisa keluar dari root (local) atau
+// tidak konsisten antar backend.
+func cleanName(name string) (string, error) {
+	if name == "" || strings.HasPrefix(name, "/") || strings.Contains(name, "\\") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	for _, seg := range strings.Split(name, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return "", fmt.Errorf("%w: %q", ErrInvalidName, name)
+		}
+	}
+	return name, nil
+}